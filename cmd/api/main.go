@@ -1,41 +1,192 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"os/signal"
 	"runtime"
+	"strings"
+	"syscall"
+	_ "github.com/vegh1010/test/database/migrations"
+	_ "github.com/vegh1010/test/database/seeds"
+	"github.com/vegh1010/test/pkg/config"
 	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/encryption"
 	"github.com/vegh1010/test/pkg/model/modelinit"
 	"github.com/vegh1010/test/pkg/api/router"
 	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/jobs"
+	"github.com/vegh1010/test/pkg/jobs/jobinit"
 	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
+	// config flags come before the command, test-api -set APP_LOG_LEVEL=debug migrate up
+	co := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	args := flag.Args()
+
+	// config - test-api config print
+	if len(args) > 0 && args[0] == "config" {
+		os.Exit(configCommand(co, args[1:]))
+	}
+
 	// environment
-	e := env.NewEnv()
+	e := env.MustLoad(co)
 
 	// logger
 	l := logger.NewLogger(e)
 
+	// migrations - test-api migrate, see migrate.Usage
+	if len(args) > 0 && args[0] == "migrate" {
+		err := migrate.Main(e, l, args[1:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// schema - checked or migrated before statements are prepared
+	err := migrate.Startup(e, l)
+	if err != nil {
+		panic(fmt.Sprintf("Migration error: %v", err))
+	}
+
 	// database
-	db := db.NewDB(l, e)
+	d := db.NewDB(l, e)
+
+	// secrets - read again on SIGHUP so database credentials can rotate
+	stop := db.WatchSecrets(l, e, d)
+	defer stop()
+
+	// log levels - set again from the configuration on SIGHUP, replacing
+	// levels changed at runtime
+	stopLevels := logger.WatchLevels(l, e)
+	defer stopLevels()
 
 	// prepare model statements.
 	l.Info().Msg("Preparing model statements")
-	modelinit.PrepareStatements(db)
+	modelinit.PrepareStatements(d)
+
+	// reference data
+	l.Info().Msg("Loading reference data")
+	err = modelinit.LoadReferenceData()
+	if err != nil {
+		panic(fmt.Sprintf("Reference data error: %v", err))
+	}
+
+	// job workers - normally run by test-worker but can
+	// also run in process for small deployments
+	var w *jobs.Worker
+	if e.Config.Jobs.Workers > 0 {
+		w, err = jobinit.NewWorker(e, l, d)
+		if err != nil {
+			panic(fmt.Sprintf("Worker error: %v", err))
+		}
+		w.Start()
+	}
 
 	// router
-	r, err := router.NewRouter(e, l, db)
+	r, err := router.NewRouter(e, l, d)
 	if err != nil {
 		panic(fmt.Sprintf("Router error: %v", err))
 	}
 
 	// server
-	sp := e.Get("APP_SERVER_PORT")
-	l.Info().Msgf("Listing on http://0.0.0.0:%s", sp)
+	sp := e.Config.ServerPort
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", sp), Handler: r}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		l.Info().Msgf("Listing on http://0.0.0.0:%d", sp)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	// wait for shutdown
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case s := <-sig:
+		l.Info().Msgf("Received %s, shutting down", s)
+	case err = <-serveErr:
+		l.Error().Msgf("Server error: %v", err)
+	}
+
+	// requests in flight finish before the workers stop, they may enqueue
+	// jobs, then the deferred cleanups run
+	ctx, cancel := context.WithTimeout(context.Background(), e.Config.ShutdownTimeout)
+	defer cancel()
+
+	err = srv.Shutdown(ctx)
+	if err != nil {
+		l.Error().Msgf("Server shutdown error: %v", err)
+	}
+
+	if w != nil {
+		w.Stop()
+	}
+}
+
+// configCommand runs config print, which prints the configuration with
+// secrets redacted followed by every invalid value, or config encrypt,
+// which encrypts a secret read from stdin for the encrypted-file secrets
+// provider. Returns the exit code.
+func configCommand(co *config.Options, args []string) int {
+
+	if len(args) != 1 || (args[0] != "print" && args[0] != "encrypt") {
+		fmt.Fprintln(os.Stderr, "usage: config print|encrypt")
+		return 2
+	}
+
+	c, err := config.Load(co)
+
+	if args[0] == "encrypt" {
+		// the configuration may be incomplete until the secret is added
+		ct, err := encryptSecret(c, os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(ct)
+		return 0
+	}
+
+	perr := c.Print(os.Stdout)
+	if perr != nil {
+		fmt.Fprintln(os.Stderr, perr)
+		return 1
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// encryptSecret encrypts a secret with APP_SECRETS_KEY
+func encryptSecret(c *config.Config, r io.Reader) (string, error) {
+
+	key, err := encryption.Key("APP_SECRETS_KEY", c.Secrets.Key)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
 
-	l.Error().Msgf(fmt.Sprintf("%v", http.ListenAndServe(fmt.Sprintf(":%s", sp), r)))
+	return encryption.Encrypt(key, strings.TrimRight(string(b), "\r\n"))
 }
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/middleware/ratelimit"
+	"github.com/vegh1010/test/pkg/model/apiclient"
+	"github.com/vegh1010/test/pkg/model/modelinit"
+	"github.com/vegh1010/test/pkg/model/tenant"
+)
+
+// Creates an API client and prints its API key. The key is not stored and
+// cannot be shown again. Clients belong to the default tenant unless an
+// existing tenant is given with -tenant, or a new tenant is created with
+// -tenant-name. -rate-limit gives the client a rate limit of its own.
+func main() {
+
+	name := flag.String("name", "", "API client name")
+	role := flag.String("role", apiclient.RoleUser, "API client role, admin, user or operator")
+	tenantID := flag.String("tenant", tenant.DefaultID, "ID of the tenant the API client belongs to")
+	tenantName := flag.String("tenant-name", "", "Create a new tenant with this name for the API client")
+	rateLimit := flag.String("rate-limit", "", "API client rate limit across all routes, in place of APP_RATE_LIMIT, e.g. 6000/1m")
+	co := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *name == "" || (*role != apiclient.RoleAdmin && *role != apiclient.RoleUser && *role != apiclient.RoleOperator) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	_, err := ratelimit.ParseLimit(*rateLimit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -rate-limit %s: %v\n", *rateLimit, err)
+		os.Exit(2)
+	}
+
+	// environment
+	e := env.MustLoad(co)
+
+	// logger
+	l := logger.NewLogger(e)
+
+	// database
+	db := db.NewDB(l, e)
+
+	// prepare model statements.
+	modelinit.PrepareStatements(db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		panic(fmt.Sprintf("Begin tx error: %v", err))
+	}
+
+	tm, err := tenant.NewModel(e, l, tx)
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Model error: %v", err))
+	}
+
+	var trec *tenant.Record
+	if *tenantName != "" {
+		r := tm.NewRecord()
+		r.Name = *tenantName
+		err = tm.Create(&r)
+		trec = &r
+	} else {
+		trec, err = tm.GetByID(*tenantID)
+	}
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Tenant error: %v", err))
+	}
+
+	m, err := apiclient.NewModel(e, l, tx)
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Model error: %v", err))
+	}
+
+	rec := m.NewRecord()
+	rec.TenantID = trec.ID
+	rec.Name = *name
+	rec.Role = *role
+	if *rateLimit != "" {
+		rec.RateLimit = sql.NullString{String: *rateLimit, Valid: true}
+	}
+
+	key, err := m.Create(&rec)
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Create API client error: %v", err))
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		panic(fmt.Sprintf("Commit tx error: %v", err))
+	}
+
+	fmt.Printf("API client %s (%s) created for tenant %s (%s)\nAPI key: %s\n", rec.Name, rec.ID, trec.Name, trec.ID, key)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/iso3166"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/model/modelinit"
+)
+
+// Imports ISO 3166 countries and subdivisions from -file, the bundled
+// database/data/iso3166.json by default, listing each change made.
+func main() {
+
+	file := flag.String("file", "", "ISO 3166 data file, default $APP_HOME/"+iso3166.DefaultFile)
+	dryRun := flag.Bool("dry-run", false, "list the changes without making them")
+	co := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	// environment
+	e := env.MustLoad(co)
+
+	// logger
+	l := logger.NewLogger(e)
+
+	if *file == "" {
+		*file = filepath.Join(e.Get("APP_HOME"), iso3166.DefaultFile)
+	}
+
+	f, err := iso3166.Load(*file)
+	if err != nil {
+		panic(fmt.Sprintf("Load data file error: %v", err))
+	}
+
+	// database
+	db := db.NewDB(l, e)
+
+	// prepare model statements.
+	modelinit.PrepareStatements(db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		panic(fmt.Sprintf("Begin tx error: %v", err))
+	}
+
+	p, err := iso3166.Import(e, l, tx, f)
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Import countries error: %v", err))
+	}
+
+	for _, c := range p.Changes {
+		fmt.Println(c)
+	}
+
+	if *dryRun {
+		tx.Rollback()
+		fmt.Printf("Dry run, would have made %s\n", p.Summary())
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		panic(fmt.Sprintf("Commit tx error: %v", err))
+	}
+
+	fmt.Printf("Imported %d countries and %d subdivisions from %s, %s\n", len(f.Countries), len(f.Subdivisions), *file, p.Summary())
+}
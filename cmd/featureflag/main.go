@@ -0,0 +1,195 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/model/featureflag"
+	"github.com/vegh1010/test/pkg/model/modelinit"
+)
+
+// Lists feature flags, or creates or updates the flag -name. With -tenant
+// or -client the flag is set for that tenant or API client alone, or with
+// -clear goes back to the flag's own setting for them. Running processes
+// see changes once APP_FEATURE_FLAG_TTL has passed.
+func main() {
+
+	name := flag.String("name", "", "feature flag name, flags are listed when not given")
+	enabled := flag.String("enabled", "", "true or false, turns the flag on or off")
+	description := flag.String("description", "", "what the flag gates")
+	tenantID := flag.String("tenant", "", "ID of a tenant to set the flag for")
+	clientID := flag.String("client", "", "ID of an API client to set the flag for")
+	clearRule := flag.Bool("clear", false, "remove the flag's setting for -tenant or -client")
+	co := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	rule := *tenantID != "" || *clientID != ""
+
+	var on bool
+	if *enabled != "" {
+		var err error
+		on, err = strconv.ParseBool(*enabled)
+		if err != nil {
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
+	// a rule is for a tenant or an API client, and is set or cleared
+	invalid := *name == "" && (*enabled != "" || rule || *clearRule)
+	invalid = invalid || (*tenantID != "" && *clientID != "")
+	invalid = invalid || (*clearRule && (!rule || *enabled != ""))
+	invalid = invalid || (*name != "" && *enabled == "" && !*clearRule)
+	if invalid {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// environment
+	e := env.MustLoad(co)
+
+	// logger
+	l := logger.NewLogger(e)
+
+	// database
+	db := db.NewDB(l, e)
+
+	// prepare model statements.
+	modelinit.PrepareStatements(db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		panic(fmt.Sprintf("Begin tx error: %v", err))
+	}
+
+	m, err := featureflag.NewModel(e, l, tx)
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Model error: %v", err))
+	}
+
+	if *name == "" {
+		err = list(m)
+		tx.Rollback()
+		if err != nil {
+			panic(fmt.Sprintf("List feature flags error: %v", err))
+		}
+		return
+	}
+
+	if !rule {
+		rec := m.NewRecord()
+		rec.Name = *name
+		rec.Enabled = on
+		rec.Description = sql.NullString{String: *description, Valid: *description != ""}
+
+		err = m.Upsert(&rec)
+		if err != nil {
+			tx.Rollback()
+			panic(fmt.Sprintf("Set feature flag error: %v", err))
+		}
+
+		commit(tx.Commit())
+		fmt.Printf("Feature flag %s is %s\n", rec.Name, onOff(rec.Enabled))
+		return
+	}
+
+	frec, err := m.GetByName(*name)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		fmt.Fprintf(os.Stderr, "Feature flag %s does not exist, create it with -enabled first\n", *name)
+		os.Exit(1)
+	}
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Feature flag error: %v", err))
+	}
+
+	rec := m.NewRuleRecord()
+	rec.FeatureFlagID = frec.ID
+	rec.TenantID = sql.NullString{String: *tenantID, Valid: *tenantID != ""}
+	rec.APIClientID = sql.NullString{String: *clientID, Valid: *clientID != ""}
+	rec.Enabled = on
+
+	target := "tenant " + *tenantID
+	if *clientID != "" {
+		target = "API client " + *clientID
+	}
+
+	if *clearRule {
+		err = m.DeleteRule(&rec)
+		if err != nil {
+			tx.Rollback()
+			panic(fmt.Sprintf("Clear feature flag rule error: %v", err))
+		}
+
+		commit(tx.Commit())
+		fmt.Printf("Feature flag %s is %s for %s\n", frec.Name, onOff(frec.Enabled), target)
+		return
+	}
+
+	err = m.SetRule(&rec)
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Set feature flag rule error: %v", err))
+	}
+
+	commit(tx.Commit())
+	fmt.Printf("Feature flag %s is %s for %s\n", frec.Name, onOff(rec.Enabled), target)
+}
+
+// list prints every flag with its rules
+func list(m *featureflag.Model) error {
+
+	recs, err := m.GetAll()
+	if err != nil {
+		return err
+	}
+
+	rules, err := m.GetAllRules()
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range recs {
+		fmt.Printf("%s %s", rec.Name, onOff(rec.Enabled))
+		if rec.Description.Valid {
+			fmt.Printf(" - %s", rec.Description.String)
+		}
+		fmt.Println()
+
+		for _, rule := range rules {
+			if rule.FeatureFlagID != rec.ID {
+				continue
+			}
+			if rule.TenantID.Valid {
+				fmt.Printf("  tenant %s %s\n", rule.TenantID.String, onOff(rule.Enabled))
+			}
+			if rule.APIClientID.Valid {
+				fmt.Printf("  API client %s %s\n", rule.APIClientID.String, onOff(rule.Enabled))
+			}
+		}
+	}
+
+	return nil
+}
+
+func commit(err error) {
+	if err != nil {
+		panic(fmt.Sprintf("Commit tx error: %v", err))
+	}
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,11 @@
+// +build test_no_fixtures
+
+package main
+
+import (
+	"testing"
+)
+
+func TestMain(t *testing.T) {
+	// main compiles
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/model/modelinit"
+	"github.com/vegh1010/test/pkg/tzsync"
+)
+
+// Syncs the timezone table with the tz database, from -source or the
+// first of APP_TIMEZONE_SOURCE, ZONEINFO and /usr/share/zoneinfo that is
+// set.
+func main() {
+
+	source := flag.String("source", "", "zoneinfo directory to load time zones from")
+	dryRun := flag.Bool("dry-run", false, "list the time zones found without storing them")
+	co := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	// environment
+	e := env.MustLoad(co)
+
+	// logger
+	l := logger.NewLogger(e)
+
+	if *source == "" {
+		*source = tzsync.Source(e)
+	}
+
+	zones, err := tzsync.Load(*source, time.Now())
+	if err != nil {
+		panic(fmt.Sprintf("Load time zones error: %v", err))
+	}
+
+	if *dryRun {
+		for _, z := range zones {
+			fmt.Printf("%s\t%d\t%t\t%s\n", z.ID, z.UTCOffset, z.DST, z.CanonicalID)
+		}
+		return
+	}
+
+	// database
+	db := db.NewDB(l, e)
+
+	// prepare model statements.
+	modelinit.PrepareStatements(db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		panic(fmt.Sprintf("Begin tx error: %v", err))
+	}
+
+	res, err := tzsync.Sync(e, l, tx, zones)
+	if err != nil {
+		tx.Rollback()
+		panic(fmt.Sprintf("Sync time zones error: %v", err))
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		panic(fmt.Sprintf("Commit tx error: %v", err))
+	}
+
+	fmt.Printf("Synced %d time zones, %d aliases, from %s, deactivated %d\n", res.Zones, res.Aliases, *source, res.Deactivated)
+}
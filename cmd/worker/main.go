@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	_ "github.com/vegh1010/test/database/migrations"
+	_ "github.com/vegh1010/test/database/seeds"
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/jobs/jobinit"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/migrate"
+	"github.com/vegh1010/test/pkg/model/modelinit"
+)
+
+func main() {
+	runtime.GOMAXPROCS(runtime.NumCPU())
+
+	co := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	// environment
+	e := env.MustLoad(co)
+
+	// logger
+	l := logger.NewLogger(e)
+
+	// schema - checked or migrated before statements are prepared
+	err := migrate.Startup(e, l)
+	if err != nil {
+		panic(fmt.Sprintf("Migration error: %v", err))
+	}
+
+	// database
+	d := db.NewDB(l, e)
+
+	// secrets - read again on SIGHUP so database credentials can rotate
+	stop := db.WatchSecrets(l, e, d)
+	defer stop()
+
+	// log levels - set again from the configuration on SIGHUP, replacing
+	// levels changed at runtime
+	stopLevels := logger.WatchLevels(l, e)
+	defer stopLevels()
+
+	// prepare model statements.
+	l.Info().Msg("Preparing model statements")
+	modelinit.PrepareStatements(d)
+
+	// reference data
+	l.Info().Msg("Loading reference data")
+	err = modelinit.LoadReferenceData()
+	if err != nil {
+		panic(fmt.Sprintf("Reference data error: %v", err))
+	}
+
+	// worker
+	w, err := jobinit.NewWorker(e, l, d)
+	if err != nil {
+		panic(fmt.Sprintf("Worker error: %v", err))
+	}
+
+	w.Start()
+
+	// wait for shutdown
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	s := <-sig
+
+	l.Info().Msgf("Received %s, shutting down", s)
+
+	w.Stop()
+}
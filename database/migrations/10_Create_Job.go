@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE job (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			queue         	TEXT              NOT NULL DEFAULT 'default',
+		  			type          	TEXT              NOT NULL,
+		  			payload       	JSONB             NOT NULL DEFAULT '{}',
+		  			unique_key    	TEXT              NULL,
+		  			status        	e_job_status NOT NULL DEFAULT 'pending',
+		  			attempts      	INTEGER           NOT NULL DEFAULT 0,
+		  			max_attempts  	INTEGER           NOT NULL DEFAULT 10,
+		  			run_at        	TIMESTAMP         NOT NULL DEFAULT now(),
+		  			last_error    	TEXT              NULL,
+		  			completed_at  	TIMESTAMP         NULL,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		job_pk PRIMARY KEY (id)
+		);
+		CREATE INDEX job_pending_idx ON job (queue, run_at) WHERE status = 'pending';
+		CREATE UNIQUE INDEX job_unique_key_idx ON job (unique_key) WHERE unique_key IS NOT NULL;`
+
+	downQuery := `DROP TABLE job;`
+
+	migrate.Register(10, "Create_Job", upQuery, downQuery)
+}
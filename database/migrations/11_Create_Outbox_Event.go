@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE outbox_event (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			sequence      	BIGSERIAL         NOT NULL,
+		  			aggregate_type	TEXT              NOT NULL,
+		  			aggregate_id  	TEXT              NOT NULL,
+		  			event_type    	TEXT              NOT NULL,
+		  			payload       	JSONB             NOT NULL DEFAULT '{}',
+		  			processed_at  	TIMESTAMP         NULL,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		outbox_event_pk PRIMARY KEY (id),
+					CONSTRAINT 		outbox_event_sequence_uk UNIQUE (sequence)
+		);
+		CREATE INDEX outbox_event_unprocessed_idx ON outbox_event (sequence) WHERE processed_at IS NULL;`
+
+	downQuery := `DROP TABLE outbox_event;`
+
+	migrate.Register(11, "Create_Outbox_Event", upQuery, downQuery)
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_webhook_status AS ENUM (
+		  		'active',
+		  		'inactive'
+		);`
+
+	downQuery := `DROP TYPE e_webhook_status;`
+
+	migrate.Register(12, "Create_E_Webhook_Status", upQuery, downQuery)
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE webhook (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			url           	TEXT              NOT NULL,
+		  			description   	TEXT              NOT NULL DEFAULT '',
+		  			secret        	TEXT              NOT NULL,
+		  			event_types   	TEXT[]            NOT NULL DEFAULT '{}',
+		  			status        	e_webhook_status NOT NULL DEFAULT 'active',
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		webhook_pk PRIMARY KEY (id)
+		);`
+
+	downQuery := `DROP TABLE webhook;`
+
+	migrate.Register(13, "Create_Webhook", upQuery, downQuery)
+}
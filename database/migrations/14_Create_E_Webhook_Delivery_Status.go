@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_webhook_delivery_status AS ENUM (
+		  		'pending',
+		  		'delivered',
+		  		'dead'
+		);`
+
+	downQuery := `DROP TYPE e_webhook_delivery_status;`
+
+	migrate.Register(14, "Create_E_Webhook_Delivery_Status", upQuery, downQuery)
+}
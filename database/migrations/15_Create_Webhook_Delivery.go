@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE webhook_delivery (
+					id              	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			webhook_id      	UUID              NOT NULL,
+		  			outbox_event_id 	UUID              NOT NULL,
+		  			event_sequence  	BIGINT            NOT NULL,
+		  			event_type      	TEXT              NOT NULL,
+		  			status          	e_webhook_delivery_status NOT NULL DEFAULT 'pending',
+		  			attempts        	INTEGER           NOT NULL DEFAULT 0,
+		  			next_attempt_at 	TIMESTAMP         NOT NULL DEFAULT now(),
+		  			response_status 	INTEGER           NULL,
+		  			response_body   	TEXT              NULL,
+		  			last_error      	TEXT              NULL,
+		  			delivered_at    	TIMESTAMP         NULL,
+					created_at      	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at      	TIMESTAMP         NULL,
+					deleted_at      	TIMESTAMP         NULL,
+					CONSTRAINT 		webhook_delivery_pk PRIMARY KEY (id),
+					CONSTRAINT 		webhook_delivery_uk UNIQUE (webhook_id, outbox_event_id),
+		  			CONSTRAINT 		webhook_delivery_webhook_fk FOREIGN KEY (webhook_id) REFERENCES webhook (id),
+		  			CONSTRAINT 		webhook_delivery_outbox_event_fk FOREIGN KEY (outbox_event_id) REFERENCES outbox_event (id)
+		);
+		CREATE INDEX webhook_delivery_pending_idx ON webhook_delivery (webhook_id, event_sequence) WHERE status = 'pending';`
+
+	downQuery := `DROP TABLE webhook_delivery;`
+
+	migrate.Register(15, "Create_Webhook_Delivery", upQuery, downQuery)
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_api_client_role AS ENUM (
+		  		'admin',
+		  		'user'
+		);`
+
+	downQuery := `DROP TYPE e_api_client_role;`
+
+	migrate.Register(16, "Create_E_Api_Client_Role", upQuery, downQuery)
+}
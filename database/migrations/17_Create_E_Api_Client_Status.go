@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_api_client_status AS ENUM (
+		  		'active',
+		  		'inactive'
+		);`
+
+	downQuery := `DROP TYPE e_api_client_status;`
+
+	migrate.Register(17, "Create_E_Api_Client_Status", upQuery, downQuery)
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE api_client (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			name          	TEXT              NOT NULL,
+		  			key_hash      	TEXT              NOT NULL,
+		  			role          	e_api_client_role NOT NULL DEFAULT 'user',
+		  			status        	e_api_client_status NOT NULL DEFAULT 'active',
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		api_client_pk PRIMARY KEY (id),
+					CONSTRAINT 		api_client_key_hash_uk UNIQUE (key_hash)
+		);`
+
+	downQuery := `DROP TABLE api_client;`
+
+	migrate.Register(18, "Create_Api_Client", upQuery, downQuery)
+}
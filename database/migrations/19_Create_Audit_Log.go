@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE audit_log (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			entity_type   	TEXT              NOT NULL,
+		  			entity_id     	TEXT              NOT NULL,
+		  			operation     	TEXT              NOT NULL,
+		  			actor_id      	TEXT              NOT NULL,
+		  			actor_name    	TEXT              NOT NULL,
+		  			request_id    	TEXT              NOT NULL DEFAULT '',
+		  			before        	JSONB             NULL,
+		  			after         	JSONB             NULL,
+		  			diff          	JSONB             NOT NULL DEFAULT '{}',
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					CONSTRAINT 		audit_log_pk PRIMARY KEY (id)
+		);
+		CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id, created_at);
+
+		CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_log is append only, % is not permitted', TG_OP;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE TRIGGER audit_log_no_update_delete
+			BEFORE UPDATE OR DELETE ON audit_log
+			FOR EACH ROW EXECUTE PROCEDURE audit_log_append_only();
+
+		CREATE TRIGGER audit_log_no_truncate
+			BEFORE TRUNCATE ON audit_log
+			FOR EACH STATEMENT EXECUTE PROCEDURE audit_log_append_only();`
+
+	downQuery := `DROP TABLE audit_log;
+		DROP FUNCTION audit_log_append_only();`
+
+	migrate.Register(19, "Create_Audit_Log", upQuery, downQuery)
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+		CREATE INDEX merchant_search_idx ON merchant USING GIN (
+			to_tsvector('simple', name || ' ' || short_name || ' ' || dba_name)
+		) WHERE deleted_at IS NULL;
+		CREATE INDEX merchant_name_trgm_idx ON merchant USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL;
+		CREATE INDEX merchant_short_name_trgm_idx ON merchant USING GIN (short_name gin_trgm_ops) WHERE deleted_at IS NULL;
+		CREATE INDEX merchant_dba_name_trgm_idx ON merchant USING GIN (dba_name gin_trgm_ops) WHERE deleted_at IS NULL;`
+
+	downQuery := `DROP INDEX merchant_search_idx;
+		DROP INDEX merchant_name_trgm_idx;
+		DROP INDEX merchant_short_name_trgm_idx;
+		DROP INDEX merchant_dba_name_trgm_idx;`
+
+	migrate.Register(20, "Create_Merchant_Search", upQuery, downQuery)
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_organisation_status AS ENUM (
+		  		'active',
+		  		'inactive',
+		  		'terminated'
+		);`
+
+	downQuery := `DROP TYPE e_organisation_status;`
+
+	migrate.Register(21, "Create_E_Organisation_Status", upQuery, downQuery)
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE organisation (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			parent_id     	UUID              NULL,
+		  			name          	TEXT              NOT NULL,
+		  			status        	e_organisation_status NOT NULL DEFAULT 'active',
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		organisation_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		organisation_parent_fk FOREIGN KEY (parent_id) REFERENCES organisation (id)
+		);
+		CREATE INDEX organisation_parent_idx ON organisation (parent_id);`
+
+	downQuery := `DROP TABLE organisation;`
+
+	migrate.Register(22, "Create_Organisation", upQuery, downQuery)
+}
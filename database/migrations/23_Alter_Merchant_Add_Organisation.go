@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `ALTER TABLE merchant
+			ADD COLUMN organisation_id UUID NULL,
+			ADD CONSTRAINT merchant_organisation_fk FOREIGN KEY (organisation_id) REFERENCES organisation (id);
+		CREATE INDEX merchant_organisation_idx ON merchant (organisation_id);`
+
+	downQuery := `ALTER TABLE merchant DROP COLUMN organisation_id;`
+
+	migrate.Register(23, "Alter_Merchant_Add_Organisation", upQuery, downQuery)
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_location_status AS ENUM (
+		  		'active',
+		  		'inactive',
+		  		'terminated'
+		);`
+
+	downQuery := `DROP TYPE e_location_status;`
+
+	migrate.Register(24, "Create_E_Location_Status", upQuery, downQuery)
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE location (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			merchant_id   	UUID              NOT NULL,
+		  			name          	TEXT              NOT NULL,
+		  			timezone_id   	TEXT              NOT NULL,
+		  			status        	e_location_status NOT NULL DEFAULT 'active',
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		location_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		location_merchant_fk FOREIGN KEY (merchant_id) REFERENCES merchant (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		location_timezone_fk FOREIGN KEY (timezone_id) REFERENCES timezone (id)
+		);
+		CREATE INDEX location_merchant_idx ON location (merchant_id);`
+
+	downQuery := `DROP TABLE location;`
+
+	migrate.Register(25, "Create_Location", upQuery, downQuery)
+}
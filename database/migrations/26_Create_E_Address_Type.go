@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_address_type AS ENUM (
+		  		'registered',
+		  		'trading',
+		  		'postal'
+		);`
+
+	downQuery := `DROP TYPE e_address_type;`
+
+	migrate.Register(26, "Create_E_Address_Type", upQuery, downQuery)
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE merchant_address (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			merchant_id   	UUID              NOT NULL,
+		  			type          	e_address_type NOT NULL,
+		  			line1         	TEXT              NOT NULL,
+		  			line2         	TEXT              NOT NULL DEFAULT '',
+		  			city          	TEXT              NOT NULL,
+		  			region        	TEXT              NOT NULL DEFAULT '',
+		  			postal_code   	TEXT              NOT NULL DEFAULT '',
+		  			country_id    	VARCHAR(2)        NOT NULL,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		merchant_address_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		merchant_address_merchant_fk FOREIGN KEY (merchant_id) REFERENCES merchant (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		merchant_address_country_fk FOREIGN KEY (country_id) REFERENCES country (id)
+		);
+		CREATE INDEX merchant_address_merchant_idx ON merchant_address (merchant_id);`
+
+	downQuery := `DROP TABLE merchant_address;`
+
+	migrate.Register(27, "Create_Merchant_Address", upQuery, downQuery)
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_contact_role AS ENUM (
+		  		'billing',
+		  		'technical',
+		  		'legal'
+		);`
+
+	downQuery := `DROP TYPE e_contact_role;`
+
+	migrate.Register(28, "Create_E_Contact_Role", upQuery, downQuery)
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE merchant_contact (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			merchant_id   	UUID              NOT NULL,
+		  			role          	e_contact_role NOT NULL,
+		  			name          	TEXT              NOT NULL,
+		  			email         	TEXT              NOT NULL DEFAULT '',
+		  			phone         	TEXT              NOT NULL DEFAULT '',
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		merchant_contact_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		merchant_contact_merchant_fk FOREIGN KEY (merchant_id) REFERENCES merchant (id) ON DELETE CASCADE
+		);
+		CREATE INDEX merchant_contact_merchant_idx ON merchant_contact (merchant_id);`
+
+	downQuery := `DROP TABLE merchant_contact;`
+
+	migrate.Register(29, "Create_Merchant_Contact", upQuery, downQuery)
+}
@@ -1,32 +1,24 @@
-package main
+package migrations
 
 import (
-	"gopkg.in/go-pg/migrations.v5"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func init() {
-	migrations.Register(func(db migrations.DB) error {
-		upQuery := `CREATE TABLE ` + GetDatabaseName() +`.country (
+	upQuery := `CREATE TABLE country (
 				id              VARCHAR(2)        NOT NULL,
 				name            TEXT              NOT NULL,
 				alpha2_code     VARCHAR(2)        NOT NULL,
 				alpha3_code     VARCHAR(3)        NOT NULL,
 				numeric_code    VARCHAR(3)        NOT NULL,
-				status          ` + GetDatabaseName() +`.e_country_status  NOT NULL DEFAULT 'active',
+				status          e_country_status  NOT NULL DEFAULT 'active',
 				created_at      TIMESTAMP         NOT NULL DEFAULT now(),
 				updated_at      TIMESTAMP         NULL,
 				deleted_at      TIMESTAMP         NULL,
   				CONSTRAINT country_pk PRIMARY KEY (id)
 		);`
 
-		_, err := db.Exec(upQuery)
+	downQuery := `DROP TABLE country;`
 
-		return err
-	}, func(db migrations.DB) error {
-		downQuery := `DROP TABLE ` + GetDatabaseName() +`.country;`
-
-		_, err := db.Exec(downQuery)
-
-		return err
-	})
+	migrate.Register(2, "Create_Country", upQuery, downQuery)
 }
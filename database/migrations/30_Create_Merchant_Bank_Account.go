@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE merchant_bank_account (
+					id                      	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			merchant_id             	UUID              NOT NULL,
+		  			account_name            	TEXT              NOT NULL,
+		  			country_id              	VARCHAR(2)        NOT NULL,
+		  			currency                	VARCHAR(3)        NOT NULL,
+		  			bank_code               	TEXT              NOT NULL DEFAULT '',
+		  			account_number_encrypted	TEXT              NOT NULL,
+		  			is_default              	BOOLEAN           NOT NULL DEFAULT FALSE,
+					created_at              	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at              	TIMESTAMP         NULL,
+					deleted_at              	TIMESTAMP         NULL,
+					CONSTRAINT 		merchant_bank_account_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		merchant_bank_account_merchant_fk FOREIGN KEY (merchant_id) REFERENCES merchant (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		merchant_bank_account_country_fk FOREIGN KEY (country_id) REFERENCES country (id)
+		);
+		CREATE INDEX merchant_bank_account_merchant_idx ON merchant_bank_account (merchant_id);
+		CREATE UNIQUE INDEX merchant_bank_account_default_idx ON merchant_bank_account (merchant_id, currency) WHERE is_default AND deleted_at IS NULL;`
+
+	downQuery := `DROP TABLE merchant_bank_account;`
+
+	migrate.Register(30, "Create_Merchant_Bank_Account", upQuery, downQuery)
+}
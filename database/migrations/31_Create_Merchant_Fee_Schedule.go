@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE merchant_fee_schedule (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			merchant_id   	UUID              NOT NULL,
+		  			currency      	VARCHAR(3)        NOT NULL,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		merchant_fee_schedule_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		merchant_fee_schedule_merchant_fk FOREIGN KEY (merchant_id) REFERENCES merchant (id) ON DELETE CASCADE
+		);
+		CREATE UNIQUE INDEX merchant_fee_schedule_currency_idx ON merchant_fee_schedule (merchant_id, currency) WHERE deleted_at IS NULL;`
+
+	downQuery := `DROP TABLE merchant_fee_schedule;`
+
+	migrate.Register(31, "Create_Merchant_Fee_Schedule", upQuery, downQuery)
+}
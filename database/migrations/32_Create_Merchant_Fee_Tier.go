@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE merchant_fee_tier (
+					id              	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			fee_schedule_id 	UUID              NOT NULL,
+		  			min_amount      	BIGINT            NOT NULL,
+		  			percentage      	NUMERIC(7,4)      NOT NULL,
+		  			fixed_fee       	BIGINT            NOT NULL,
+					created_at      	TIMESTAMP         NOT NULL DEFAULT now(),
+					CONSTRAINT 		merchant_fee_tier_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		merchant_fee_tier_schedule_fk FOREIGN KEY (fee_schedule_id) REFERENCES merchant_fee_schedule (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		merchant_fee_tier_min_amount_uq UNIQUE (fee_schedule_id, min_amount),
+		  			CONSTRAINT 		merchant_fee_tier_min_amount_ck CHECK (min_amount >= 0),
+		  			CONSTRAINT 		merchant_fee_tier_percentage_ck CHECK (percentage >= 0 AND percentage <= 100),
+		  			CONSTRAINT 		merchant_fee_tier_fixed_fee_ck CHECK (fixed_fee >= 0)
+		);`
+
+	downQuery := `DROP TABLE merchant_fee_tier;`
+
+	migrate.Register(32, "Create_Merchant_Fee_Tier", upQuery, downQuery)
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_currency_status AS ENUM (
+            	'active',
+            	'inactive'
+        );`
+
+	downQuery := `DROP TYPE e_currency_status;`
+
+	migrate.Register(33, "Create_E_Currency_Status", upQuery, downQuery)
+}
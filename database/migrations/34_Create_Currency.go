@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE currency (
+				id              VARCHAR(3)        NOT NULL,
+				name            TEXT              NOT NULL,
+				numeric_code    VARCHAR(3)        NOT NULL,
+				minor_units     SMALLINT          NOT NULL,
+				status          e_currency_status  NOT NULL DEFAULT 'active',
+				created_at      TIMESTAMP         NOT NULL DEFAULT now(),
+				updated_at      TIMESTAMP         NULL,
+				deleted_at      TIMESTAMP         NULL,
+  				CONSTRAINT currency_pk PRIMARY KEY (id),
+  				CONSTRAINT currency_minor_units_ck CHECK (minor_units >= 0 AND minor_units <= 4)
+		);`
+
+	downQuery := `DROP TABLE currency;`
+
+	migrate.Register(34, "Create_Currency", upQuery, downQuery)
+}
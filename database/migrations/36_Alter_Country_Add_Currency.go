@@ -0,0 +1,265 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `ALTER TABLE country
+			ADD COLUMN currency_id VARCHAR(3) NULL,
+			ADD CONSTRAINT country_currency_fk FOREIGN KEY (currency_id) REFERENCES currency (id);
+
+		UPDATE country c SET currency_id = v.currency_id FROM (VALUES
+('AF',    'AFN'),
+('AL',    'ALL'),
+('DZ',    'DZD'),
+('AS',    'USD'),
+('AD',    'EUR'),
+('AO',    'AOA'),
+('AG',    'XCD'),
+('AZ',    'AZN'),
+('AR',    'ARS'),
+('AU',    'AUD'),
+('AT',    'EUR'),
+('BS',    'BSD'),
+('BH',    'BHD'),
+('BD',    'BDT'),
+('AM',    'AMD'),
+('BB',    'BBD'),
+('BE',    'EUR'),
+('BM',    'BMD'),
+('BT',    'BTN'),
+('BO',    'BOB'),
+('BA',    'BAM'),
+('BW',    'BWP'),
+('BV',    'NOK'),
+('BR',    'BRL'),
+('BZ',    'BZD'),
+('IO',    'USD'),
+('SB',    'SBD'),
+('VG',    'USD'),
+('BN',    'BND'),
+('BG',    'EUR'),
+('MM',    'MMK'),
+('BI',    'BIF'),
+('BY',    'BYN'),
+('KH',    'KHR'),
+('CM',    'XAF'),
+('CA',    'CAD'),
+('CV',    'CVE'),
+('KY',    'KYD'),
+('CF',    'XAF'),
+('LK',    'LKR'),
+('TD',    'XAF'),
+('CL',    'CLP'),
+('CN',    'CNY'),
+('TW',    'TWD'),
+('CX',    'AUD'),
+('CC',    'AUD'),
+('CO',    'COP'),
+('KM',    'KMF'),
+('YT',    'EUR'),
+('CG',    'XAF'),
+('CD',    'CDF'),
+('CK',    'NZD'),
+('CR',    'CRC'),
+('HR',    'EUR'),
+('CU',    'CUP'),
+('CY',    'EUR'),
+('CZ',    'CZK'),
+('BJ',    'XOF'),
+('DK',    'DKK'),
+('DM',    'XCD'),
+('DO',    'DOP'),
+('EC',    'USD'),
+('SV',    'USD'),
+('GQ',    'XAF'),
+('ET',    'ETB'),
+('ER',    'ERN'),
+('EE',    'EUR'),
+('FO',    'DKK'),
+('FK',    'FKP'),
+('GS',    'GBP'),
+('FJ',    'FJD'),
+('FI',    'EUR'),
+('AX',    'EUR'),
+('FR',    'EUR'),
+('GF',    'EUR'),
+('PF',    'XPF'),
+('TF',    'EUR'),
+('DJ',    'DJF'),
+('GA',    'XAF'),
+('GE',    'GEL'),
+('GM',    'GMD'),
+('PS',    'ILS'),
+('DE',    'EUR'),
+('GH',    'GHS'),
+('GI',    'GIP'),
+('KI',    'AUD'),
+('GR',    'EUR'),
+('GL',    'DKK'),
+('GD',    'XCD'),
+('GP',    'EUR'),
+('GU',    'USD'),
+('GT',    'GTQ'),
+('GN',    'GNF'),
+('GY',    'GYD'),
+('HT',    'HTG'),
+('HM',    'AUD'),
+('VA',    'EUR'),
+('HN',    'HNL'),
+('HK',    'HKD'),
+('HU',    'HUF'),
+('IS',    'ISK'),
+('IN',    'INR'),
+('ID',    'IDR'),
+('IR',    'IRR'),
+('IQ',    'IQD'),
+('IE',    'EUR'),
+('IL',    'ILS'),
+('IT',    'EUR'),
+('CI',    'XOF'),
+('JM',    'JMD'),
+('JP',    'JPY'),
+('KZ',    'KZT'),
+('JO',    'JOD'),
+('KE',    'KES'),
+('KP',    'KPW'),
+('KR',    'KRW'),
+('KW',    'KWD'),
+('KG',    'KGS'),
+('LA',    'LAK'),
+('LB',    'LBP'),
+('LS',    'LSL'),
+('LV',    'EUR'),
+('LR',    'LRD'),
+('LY',    'LYD'),
+('LI',    'CHF'),
+('LT',    'EUR'),
+('LU',    'EUR'),
+('MO',    'MOP'),
+('MG',    'MGA'),
+('MW',    'MWK'),
+('MY',    'MYR'),
+('MV',    'MVR'),
+('ML',    'XOF'),
+('MT',    'EUR'),
+('MQ',    'EUR'),
+('MR',    'MRU'),
+('MU',    'MUR'),
+('MX',    'MXN'),
+('MC',    'EUR'),
+('MN',    'MNT'),
+('MD',    'MDL'),
+('ME',    'EUR'),
+('MS',    'XCD'),
+('MA',    'MAD'),
+('MZ',    'MZN'),
+('OM',    'OMR'),
+('NA',    'NAD'),
+('NR',    'AUD'),
+('NP',    'NPR'),
+('NL',    'EUR'),
+('AN',    'ANG'),
+('AW',    'AWG'),
+('NC',    'XPF'),
+('VU',    'VUV'),
+('NZ',    'NZD'),
+('NI',    'NIO'),
+('NE',    'XOF'),
+('NG',    'NGN'),
+('NU',    'NZD'),
+('NF',    'AUD'),
+('NO',    'NOK'),
+('MP',    'USD'),
+('UM',    'USD'),
+('FM',    'USD'),
+('MH',    'USD'),
+('PW',    'USD'),
+('PK',    'PKR'),
+('PA',    'PAB'),
+('PG',    'PGK'),
+('PY',    'PYG'),
+('PE',    'PEN'),
+('PH',    'PHP'),
+('PN',    'NZD'),
+('PL',    'PLN'),
+('PT',    'EUR'),
+('GW',    'XOF'),
+('TL',    'USD'),
+('PR',    'USD'),
+('QA',    'QAR'),
+('RE',    'EUR'),
+('RO',    'RON'),
+('RU',    'RUB'),
+('RW',    'RWF'),
+('BL',    'EUR'),
+('SH',    'SHP'),
+('KN',    'XCD'),
+('AI',    'XCD'),
+('LC',    'XCD'),
+('MF',    'EUR'),
+('PM',    'EUR'),
+('VC',    'XCD'),
+('SM',    'EUR'),
+('ST',    'STN'),
+('SA',    'SAR'),
+('SN',    'XOF'),
+('RS',    'RSD'),
+('SC',    'SCR'),
+('SL',    'SLE'),
+('SG',    'SGD'),
+('SK',    'EUR'),
+('VN',    'VND'),
+('SI',    'EUR'),
+('SO',    'SOS'),
+('ZA',    'ZAR'),
+('ZW',    'ZWG'),
+('ES',    'EUR'),
+('SS',    'SSP'),
+('EH',    'MAD'),
+('SD',    'SDG'),
+('SR',    'SRD'),
+('SJ',    'NOK'),
+('SZ',    'SZL'),
+('SE',    'SEK'),
+('CH',    'CHF'),
+('SY',    'SYP'),
+('TJ',    'TJS'),
+('TH',    'THB'),
+('TG',    'XOF'),
+('TK',    'NZD'),
+('TO',    'TOP'),
+('TT',    'TTD'),
+('AE',    'AED'),
+('TN',    'TND'),
+('TR',    'TRY'),
+('TM',    'TMT'),
+('TC',    'USD'),
+('TV',    'AUD'),
+('UG',    'UGX'),
+('UA',    'UAH'),
+('MK',    'MKD'),
+('EG',    'EGP'),
+('GB',    'GBP'),
+('GG',    'GBP'),
+('JE',    'GBP'),
+('IM',    'GBP'),
+('TZ',    'TZS'),
+('US',    'USD'),
+('VI',    'USD'),
+('BF',    'XOF'),
+('UY',    'UYU'),
+('UZ',    'UZS'),
+('VE',    'VES'),
+('WF',    'XPF'),
+('WS',    'WST'),
+('YE',    'YER'),
+('ZM',    'ZMW')
+		) AS v (country_id, currency_id)
+		WHERE c.id = v.country_id;`
+
+	downQuery := `ALTER TABLE country DROP COLUMN currency_id;`
+
+	migrate.Register(36, "Alter_Country_Add_Currency", upQuery, downQuery)
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `ALTER TABLE merchant
+			ADD COLUMN currencies TEXT[] NOT NULL DEFAULT '{}';
+
+		UPDATE merchant m SET currencies = ARRAY[c.currency_id]
+		FROM country c
+		WHERE c.id = m.country_id
+		AND c.currency_id IS NOT NULL;`
+
+	downQuery := `ALTER TABLE merchant DROP COLUMN currencies;`
+
+	migrate.Register(37, "Alter_Merchant_Add_Currencies", upQuery, downQuery)
+}
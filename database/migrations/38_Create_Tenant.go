@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE tenant (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			name          	TEXT              NOT NULL,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					deleted_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		tenant_pk PRIMARY KEY (id)
+		);
+
+		INSERT INTO tenant (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'Default');
+
+		CREATE FUNCTION current_tenant_id() RETURNS UUID AS $$
+			SELECT CAST(NULLIF(NULLIF(current_setting('app.tenant_id', true), ''), '*') AS UUID)
+		$$ LANGUAGE SQL STABLE;
+
+		CREATE FUNCTION all_tenants() RETURNS BOOLEAN AS $$
+			SELECT COALESCE(current_setting('app.tenant_id', true) = '*', false)
+		$$ LANGUAGE SQL STABLE;`
+
+	downQuery := `DROP FUNCTION all_tenants();
+		DROP FUNCTION current_tenant_id();
+		DROP TABLE tenant;`
+
+	migrate.Register(38, "Create_Tenant", upQuery, downQuery)
+}
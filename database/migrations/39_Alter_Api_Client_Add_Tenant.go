@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `ALTER TABLE api_client
+			ADD COLUMN tenant_id UUID NULL;
+
+		UPDATE api_client SET tenant_id = '00000000-0000-0000-0000-000000000001';
+
+		ALTER TABLE api_client
+			ALTER COLUMN tenant_id SET NOT NULL,
+			ADD CONSTRAINT api_client_tenant_fk FOREIGN KEY (tenant_id) REFERENCES tenant (id);
+
+		CREATE INDEX api_client_tenant_idx ON api_client (tenant_id);`
+
+	downQuery := `ALTER TABLE api_client DROP COLUMN tenant_id;`
+
+	migrate.Register(39, "Alter_Api_Client_Add_Tenant", upQuery, downQuery)
+}
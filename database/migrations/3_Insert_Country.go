@@ -1,12 +1,11 @@
-package main
+package migrations
 
 import (
-	"gopkg.in/go-pg/migrations.v5"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func init() {
-	migrations.Register(func(db migrations.DB) error {
-		upQuery := `INSERT INTO ` + GetDatabaseName() +`.country (id, name, alpha2_code, alpha3_code, numeric_code, status) VALUES
+	upQuery := `INSERT INTO country (id, name, alpha2_code, alpha3_code, numeric_code, status) VALUES
 ('AF',    'Afghanistan',                                  'AF',   	'AFG',	'004',    'active'),
 ('AL',    'Albania',                                      'AL',   	'ALB',	'008',    'active'),
 ('AQ',    'Antarctica',                                   'AQ',   	'ATA',	'010',    'active'),
@@ -255,14 +254,7 @@ func init() {
 ('YE',    'Yemen',                                        'YE',   	'YEM',	'887',    'active'),
 ('ZM',    'Zambia',                                       'ZM',   	'ZMB',	'894',    'active');`
 
-		_, err := db.Exec(upQuery)
+	downQuery := `TRUNCATE TABLE country;`
 
-		return err
-	}, func(db migrations.DB) error {
-		downQuery := `TRUNCATE TABLE ` + GetDatabaseName() +`.country;`
-
-		_, err := db.Exec(downQuery)
-
-		return err
-	})
+	migrate.Register(3, "Insert_Country", upQuery, downQuery)
 }
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+// tenantTables - tables holding a tenant's data, isolated with row level
+// security in 41_Create_Tenant_Policy
+var tenantTables = []string{
+	"organisation",
+	"merchant",
+	"location",
+	"merchant_address",
+	"merchant_contact",
+	"merchant_bank_account",
+	"merchant_fee_schedule",
+	"merchant_fee_tier",
+	"webhook",
+	"webhook_delivery",
+	"outbox_event",
+	"audit_log",
+}
+
+func init() {
+	// existing rows take the default tenant from the column default, an
+	// UPDATE would be rejected by audit_log's append only trigger
+	upQuery := ``
+	for _, t := range tenantTables {
+		upQuery += `ALTER TABLE ` + t + `
+				ADD COLUMN tenant_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+
+			ALTER TABLE ` + t + `
+				ALTER COLUMN tenant_id SET DEFAULT current_tenant_id(),
+				ADD CONSTRAINT ` + t + `_tenant_fk FOREIGN KEY (tenant_id) REFERENCES tenant (id);
+
+			CREATE INDEX ` + t + `_tenant_idx ON ` + t + ` (tenant_id);
+			`
+	}
+
+	downQuery := ``
+	for _, t := range tenantTables {
+		downQuery += `ALTER TABLE ` + t + ` DROP COLUMN tenant_id;
+			`
+	}
+
+	migrate.Register(40, "Alter_Add_Tenant_Id", upQuery, downQuery)
+
+	// released backfilling with an UPDATE, which failed on databases with
+	// audit rows and left the same schema where it succeeded
+	migrate.Amend(40, "0f91aca1d2660ef37fdabbe56a22b1e087e8d6abcea4bbaa8ba17a8868a81ebe")
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// FORCE applies policies to the table owner, which the
+	// application connects as. Superusers always bypass them.
+	upQuery := ``
+	for _, t := range tenantTables {
+		upQuery += `ALTER TABLE ` + t + ` ENABLE ROW LEVEL SECURITY;
+			ALTER TABLE ` + t + ` FORCE ROW LEVEL SECURITY;
+
+			CREATE POLICY ` + t + `_tenant_policy ON ` + t + `
+				USING (all_tenants() OR tenant_id = current_tenant_id())
+				WITH CHECK (all_tenants() OR tenant_id = current_tenant_id());
+			`
+	}
+
+	downQuery := ``
+	for _, t := range tenantTables {
+		downQuery += `DROP POLICY ` + t + `_tenant_policy ON ` + t + `;
+			ALTER TABLE ` + t + ` NO FORCE ROW LEVEL SECURITY;
+			ALTER TABLE ` + t + ` DISABLE ROW LEVEL SECURITY;
+			`
+	}
+
+	migrate.Register(41, "Create_Tenant_Policy", upQuery, downQuery)
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TABLE rate_limit_bucket (
+					key           	TEXT              NOT NULL,
+		  			tokens        	DOUBLE PRECISION  NOT NULL,
+					updated_at    	TIMESTAMP         NOT NULL,
+					full_at       	TIMESTAMP         NOT NULL,
+					CONSTRAINT 		rate_limit_bucket_pk PRIMARY KEY (key)
+		);
+		CREATE INDEX rate_limit_bucket_full_at_idx ON rate_limit_bucket (full_at);`
+
+	downQuery := `DROP TABLE rate_limit_bucket;`
+
+	migrate.Register(42, "Create_Rate_Limit_Bucket", upQuery, downQuery)
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `ALTER TABLE timezone
+			ADD COLUMN canonical_id  TEXT       NULL,
+			ADD COLUMN utc_offset    INTEGER    NULL,
+			ADD COLUMN dst           BOOLEAN    NOT NULL DEFAULT false,
+			ADD COLUMN synced_at     TIMESTAMP  NULL,
+			ADD CONSTRAINT timezone_canonical_fk FOREIGN KEY (canonical_id) REFERENCES timezone (id);
+
+		CREATE INDEX timezone_canonical_idx ON timezone (canonical_id);`
+
+	downQuery := `ALTER TABLE timezone
+			DROP COLUMN synced_at,
+			DROP COLUMN dst,
+			DROP COLUMN utc_offset,
+			DROP COLUMN canonical_id;`
+
+	migrate.Register(43, "Alter_Timezone_Add_Canonical", upQuery, downQuery)
+}
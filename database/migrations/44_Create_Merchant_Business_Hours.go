@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// opens and closes are minutes since midnight in the merchant's
+	// timezone, closes at or before opens closes the next day
+	upQuery := `CREATE TABLE merchant_business_hours (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+					tenant_id     	UUID              NOT NULL DEFAULT current_tenant_id(),
+		  			merchant_id   	UUID              NOT NULL,
+		  			day_of_week   	SMALLINT          NOT NULL,
+		  			opens         	SMALLINT          NOT NULL,
+		  			closes        	SMALLINT          NOT NULL,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					CONSTRAINT 		merchant_business_hours_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		merchant_business_hours_merchant_fk FOREIGN KEY (merchant_id) REFERENCES merchant (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		merchant_business_hours_tenant_fk FOREIGN KEY (tenant_id) REFERENCES tenant (id),
+		  			CONSTRAINT 		merchant_business_hours_day_of_week_ck CHECK (day_of_week BETWEEN 0 AND 6),
+		  			CONSTRAINT 		merchant_business_hours_opens_ck CHECK (opens >= 0 AND opens < 1440),
+		  			CONSTRAINT 		merchant_business_hours_closes_ck CHECK (closes >= 0 AND closes <= 1440 AND closes <> opens)
+		);
+		CREATE INDEX merchant_business_hours_merchant_idx ON merchant_business_hours (merchant_id);
+		CREATE INDEX merchant_business_hours_tenant_idx ON merchant_business_hours (tenant_id);
+
+		ALTER TABLE merchant_business_hours ENABLE ROW LEVEL SECURITY;
+		ALTER TABLE merchant_business_hours FORCE ROW LEVEL SECURITY;
+
+		CREATE POLICY merchant_business_hours_tenant_policy ON merchant_business_hours
+			USING (all_tenants() OR tenant_id = current_tenant_id())
+			WITH CHECK (all_tenants() OR tenant_id = current_tenant_id());`
+
+	downQuery := `DROP TABLE merchant_business_hours;`
+
+	migrate.Register(44, "Create_Merchant_Business_Hours", upQuery, downQuery)
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// holidays replace a merchant's business hours on their date, closed
+	// all day when opens and closes are null
+	upQuery := `CREATE TABLE merchant_holiday (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+					tenant_id     	UUID              NOT NULL DEFAULT current_tenant_id(),
+		  			merchant_id   	UUID              NOT NULL,
+		  			date          	DATE              NOT NULL,
+		  			name          	VARCHAR(100)      NULL,
+		  			opens         	SMALLINT          NULL,
+		  			closes        	SMALLINT          NULL,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					CONSTRAINT 		merchant_holiday_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		merchant_holiday_merchant_fk FOREIGN KEY (merchant_id) REFERENCES merchant (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		merchant_holiday_tenant_fk FOREIGN KEY (tenant_id) REFERENCES tenant (id),
+		  			CONSTRAINT 		merchant_holiday_hours_ck CHECK ((opens IS NULL) = (closes IS NULL)),
+		  			CONSTRAINT 		merchant_holiday_opens_ck CHECK (opens >= 0 AND opens < 1440),
+		  			CONSTRAINT 		merchant_holiday_closes_ck CHECK (closes >= 0 AND closes <= 1440 AND closes <> opens)
+		);
+		CREATE INDEX merchant_holiday_merchant_idx ON merchant_holiday (merchant_id, date);
+		CREATE INDEX merchant_holiday_tenant_idx ON merchant_holiday (tenant_id);
+
+		ALTER TABLE merchant_holiday ENABLE ROW LEVEL SECURITY;
+		ALTER TABLE merchant_holiday FORCE ROW LEVEL SECURITY;
+
+		CREATE POLICY merchant_holiday_tenant_policy ON merchant_holiday
+			USING (all_tenants() OR tenant_id = current_tenant_id())
+			WITH CHECK (all_tenants() OR tenant_id = current_tenant_id());`
+
+	downQuery := `DROP TABLE merchant_holiday;`
+
+	migrate.Register(45, "Create_Merchant_Holiday", upQuery, downQuery)
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// id is the ISO 3166-1 alpha-2 code, currency_id the country's
+	// default currency
+	upQuery := `ALTER TABLE country
+			ADD COLUMN calling_code VARCHAR(4) NULL,
+			ADD CONSTRAINT country_calling_code_ck CHECK (calling_code ~ '^[0-9]{1,4}$'),
+			ADD CONSTRAINT country_alpha2_code_ck CHECK (alpha2_code = id);`
+
+	downQuery := `ALTER TABLE country
+			DROP CONSTRAINT country_alpha2_code_ck,
+			DROP COLUMN calling_code;`
+
+	migrate.Register(46, "Alter_Country_Add_Calling_Code", upQuery, downQuery)
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// id is the ISO 3166-2 code, AU-NSW, and code the part after the
+	// country, NSW. Subdivisions may sit within a parent subdivision,
+	// the parent constraint is deferred so they can be imported in any
+	// order.
+	upQuery := `CREATE TABLE country_subdivision (
+				id              VARCHAR(6)        NOT NULL,
+				country_id      VARCHAR(2)        NOT NULL,
+				code            VARCHAR(3)        NOT NULL,
+				name            TEXT              NOT NULL,
+				type            TEXT              NOT NULL,
+				parent_id       VARCHAR(6)        NULL,
+				status          e_country_status  NOT NULL DEFAULT 'active',
+				created_at      TIMESTAMP         NOT NULL DEFAULT now(),
+				updated_at      TIMESTAMP         NULL,
+  				CONSTRAINT country_subdivision_pk PRIMARY KEY (id),
+  				CONSTRAINT country_subdivision_country_fk FOREIGN KEY (country_id) REFERENCES country (id),
+  				CONSTRAINT country_subdivision_parent_fk FOREIGN KEY (parent_id) REFERENCES country_subdivision (id) DEFERRABLE INITIALLY DEFERRED,
+  				CONSTRAINT country_subdivision_code_ck CHECK (id = country_id || '-' || code)
+		);
+		CREATE INDEX country_subdivision_country_idx ON country_subdivision (country_id);`
+
+	downQuery := `DROP TABLE country_subdivision;`
+
+	migrate.Register(47, "Create_Country_Subdivision", upQuery, downQuery)
+}
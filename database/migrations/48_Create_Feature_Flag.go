@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// flags are managed by operators for every tenant so have no row level
+	// security, a rule turns a flag on or off for one tenant or API client
+	upQuery := `CREATE TABLE feature_flag (
+					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			name          	VARCHAR(100)      NOT NULL,
+		  			description   	TEXT              NULL,
+		  			enabled       	BOOLEAN           NOT NULL DEFAULT false,
+					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
+					updated_at    	TIMESTAMP         NULL,
+					CONSTRAINT 		feature_flag_pk PRIMARY KEY (id),
+					CONSTRAINT 		feature_flag_name_uk UNIQUE (name)
+		);
+
+		CREATE TABLE feature_flag_rule (
+					id              UUID              NOT NULL DEFAULT gen_random_uuid(),
+		  			feature_flag_id UUID              NOT NULL,
+		  			tenant_id       UUID              NULL,
+		  			api_client_id   UUID              NULL,
+		  			enabled         BOOLEAN           NOT NULL,
+					created_at      TIMESTAMP         NOT NULL DEFAULT now(),
+					CONSTRAINT 		feature_flag_rule_pk PRIMARY KEY (id),
+		  			CONSTRAINT 		feature_flag_rule_flag_fk FOREIGN KEY (feature_flag_id) REFERENCES feature_flag (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		feature_flag_rule_tenant_fk FOREIGN KEY (tenant_id) REFERENCES tenant (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		feature_flag_rule_api_client_fk FOREIGN KEY (api_client_id) REFERENCES api_client (id) ON DELETE CASCADE,
+		  			CONSTRAINT 		feature_flag_rule_target_ck CHECK ((tenant_id IS NULL) <> (api_client_id IS NULL))
+		);
+		CREATE UNIQUE INDEX feature_flag_rule_tenant_uk ON feature_flag_rule (feature_flag_id, tenant_id) WHERE tenant_id IS NOT NULL;
+		CREATE UNIQUE INDEX feature_flag_rule_api_client_uk ON feature_flag_rule (feature_flag_id, api_client_id) WHERE api_client_id IS NOT NULL;`
+
+	downQuery := `DROP TABLE feature_flag_rule;
+		DROP TABLE feature_flag;`
+
+	migrate.Register(48, "Create_Feature_Flag", upQuery, downQuery)
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// the outbox sequence is taken when an event is inserted, not when it
+	// commits, so deliveries are ordered by a sequence of each webhook's
+	// own, taken as they are fanned out. Existing deliveries keep their
+	// event order.
+	upQuery := `SELECT set_config('app.tenant_id', '*', true);
+
+		ALTER TABLE webhook
+			ADD COLUMN delivery_sequence BIGINT NOT NULL DEFAULT 0;
+
+		ALTER TABLE webhook_delivery
+			ADD COLUMN sequence BIGINT NULL;
+
+		UPDATE webhook_delivery d SET sequence = s.sequence
+		FROM (
+			SELECT id, row_number() OVER (PARTITION BY webhook_id ORDER BY event_sequence) AS sequence
+			FROM webhook_delivery
+		) s
+		WHERE s.id = d.id;
+
+		UPDATE webhook w SET delivery_sequence = COALESCE((
+			SELECT max(d.sequence) FROM webhook_delivery d WHERE d.webhook_id = w.id
+		), 0);
+
+		ALTER TABLE webhook_delivery
+			ALTER COLUMN sequence SET NOT NULL,
+			ADD CONSTRAINT webhook_delivery_sequence_uk UNIQUE (webhook_id, sequence);
+
+		DROP INDEX webhook_delivery_pending_idx;
+		CREATE INDEX webhook_delivery_pending_idx ON webhook_delivery (webhook_id, sequence) WHERE status = 'pending';`
+
+	downQuery := `DROP INDEX webhook_delivery_pending_idx;
+		CREATE INDEX webhook_delivery_pending_idx ON webhook_delivery (webhook_id, event_sequence) WHERE status = 'pending';
+
+		ALTER TABLE webhook_delivery DROP COLUMN sequence;
+		ALTER TABLE webhook DROP COLUMN delivery_sequence;`
+
+	migrate.Register(49, "Alter_Webhook_Add_Delivery_Sequence", upQuery, downQuery)
+}
@@ -1,24 +1,16 @@
-package main
+package migrations
 
 import (
-	"gopkg.in/go-pg/migrations.v5"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func init() {
-	migrations.Register(func(db migrations.DB) error {
-		upQuery := `CREATE TYPE ` + GetDatabaseName() +`.e_timezone_status AS ENUM (
+	upQuery := `CREATE TYPE e_timezone_status AS ENUM (
 		  		'active',
 		  		'inactive'
 		);`
 
-		_, err := db.Exec(upQuery)
+	downQuery := `DROP TYPE e_timezone_status;`
 
-		return err
-	}, func(db migrations.DB) error {
-		downQuery := `DROP TYPE ` + GetDatabaseName() +`.e_timezone_status;`
-
-		_, err := db.Exec(downQuery)
-
-		return err
-	})
+	migrate.Register(4, "Create_E_Timezone_Status", upQuery, downQuery)
 }
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// takes a token from every bucket when each has one and from none
+	// otherwise, in one statement so the rate limit middleware needs no tx
+	upQuery := `CREATE FUNCTION rate_limit_take(p_keys TEXT[], p_capacities DOUBLE PRECISION[], p_rates DOUBLE PRECISION[], p_now TIMESTAMP)
+		RETURNS TABLE (bucket_tokens DOUBLE PRECISION, bucket_allowed BOOLEAN) AS $$
+		DECLARE
+			n         INTEGER := array_length(p_keys, 1);
+			remaining DOUBLE PRECISION[] := array_fill(0::DOUBLE PRECISION, ARRAY[n]);
+			ok        BOOLEAN := true;
+			b         RECORD;
+		BEGIN
+			INSERT INTO rate_limit_bucket (key, tokens, updated_at, full_at)
+			SELECT k.key, k.capacity, p_now, p_now
+			FROM unnest(p_keys, p_capacities) AS k(key, capacity)
+			ON CONFLICT (key) DO NOTHING;
+
+			-- locked in key order so concurrent takes do not deadlock
+			PERFORM 1 FROM rate_limit_bucket WHERE key = ANY(p_keys) ORDER BY key FOR UPDATE;
+
+			FOR i IN 1..n LOOP
+				SELECT * INTO b FROM rate_limit_bucket WHERE key = p_keys[i];
+				remaining[i] := least(p_capacities[i], b.tokens + greatest(extract(epoch FROM p_now - b.updated_at), 0) * p_rates[i]);
+				ok := ok AND remaining[i] >= 1;
+			END LOOP;
+
+			FOR i IN 1..n LOOP
+				IF ok THEN
+					remaining[i] := remaining[i] - 1;
+				END IF;
+
+				UPDATE rate_limit_bucket SET
+					tokens     = remaining[i],
+					updated_at = p_now,
+					full_at    = p_now + make_interval(secs => (p_capacities[i] - remaining[i]) / p_rates[i])
+				WHERE key = p_keys[i];
+
+				bucket_tokens := remaining[i];
+				bucket_allowed := ok;
+				RETURN NEXT;
+			END LOOP;
+		END;
+		$$ LANGUAGE plpgsql;`
+
+	downQuery := `DROP FUNCTION rate_limit_take(TEXT[], DOUBLE PRECISION[], DOUBLE PRECISION[], TIMESTAMP);`
+
+	migrate.Register(50, "Create_Rate_Limit_Take", upQuery, downQuery)
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// rate_limit - the client's limit across all routes in place of
+	// APP_RATE_LIMIT, in the same form
+	upQuery := `ALTER TABLE api_client
+			ADD COLUMN rate_limit TEXT NULL;`
+
+	downQuery := `ALTER TABLE api_client DROP COLUMN rate_limit;`
+
+	migrate.Register(51, "Alter_Api_Client_Add_Rate_Limit", upQuery, downQuery)
+}
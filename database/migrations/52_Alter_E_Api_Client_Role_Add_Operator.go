@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	// operator - runs the service rather than a tenant's data, such as
+	// changing log levels. The type is replaced rather than altered with
+	// ADD VALUE, which cannot run in a transaction before Postgres 12.
+	upQuery := `CREATE TYPE e_api_client_role_new AS ENUM (
+		  		'admin',
+		  		'user',
+		  		'operator'
+		);
+
+		ALTER TABLE api_client
+			ALTER COLUMN role DROP DEFAULT,
+			ALTER COLUMN role TYPE e_api_client_role_new USING role::TEXT::e_api_client_role_new,
+			ALTER COLUMN role SET DEFAULT 'user';
+
+		DROP TYPE e_api_client_role;
+		ALTER TYPE e_api_client_role_new RENAME TO e_api_client_role;`
+
+	// fails while operator API clients exist
+	downQuery := `CREATE TYPE e_api_client_role_old AS ENUM (
+		  		'admin',
+		  		'user'
+		);
+
+		ALTER TABLE api_client
+			ALTER COLUMN role DROP DEFAULT,
+			ALTER COLUMN role TYPE e_api_client_role_old USING role::TEXT::e_api_client_role_old,
+			ALTER COLUMN role SET DEFAULT 'user';
+
+		DROP TYPE e_api_client_role;
+		ALTER TYPE e_api_client_role_old RENAME TO e_api_client_role;`
+
+	migrate.Register(52, "Alter_E_Api_Client_Role_Add_Operator", upQuery, downQuery)
+}
@@ -1,28 +1,20 @@
-package main
+package migrations
 
 import (
-	"gopkg.in/go-pg/migrations.v5"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func init() {
-	migrations.Register(func(db migrations.DB) error {
-		upQuery := `CREATE TABLE ` + GetDatabaseName() +`.timezone (
+	upQuery := `CREATE TABLE timezone (
 		  			id              TEXT                NOT NULL,
-		  			status          ` + GetDatabaseName() +`.e_timezone_status   NOT NULL DEFAULT 'active',
+		  			status          e_timezone_status   NOT NULL DEFAULT 'active',
 					created_at      TIMESTAMP           NOT NULL DEFAULT now(),
 					updated_at      TIMESTAMP           NULL,
 					deleted_at      TIMESTAMP           NULL,
 		  			CONSTRAINT 		timezone_pk PRIMARY KEY (id)
 		);`
 
-		_, err := db.Exec(upQuery)
+	downQuery := `DROP TABLE timezone;`
 
-		return err
-	}, func(db migrations.DB) error {
-		downQuery := `DROP TABLE ` + GetDatabaseName() +`.timezone;`
-
-		_, err := db.Exec(downQuery)
-
-		return err
-	})
+	migrate.Register(5, "Create_Timezone", upQuery, downQuery)
 }
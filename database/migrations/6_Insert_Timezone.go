@@ -1,12 +1,11 @@
-package main
+package migrations
 
 import (
-	"gopkg.in/go-pg/migrations.v5"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func init() {
-	migrations.Register(func(db migrations.DB) error {
-		upQuery := `INSERT INTO ` + GetDatabaseName() +`.timezone (id, status) VALUES
+	upQuery := `INSERT INTO timezone (id, status) VALUES
 ('Turkey',		                                'active'),
 ('Iran',		                                'active'),
 ('GMT+0',		                                'active'),
@@ -1222,14 +1221,7 @@ func init() {
 ('HST',		                                    'active'),
 ('NZ',		                                    'active');`
 
-		_, err := db.Exec(upQuery)
+	downQuery := `TRUNCATE TABLE timezone;`
 
-		return err
-	}, func(db migrations.DB) error {
-		downQuery := `TRUNCATE TABLE ` + GetDatabaseName() +`.timezone;`
-
-		_, err := db.Exec(downQuery)
-
-		return err
-	})
+	migrate.Register(6, "Insert_Timezone", upQuery, downQuery)
 }
@@ -1,25 +1,17 @@
-package main
+package migrations
 
 import (
-	"gopkg.in/go-pg/migrations.v5"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func init() {
-	migrations.Register(func(db migrations.DB) error {
-		upQuery := `CREATE TYPE ` + GetDatabaseName() +`.e_merchant_status AS ENUM (
+	upQuery := `CREATE TYPE e_merchant_status AS ENUM (
 		  		'active',
 		  		'inactive',
 		  		'terminated'
 		);`
 
-		_, err := db.Exec(upQuery)
+	downQuery := `DROP TYPE e_merchant_status;`
 
-		return err
-	}, func(db migrations.DB) error {
-		downQuery := `DROP TYPE ` + GetDatabaseName() +`.e_merchant_status;`
-
-		_, err := db.Exec(downQuery)
-
-		return err
-	})
+	migrate.Register(7, "Create_E_Merchant_Status", upQuery, downQuery)
 }
@@ -1,19 +1,18 @@
-package main
+package migrations
 
 import (
-	"gopkg.in/go-pg/migrations.v5"
+	"github.com/vegh1010/test/pkg/migrate"
 )
 
 func init() {
-	migrations.Register(func(db migrations.DB) error {
-		upQuery := `CREATE TABLE ` + GetDatabaseName() +`.merchant (
+	upQuery := `CREATE TABLE merchant (
 					id            	UUID              NOT NULL DEFAULT gen_random_uuid(),
 		  			name          	TEXT              NOT NULL,
 		  			short_name    	TEXT              NOT NULL,
 		  			dba_name      	TEXT              NOT NULL,
 		  			country_id    	VARCHAR(2)        NOT NULL,
 		  			timezone_id   	TEXT              NOT NULL,
-		  			status        	` + GetDatabaseName() +`.e_merchant_status NOT NULL DEFAULT 'active',
+		  			status        	e_merchant_status NOT NULL DEFAULT 'active',
 					created_at    	TIMESTAMP         NOT NULL DEFAULT now(),
 					updated_at    	TIMESTAMP         NULL,
 					deleted_at    	TIMESTAMP         NULL,
@@ -22,14 +21,7 @@ func init() {
 		  			CONSTRAINT 		merchant_timezone_fk FOREIGN KEY (timezone_id) REFERENCES timezone (id)
 		);`
 
-		_, err := db.Exec(upQuery)
+	downQuery := `DROP TABLE merchant;`
 
-		return err
-	}, func(db migrations.DB) error {
-		downQuery := `DROP TABLE ` + GetDatabaseName() +`.merchant;`
-
-		_, err := db.Exec(downQuery)
-
-		return err
-	})
+	migrate.Register(8, "Create_Merchant", upQuery, downQuery)
 }
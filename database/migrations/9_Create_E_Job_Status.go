@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := `CREATE TYPE e_job_status AS ENUM (
+		  		'pending',
+		  		'completed',
+		  		'dead'
+		);`
+
+	downQuery := `DROP TYPE e_job_status;`
+
+	migrate.Register(9, "Create_E_Job_Status", upQuery, downQuery)
+}
@@ -0,0 +1,4 @@
+// Package migrations registers the database schema migrations, run with
+// test-api migrate. Migrations are numbered, one per file, and must not be
+// edited once applied, add a new migration instead.
+package migrations
@@ -0,0 +1,107 @@
+package migrations_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	_ "github.com/vegh1010/test/database/migrations"
+	_ "github.com/vegh1010/test/database/seeds"
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+var snapshot = filepath.Join("..", "schema.json")
+
+func TestSnapshotVersion(t *testing.T) {
+
+	s, err := migrate.ReadSchema(snapshot)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r := migrate.NewRunner(zerolog.Nop(), nil)
+	assert.Equal(t, r.Latest(), s.Version, "%s is not at the latest migration, run migrate up and migrate snapshot", snapshot)
+}
+
+// TestSnapshot migrates the empty database at APP_TEST_DATABASE_URL and
+// compares its schema with the snapshot
+func TestSnapshot(t *testing.T) {
+
+	url := os.Getenv("APP_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("No APP_TEST_DATABASE_URL")
+	}
+
+	db, err := sqlx.Connect("postgres", url)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	r := migrate.NewRunner(zerolog.Nop(), db)
+	if !assert.NoError(t, r.Up()) {
+		return
+	}
+
+	live, err := r.LiveSchema()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	expected, err := migrate.ReadSchema(snapshot)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, d := range migrate.DiffSchema(expected, live) {
+		t.Errorf("%s, run migrate snapshot to update %s", d, snapshot)
+	}
+}
+
+// TestTenantIdAuditRows adds tenant_id to a database at APP_TEST_DATABASE_URL
+// that already has audit rows, which are append only
+func TestTenantIdAuditRows(t *testing.T) {
+
+	url := os.Getenv("APP_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("No APP_TEST_DATABASE_URL")
+	}
+
+	db, err := sqlx.Connect("postgres", url)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	r := migrate.NewRunner(zerolog.Nop(), db)
+	if !assert.NoError(t, r.To(39)) {
+		return
+	}
+	// left at the latest migration for other tests
+	defer func() {
+		assert.NoError(t, r.Up())
+	}()
+
+	var id string
+	err = db.Get(&id, `
+INSERT INTO audit_log (entity_type, entity_id, operation, actor_id, actor_name)
+VALUES ('merchant', 'test', 'create', 'test', 'test')
+RETURNING id`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.NoError(t, r.To(40)) {
+		return
+	}
+
+	var tenantID string
+	err = db.Get(&tenantID, `SELECT tenant_id FROM audit_log WHERE id = $1`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", tenantID)
+}
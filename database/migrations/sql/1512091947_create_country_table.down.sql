@@ -1,2 +0,0 @@
-DROP TABLE country;
-DROP TYPE e_country_status;
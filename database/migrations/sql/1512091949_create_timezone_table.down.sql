@@ -1,2 +0,0 @@
-DROP TABLE timezone;
-DROP TYPE e_timezone_status;
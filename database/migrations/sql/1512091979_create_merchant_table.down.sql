@@ -1,2 +0,0 @@
-DROP TABLE merchant;
-DROP TYPE e_merchant_status;
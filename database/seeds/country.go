@@ -1,268 +1,262 @@
-CREATE TYPE e_country_status AS ENUM (
-  'active', 
-  'inactive' 
-);
+package seeds
 
-CREATE TABLE country (
-  id              VARCHAR(2)        NOT NULL,
-  name            TEXT              NOT NULL,
-  alpha2_code     VARCHAR(2)        NOT NULL,
-  alpha3_code     VARCHAR(3)        NOT NULL,
-  numeric_code    VARCHAR(3)        NOT NULL,
-  status          e_country_status  NOT NULL DEFAULT 'active',
-	created_at      TIMESTAMP         NOT NULL DEFAULT now(),
-	updated_at      TIMESTAMP         NULL,
-	deleted_at      TIMESTAMP         NULL,
-  CONSTRAINT country_pk PRIMARY KEY (id)
-);
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
 
--- data
-INSERT INTO country (id, name, alpha2_code, alpha3_code, numeric_code, status) VALUES
-('AF',    'Afghanistan',                                  'AF',   	'AFG',	'004',    'active'),
-('AL',    'Albania',                                      'AL',   	'ALB',	'008',    'active'),
-('AQ',    'Antarctica',                                   'AQ',   	'ATA',	'010',    'active'),
-('DZ',    'Algeria',                                      'DZ',   	'DZA',	'012',    'active'),
-('AS',    'American Samoa',                               'AS',   	'ASM',	'016',    'active'),
-('AD',    'Andorra',                                      'AD',   	'AND',	'020',    'active'),
-('AO',    'Angola',                                       'AO',   	'AGO',	'024',    'active'),
-('AG',    'Antigua and Barbuda',                          'AG',   	'ATG',	'028',    'active'),
-('AZ',    'Azerbaijan',                                   'AZ',   	'AZE',	'031',    'active'),
-('AR',    'Argentina',                                    'AR',   	'ARG',	'032',    'active'),
-('AU',    'Australia',                                    'AU',   	'AUS',	'036',    'active'),
-('AT',    'Austria',                                      'AT',   	'AUT',	'040',    'active'),
-('BS',    'Bahamas',                                      'BS',   	'BHS',	'044',    'active'),
-('BH',    'Bahrain',                                      'BH',   	'BHR',	'048',    'active'),
-('BD',    'Bangladesh',                                   'BD',   	'BGD',	'050',    'active'),
-('AM',    'Armenia',                                      'AM',   	'ARM',	'051',    'active'),
-('BB',    'Barbados',                                     'BB',   	'BRB',	'052',    'active'),
-('BE',    'Belgium',                                      'BE',   	'BEL',	'056',    'active'),
-('BM',    'Bermuda',                                      'BM',   	'BMU',	'060',    'active'),
-('BT',    'Bhutan',                                       'BT',   	'BTN',	'064',    'active'),
-('BO',    'Bolivia',                                      'BO',   	'BOL',	'068',    'active'),
-('BA',    'Bosnia and Herzegovina',                       'BA',   	'BIH',	'070',    'active'),
-('BW',    'Botswana',                                     'BW',   	'BWA',	'072',    'active'),
-('BV',    'Bouvet Island',                                'BV',   	'BVT',	'074',    'active'),
-('BR',    'Brazil',                                       'BR',   	'BRA',	'076',    'active'),
-('BZ',    'Belize',                                       'BZ',   	'BLZ',	'084',    'active'),
-('IO',    'British Indian Ocean Territory',               'IO',   	'IOT',	'086',    'active'),
-('SB',    'Solomon Islands',                              'SB',   	'SLB',	'090',    'active'),
-('VG',    'British Virgin Islands',                       'VG',   	'VGB',	'092',    'active'),
-('BN',    'Brunei Darussalam',                            'BN',   	'BRN',	'096',    'active'),
-('BG',    'Bulgaria',                                     'BG',   	'BGR',	'100',    'active'),
-('MM',    'Myanmar',                                      'MM',   	'MMR',	'104',    'active'),
-('BI',    'Burundi',                                      'BI',   	'BDI',	'108',    'active'),
-('BY',    'Belarus',                                      'BY',   	'BLR',	'112',    'active'),
-('KH',    'Cambodia',                                     'KH',   	'KHM',	'116',    'active'),
-('CM',    'Cameroon',                                     'CM',   	'CMR',	'120',    'active'),
-('CA',    'Canada',                                       'CA',   	'CAN',	'124',    'active'),
-('CV',    'Cape Verde',                                   'CV',   	'CPV',	'132',    'active'),
-('KY',    'Cayman Islands',                               'KY',   	'CYM',	'136',    'active'),
-('CF',    'Central African Republic',                     'CF',   	'CAF',	'140',    'active'),
-('LK',    'Sri Lanka',                                    'LK',   	'LKA',	'144',    'active'),
-('TD',    'Chad',                                         'TD',   	'TCD',	'148',    'active'),
-('CL',    'Chile',                                        'CL',   	'CHL',	'152',    'active'),
-('CN',    'China',                                        'CN',   	'CHN',	'156',    'active'),
-('TW',    'Taiwan, Republic of China',                    'TW',   	'TWN',	'158',    'active'),
-('CX',    'Christmas Island',                             'CX',   	'CXR',	'162',    'active'),
-('CC',    'Cocos (Keeling) Islands',                      'CC',   	'CCK',	'166',    'active'),
-('CO',    'Colombia',                                     'CO',   	'COL',	'170',    'active'),
-('KM',    'Comoros',                                      'KM',   	'COM',	'174',    'active'),
-('YT',    'Mayotte',                                      'YT',   	'MYT',	'175',    'active'),
-('CG',    'Congo (Brazzaville)',                          'CG',   	'COG',	'178',    'active'),
-('CD',    'Congo, (Kinshasa)',                            'CD',   	'COD',	'180',    'active'),
-('CK',    'Cook Islands',                                 'CK',   	'COK',	'184',    'active'),
-('CR',    'Costa Rica',                                   'CR',   	'CRI',	'188',    'active'),
-('HR',    'Croatia',                                      'HR',   	'HRV',	'191',    'active'),
-('CU',    'Cuba',                                         'CU',   	'CUB',	'192',    'active'),
-('CY',    'Cyprus',                                       'CY',   	'CYP',	'196',    'active'),
-('CZ',    'Czech Republic',                               'CZ',   	'CZE',	'203',    'active'),
-('BJ',    'Benin',                                        'BJ',   	'BEN',	'204',    'active'),
-('DK',    'Denmark',                                      'DK',   	'DNK',	'208',    'active'),
-('DM',    'Dominica',                                     'DM',   	'DMA',	'212',    'active'),
-('DO',    'Dominican Republic',                           'DO',   	'DOM',	'214',    'active'),
-('EC',    'Ecuador',                                      'EC',   	'ECU',	'218',    'active'),
-('SV',    'El Salvador',                                  'SV',   	'SLV',	'222',    'active'),
-('GQ',    'Equatorial Guinea',                            'GQ',   	'GNQ',	'226',    'active'),
-('ET',    'Ethiopia',                                     'ET',   	'ETH',	'231',    'active'),
-('ER',    'Eritrea',                                      'ER',   	'ERI',	'232',    'active'),
-('EE',    'Estonia',                                      'EE',   	'EST',	'233',    'active'),
-('FO',    'Faroe Islands',                                'FO',   	'FRO',	'234',    'active'),
-('FK',    'Falkland Islands (Malvinas)',                  'FK',   	'FLK',	'238',    'active'),
-('GS',    'South Georgia and the South Sandwich Islands', 'GS',   	'SGS',	'239',    'active'),
-('FJ',    'Fiji',                                         'FJ',   	'FJI',	'242',    'active'),
-('FI',    'Finland',                                      'FI',   	'FIN',	'246',    'active'),
-('AX',    'ALA	Aland Islands',                           'AX',  	'ALA',	'248',    'active'),
-('FR',    'France',                                       'FR',   	'FRA',	'250',    'active'),
-('GF',    'French Guiana',                                'GF',   	'GUF',	'254',    'active'),
-('PF',    'French Polynesia',                             'PF',   	'PYF',	'258',    'active'),
-('TF',    'French Southern Territories',                  'TF',   	'ATF',	'260',    'active'),
-('DJ',    'Djibouti',                                     'DJ',   	'DJI',	'262',    'active'),
-('GA',    'Gabon',                                        'GA',   	'GAB',	'266',    'active'),
-('GE',    'Georgia',                                      'GE',   	'GEO',	'268',    'active'),
-('GM',    'Gambia',                                       'GM',   	'GMB',	'270',    'active'),
-('PS',    'Palestinian Territory',                        'PS',   	'PSE',	'275',    'active'),
-('DE',    'Germany',                                      'DE',   	'DEU',	'276',    'active'),
-('GH',    'Ghana',                                        'GH',   	'GHA',	'288',    'active'),
-('GI',    'Gibraltar',                                    'GI',   	'GIB',	'292',    'active'),
-('KI',    'Kiribati',                                     'KI',   	'KIR',	'296',    'active'),
-('GR',    'Greece',                                       'GR',   	'GRC',	'300',    'active'),
-('GL',    'Greenland',                                    'GL',   	'GRL',	'304',    'active'),
-('GD',    'Grenada',                                      'GD',   	'GRD',	'308',    'active'),
-('GP',    'Guadeloupe',                                   'GP',   	'GLP',	'312',    'active'),
-('GU',    'Guam',                                         'GU',   	'GUM',	'316',    'active'),
-('GT',    'Guatemala',                                    'GT',   	'GTM',	'320',    'active'),
-('GN',    'Guinea',                                       'GN',   	'GIN',	'324',    'active'),
-('GY',    'Guyana',                                       'GY',   	'GUY',	'328',    'active'),
-('HT',    'Haiti',                                        'HT',   	'HTI',	'332',    'active'),
-('HM',    'Heard and Mcdonald Islands',                   'HM',   	'HMD',	'334',    'active'),
-('VA',    'Holy See (Vatican City State)',                'VA',   	'VAT',	'336',    'active'),
-('HN',    'Honduras',                                     'HN',   	'HND',	'340',    'active'),
-('HK',    'Hong Kong, SAR China',                         'HK',   	'HKG',	'344',    'active'),
-('HU',    'Hungary',                                      'HU',   	'HUN',	'348',    'active'),
-('IS',    'Iceland',                                      'IS',   	'ISL',	'352',    'active'),
-('IN',    'India',                                        'IN',   	'IND',	'356',    'active'),
-('ID',    'Indonesia',                                    'ID',   	'IDN',	'360',    'active'),
-('IR',    'Iran, Islamic Republic of',                    'IR',   	'IRN',	'364',    'active'),
-('IQ',    'Iraq',                                         'IQ',   	'IRQ',	'368',    'active'),
-('IE',    'Ireland',                                      'IE',   	'IRL',	'372',    'active'),
-('IL',    'Israel',                                       'IL',   	'ISR',	'376',    'active'),
-('IT',    'Italy',                                        'IT',   	'ITA',	'380',    'active'),
-('CI',    'Côte d''Ivoire',                                'CI',   	'CIV',	'384',    'active'),
-('JM',    'Jamaica',                                      'JM',   	'JAM',	'388',    'active'),
-('JP',    'Japan',                                        'JP',   	'JPN',	'392',    'active'),
-('KZ',    'Kazakhstan',                                   'KZ',   	'KAZ',	'398',    'active'),
-('JO',    'Jordan',                                       'JO',   	'JOR',	'400',    'active'),
-('KE',    'Kenya',                                        'KE',   	'KEN',	'404',    'active'),
-('KP',    'Korea (North)',                                'KP',   	'PRK',	'408',    'active'),
-('KR',    'Korea (South)',                                'KR',   	'KOR',	'410',    'active'),
-('KW',    'Kuwait',                                       'KW',   	'KWT',	'414',    'active'),
-('KG',    'Kyrgyzstan',                                   'KG',   	'KGZ',	'417',    'active'),
-('LA',    'Lao PDR',                                      'LA',   	'LAO',	'418',    'active'),
-('LB',    'Lebanon',                                      'LB',   	'LBN',	'422',    'active'),
-('LS',    'Lesotho',                                      'LS',   	'LSO',	'426',    'active'),
-('LV',    'Latvia',                                       'LV',   	'LVA',	'428',    'active'),
-('LR',    'Liberia',                                      'LR',   	'LBR',	'430',    'active'),
-('LY',    'Libya',                                        'LY',   	'LBY',	'434',    'active'),
-('LI',    'Liechtenstein',                                'LI',   	'LIE',	'438',    'active'),
-('LT',    'Lithuania',                                    'LT',   	'LTU',	'440',    'active'),
-('LU',    'Luxembourg',                                   'LU',   	'LUX',	'442',    'active'),
-('MO',    'Macao, SAR China',                             'MO',   	'MAC',	'446',    'active'),
-('MG',    'Madagascar',                                   'MG',   	'MDG',	'450',    'active'),
-('MW',    'Malawi',                                       'MW',   	'MWI',	'454',    'active'),
-('MY',    'Malaysia',                                     'MY',   	'MYS',	'458',    'active'),
-('MV',    'Maldives',                                     'MV',   	'MDV',	'462',    'active'),
-('ML',    'Mali',                                         'ML',   	'MLI',	'466',    'active'),
-('MT',    'Malta',                                        'MT',   	'MLT',	'470',    'active'),
-('MQ',    'Martinique',                                   'MQ',   	'MTQ',	'474',    'active'),
-('MR',    'Mauritania',                                   'MR',   	'MRT',	'478',    'active'),
-('MU',    'Mauritius',                                    'MU',   	'MUS',	'480',    'active'),
-('MX',    'Mexico',                                       'MX',   	'MEX',	'484',    'active'),
-('MC',    'Monaco',                                       'MC',   	'MCO',	'492',    'active'),
-('MN',    'Mongolia',                                     'MN',   	'MNG',	'496',    'active'),
-('MD',    'Moldova',                                      'MD',   	'MDA',	'498',    'active'),
-('ME',    'Montenegro',                                   'ME',   	'MNE',	'499',    'active'),
-('MS',    'Montserrat',                                   'MS',   	'MSR',	'500',    'active'),
-('MA',    'Morocco',                                      'MA',   	'MAR',	'504',    'active'),
-('MZ',    'Mozambique',                                   'MZ',   	'MOZ',	'508',    'active'),
-('OM',    'Oman',                                         'OM',   	'OMN',	'512',    'active'),
-('NA',    'Namibia',                                      'NA',   	'NAM',	'516',    'active'),
-('NR',    'Nauru',                                        'NR',   	'NRU',	'520',    'active'),
-('NP',    'Nepal',                                        'NP',   	'NPL',	'524',    'active'),
-('NL',    'Netherlands',                                  'NL',   	'NLD',	'528',    'active'),
-('AN',    'Netherlands Antilles',                         'AN',   	'ANT',	'530',    'active'),
-('AW',    'Aruba',                                        'AW',   	'ABW',	'533',    'active'),
-('NC',    'New Caledonia',                                'NC',   	'NCL',	'540',    'active'),
-('VU',    'Vanuatu',                                      'VU',   	'VUT',	'548',    'active'),
-('NZ',    'New Zealand',                                  'NZ',   	'NZL',	'554',    'active'),
-('NI',    'Nicaragua',                                    'NI',   	'NIC',	'558',    'active'),
-('NE',    'Niger',                                        'NE',   	'NER',	'562',    'active'),
-('NG',    'Nigeria',                                      'NG',   	'NGA',	'566',    'active'),
-('NU',    'Niue',                                         'NU',   	'NIU',	'570',    'active'),
-('NF',    'Norfolk Island',                               'NF',   	'NFK',	'574',    'active'),
-('NO',    'Norway',                                       'NO',   	'NOR',	'578',    'active'),
-('MP',    'Northern Mariana Islands',                     'MP',   	'MNP',	'580',    'active'),
-('UM',    'US Minor Outlying Islands',                    'UM',   	'UMI',	'581',    'active'),
-('FM',    'Micronesia, Federated States of',              'FM',   	'FSM',	'583',    'active'),
-('MH',    'Marshall Islands',                             'MH',   	'MHL',	'584',    'active'),
-('PW',    'Palau',                                        'PW',   	'PLW',	'585',    'active'),
-('PK',    'Pakistan',                                     'PK',   	'PAK',	'586',    'active'),
-('PA',    'Panama',                                       'PA',   	'PAN',	'591',    'active'),
-('PG',    'Papua New Guinea',                             'PG',   	'PNG',	'598',    'active'),
-('PY',    'Paraguay',                                     'PY',   	'PRY',	'600',    'active'),
-('PE',    'Peru',                                         'PE',   	'PER',	'604',    'active'),
-('PH',    'Philippines',                                  'PH',   	'PHL',	'608',    'active'),
-('PN',    'Pitcairn',                                     'PN',   	'PCN',	'612',    'active'),
-('PL',    'Poland',                                       'PL',   	'POL',	'616',    'active'),
-('PT',    'Portugal',                                     'PT',   	'PRT',	'620',    'active'),
-('GW',    'Guinea-Bissau',                                'GW',   	'GNB',	'624',    'active'),
-('TL',    'Timor-Leste',                                  'TL',   	'TLS',	'626',    'active'),
-('PR',    'Puerto Rico',                                  'PR',   	'PRI',	'630',    'active'),
-('QA',    'Qatar',                                        'QA',   	'QAT',	'634',    'active'),
-('RE',    'Réunion',                                      'RE',   	'REU',	'638',    'active'),
-('RO',    'Romania',                                      'RO',   	'ROU',	'642',    'active'),
-('RU',    'Russian Federation',                           'RU',   	'RUS',	'643',    'active'),
-('RW',    'Rwanda',                                       'RW',   	'RWA',	'646',    'active'),
-('BL',    'Saint-Barthélemy',                             'BL',   	'BLM',	'652',    'active'),
-('SH',    'Saint Helena',                                 'SH',   	'SHN',	'654',    'active'),
-('KN',    'Saint Kitts and Nevis',                        'KN',   	'KNA',	'659',    'active'),
-('AI',    'Anguilla',                                     'AI',   	'AIA',	'660',    'active'),
-('LC',    'Saint Lucia',                                  'LC',   	'LCA',	'662',    'active'),
-('MF',    'Saint-Martin (French part)',                   'MF',   	'MAF',	'663',    'active'),
-('PM',    'Saint Pierre and Miquelon',                    'PM',   	'SPM',	'666',    'active'),
-('VC',    'Saint Vincent and Grenadines',                 'VC',   	'VCT',	'670',    'active'),
-('SM',    'San Marino',                                   'SM',   	'SMR',	'674',    'active'),
-('ST',    'Sao Tome and Principe',                        'ST',   	'STP',	'678',    'active'),
-('SA',    'Saudi Arabia',                                 'SA',   	'SAU',	'682',    'active'),
-('SN',    'Senegal',                                      'SN',   	'SEN',	'686',    'active'),
-('RS',    'Serbia',                                       'RS',   	'SRB',	'688',    'active'),
-('SC',    'Seychelles',                                   'SC',   	'SYC',	'690',    'active'),
-('SL',    'Sierra Leone',                                 'SL',   	'SLE',	'694',    'active'),
-('SG',    'Singapore',                                    'SG',   	'SGP',	'702',    'active'),
-('SK',    'Slovakia',                                     'SK',   	'SVK',	'703',    'active'),
-('VN',    'Viet Nam',                                     'VN',   	'VNM',	'704',    'active'),
-('SI',    'Slovenia',                                     'SI',   	'SVN',	'705',    'active'),
-('SO',    'Somalia',                                      'SO',   	'SOM',	'706',    'active'),
-('ZA',    'South Africa',                                 'ZA',   	'ZAF',	'710',    'active'),
-('ZW',    'Zimbabwe',                                     'ZW',   	'ZWE',	'716',    'active'),
-('ES',    'Spain',                                        'ES',   	'ESP',	'724',    'active'),
-('SS',    'South Sudan',                                  'SS',   	'SSD',	'728',    'active'),
-('EH',    'Western Sahara',                               'EH',   	'ESH',	'732',    'active'),
-('SD',    'Sudan',                                        'SD',   	'SDN',	'736',    'active'),
-('SR',    'Suriname',                                     'SR',   	'SUR',	'740',    'active'),
-('SJ',    'Svalbard and Jan Mayen Islands',               'SJ',   	'SJM',	'744',    'active'),
-('SZ',    'Swaziland',                                    'SZ',   	'SWZ',	'748',    'active'),
-('SE',    'Sweden',                                       'SE',   	'SWE',	'752',    'active'),
-('CH',    'Switzerland',                                  'CH',   	'CHE',	'756',    'active'),
-('SY',    'Syrian Arab Republic (Syria)',                 'SY',   	'SYR',	'760',    'active'),
-('TJ',    'Tajikistan',                                   'TJ',   	'TJK',	'762',    'active'),
-('TH',    'Thailand',                                     'TH',   	'THA',	'764',    'active'),
-('TG',    'Togo',                                         'TG',   	'TGO',	'768',    'active'),
-('TK',    'Tokelau',                                      'TK',   	'TKL',	'772',    'active'),
-('TO',    'Tonga',                                        'TO',   	'TON',	'776',    'active'),
-('TT',    'Trinidad and Tobago',                          'TT',   	'TTO',	'780',    'active'),
-('AE',    'United Arab Emirates',                         'AE',   	'ARE',	'784',    'active'),
-('TN',    'Tunisia',                                      'TN',   	'TUN',	'788',    'active'),
-('TR',    'Turkey',                                       'TR',   	'TUR',	'792',    'active'),
-('TM',    'Turkmenistan',                                 'TM',   	'TKM',	'795',    'active'),
-('TC',    'Turks and Caicos Islands',                     'TC',   	'TCA',	'796',    'active'),
-('TV',    'Tuvalu',                                       'TV',   	'TUV',	'798',    'active'),
-('UG',    'Uganda',                                       'UG',   	'UGA',	'800',    'active'),
-('UA',    'Ukraine',                                      'UA',   	'UKR',	'804',    'active'),
-('MK',    'Macedonia, Republic of',                       'MK',   	'MKD',	'807',    'active'),
-('EG',    'Egypt',                                        'EG',   	'EGY',	'818',    'active'),
-('GB',    'United Kingdom',                               'GB',   	'GBR',	'826',    'active'),
-('GG',    'Guernsey',                                     'GG',   	'GGY',	'831',    'active'),
-('JE',    'Jersey',                                       'JE',   	'JEY',	'832',    'active'),
-('IM',    'Isle of Man',                                  'IM',   	'IMN',	'833',    'active'),
-('TZ',    'Tanzania, United Republic of',                 'TZ',   	'TZA',	'834',    'active'),
-('US',    'United States of America',                     'US',   	'USA',	'840',    'active'),
-('VI',    'Virgin Islands, US',                           'VI',   	'VIR',	'850',    'active'),
-('BF',    'Burkina Faso',                                 'BF',   	'BFA',	'854',    'active'),
-('UY',    'Uruguay',                                      'UY',   	'URY',	'858',    'active'),
-('UZ',    'Uzbekistan',                                   'UZ',   	'UZB',	'860',    'active'),
-('VE',    'Venezuela (Bolivarian Republic)',              'VE',   	'VEN',	'862',    'active'),
-('WF',    'Wallis and Futuna Islands',                    'WF',   	'WLF',	'876',    'active'),
-('WS',    'Samoa',                                        'WS',   	'WSM',	'882',    'active'),
-('YE',    'Yemen',                                        'YE',   	'YEM',	'887',    'active'),
-('ZM',    'Zambia',                                       'ZM',   	'ZMB',	'894',    'active');
+// countrySQL - ISO 3166-1 countries and their currencies present before
+// countries were imported from database/data/iso3166.json. The import
+// owns the table, so existing countries are left alone.
+var countrySQL = `INSERT INTO country (id, name, alpha2_code, alpha3_code, numeric_code, status, currency_id) VALUES
+('AF',    'Afghanistan',                                  'AF',   	'AFG',	'004',    'active',    'AFN'),
+('AL',    'Albania',                                      'AL',   	'ALB',	'008',    'active',    'ALL'),
+('AQ',    'Antarctica',                                   'AQ',   	'ATA',	'010',    'active',    NULL),
+('DZ',    'Algeria',                                      'DZ',   	'DZA',	'012',    'active',    'DZD'),
+('AS',    'American Samoa',                               'AS',   	'ASM',	'016',    'active',    'USD'),
+('AD',    'Andorra',                                      'AD',   	'AND',	'020',    'active',    'EUR'),
+('AO',    'Angola',                                       'AO',   	'AGO',	'024',    'active',    'AOA'),
+('AG',    'Antigua and Barbuda',                          'AG',   	'ATG',	'028',    'active',    'XCD'),
+('AZ',    'Azerbaijan',                                   'AZ',   	'AZE',	'031',    'active',    'AZN'),
+('AR',    'Argentina',                                    'AR',   	'ARG',	'032',    'active',    'ARS'),
+('AU',    'Australia',                                    'AU',   	'AUS',	'036',    'active',    'AUD'),
+('AT',    'Austria',                                      'AT',   	'AUT',	'040',    'active',    'EUR'),
+('BS',    'Bahamas',                                      'BS',   	'BHS',	'044',    'active',    'BSD'),
+('BH',    'Bahrain',                                      'BH',   	'BHR',	'048',    'active',    'BHD'),
+('BD',    'Bangladesh',                                   'BD',   	'BGD',	'050',    'active',    'BDT'),
+('AM',    'Armenia',                                      'AM',   	'ARM',	'051',    'active',    'AMD'),
+('BB',    'Barbados',                                     'BB',   	'BRB',	'052',    'active',    'BBD'),
+('BE',    'Belgium',                                      'BE',   	'BEL',	'056',    'active',    'EUR'),
+('BM',    'Bermuda',                                      'BM',   	'BMU',	'060',    'active',    'BMD'),
+('BT',    'Bhutan',                                       'BT',   	'BTN',	'064',    'active',    'BTN'),
+('BO',    'Bolivia',                                      'BO',   	'BOL',	'068',    'active',    'BOB'),
+('BA',    'Bosnia and Herzegovina',                       'BA',   	'BIH',	'070',    'active',    'BAM'),
+('BW',    'Botswana',                                     'BW',   	'BWA',	'072',    'active',    'BWP'),
+('BV',    'Bouvet Island',                                'BV',   	'BVT',	'074',    'active',    'NOK'),
+('BR',    'Brazil',                                       'BR',   	'BRA',	'076',    'active',    'BRL'),
+('BZ',    'Belize',                                       'BZ',   	'BLZ',	'084',    'active',    'BZD'),
+('IO',    'British Indian Ocean Territory',               'IO',   	'IOT',	'086',    'active',    'USD'),
+('SB',    'Solomon Islands',                              'SB',   	'SLB',	'090',    'active',    'SBD'),
+('VG',    'British Virgin Islands',                       'VG',   	'VGB',	'092',    'active',    'USD'),
+('BN',    'Brunei Darussalam',                            'BN',   	'BRN',	'096',    'active',    'BND'),
+('BG',    'Bulgaria',                                     'BG',   	'BGR',	'100',    'active',    'EUR'),
+('MM',    'Myanmar',                                      'MM',   	'MMR',	'104',    'active',    'MMK'),
+('BI',    'Burundi',                                      'BI',   	'BDI',	'108',    'active',    'BIF'),
+('BY',    'Belarus',                                      'BY',   	'BLR',	'112',    'active',    'BYN'),
+('KH',    'Cambodia',                                     'KH',   	'KHM',	'116',    'active',    'KHR'),
+('CM',    'Cameroon',                                     'CM',   	'CMR',	'120',    'active',    'XAF'),
+('CA',    'Canada',                                       'CA',   	'CAN',	'124',    'active',    'CAD'),
+('CV',    'Cape Verde',                                   'CV',   	'CPV',	'132',    'active',    'CVE'),
+('KY',    'Cayman Islands',                               'KY',   	'CYM',	'136',    'active',    'KYD'),
+('CF',    'Central African Republic',                     'CF',   	'CAF',	'140',    'active',    'XAF'),
+('LK',    'Sri Lanka',                                    'LK',   	'LKA',	'144',    'active',    'LKR'),
+('TD',    'Chad',                                         'TD',   	'TCD',	'148',    'active',    'XAF'),
+('CL',    'Chile',                                        'CL',   	'CHL',	'152',    'active',    'CLP'),
+('CN',    'China',                                        'CN',   	'CHN',	'156',    'active',    'CNY'),
+('TW',    'Taiwan, Republic of China',                    'TW',   	'TWN',	'158',    'active',    'TWD'),
+('CX',    'Christmas Island',                             'CX',   	'CXR',	'162',    'active',    'AUD'),
+('CC',    'Cocos (Keeling) Islands',                      'CC',   	'CCK',	'166',    'active',    'AUD'),
+('CO',    'Colombia',                                     'CO',   	'COL',	'170',    'active',    'COP'),
+('KM',    'Comoros',                                      'KM',   	'COM',	'174',    'active',    'KMF'),
+('YT',    'Mayotte',                                      'YT',   	'MYT',	'175',    'active',    'EUR'),
+('CG',    'Congo (Brazzaville)',                          'CG',   	'COG',	'178',    'active',    'XAF'),
+('CD',    'Congo, (Kinshasa)',                            'CD',   	'COD',	'180',    'active',    'CDF'),
+('CK',    'Cook Islands',                                 'CK',   	'COK',	'184',    'active',    'NZD'),
+('CR',    'Costa Rica',                                   'CR',   	'CRI',	'188',    'active',    'CRC'),
+('HR',    'Croatia',                                      'HR',   	'HRV',	'191',    'active',    'EUR'),
+('CU',    'Cuba',                                         'CU',   	'CUB',	'192',    'active',    'CUP'),
+('CY',    'Cyprus',                                       'CY',   	'CYP',	'196',    'active',    'EUR'),
+('CZ',    'Czech Republic',                               'CZ',   	'CZE',	'203',    'active',    'CZK'),
+('BJ',    'Benin',                                        'BJ',   	'BEN',	'204',    'active',    'XOF'),
+('DK',    'Denmark',                                      'DK',   	'DNK',	'208',    'active',    'DKK'),
+('DM',    'Dominica',                                     'DM',   	'DMA',	'212',    'active',    'XCD'),
+('DO',    'Dominican Republic',                           'DO',   	'DOM',	'214',    'active',    'DOP'),
+('EC',    'Ecuador',                                      'EC',   	'ECU',	'218',    'active',    'USD'),
+('SV',    'El Salvador',                                  'SV',   	'SLV',	'222',    'active',    'USD'),
+('GQ',    'Equatorial Guinea',                            'GQ',   	'GNQ',	'226',    'active',    'XAF'),
+('ET',    'Ethiopia',                                     'ET',   	'ETH',	'231',    'active',    'ETB'),
+('ER',    'Eritrea',                                      'ER',   	'ERI',	'232',    'active',    'ERN'),
+('EE',    'Estonia',                                      'EE',   	'EST',	'233',    'active',    'EUR'),
+('FO',    'Faroe Islands',                                'FO',   	'FRO',	'234',    'active',    'DKK'),
+('FK',    'Falkland Islands (Malvinas)',                  'FK',   	'FLK',	'238',    'active',    'FKP'),
+('GS',    'South Georgia and the South Sandwich Islands', 'GS',   	'SGS',	'239',    'active',    'GBP'),
+('FJ',    'Fiji',                                         'FJ',   	'FJI',	'242',    'active',    'FJD'),
+('FI',    'Finland',                                      'FI',   	'FIN',	'246',    'active',    'EUR'),
+('AX',    'ALA	Aland Islands',                           'AX',  	'ALA',	'248',    'active',    'EUR'),
+('FR',    'France',                                       'FR',   	'FRA',	'250',    'active',    'EUR'),
+('GF',    'French Guiana',                                'GF',   	'GUF',	'254',    'active',    'EUR'),
+('PF',    'French Polynesia',                             'PF',   	'PYF',	'258',    'active',    'XPF'),
+('TF',    'French Southern Territories',                  'TF',   	'ATF',	'260',    'active',    'EUR'),
+('DJ',    'Djibouti',                                     'DJ',   	'DJI',	'262',    'active',    'DJF'),
+('GA',    'Gabon',                                        'GA',   	'GAB',	'266',    'active',    'XAF'),
+('GE',    'Georgia',                                      'GE',   	'GEO',	'268',    'active',    'GEL'),
+('GM',    'Gambia',                                       'GM',   	'GMB',	'270',    'active',    'GMD'),
+('PS',    'Palestinian Territory',                        'PS',   	'PSE',	'275',    'active',    'ILS'),
+('DE',    'Germany',                                      'DE',   	'DEU',	'276',    'active',    'EUR'),
+('GH',    'Ghana',                                        'GH',   	'GHA',	'288',    'active',    'GHS'),
+('GI',    'Gibraltar',                                    'GI',   	'GIB',	'292',    'active',    'GIP'),
+('KI',    'Kiribati',                                     'KI',   	'KIR',	'296',    'active',    'AUD'),
+('GR',    'Greece',                                       'GR',   	'GRC',	'300',    'active',    'EUR'),
+('GL',    'Greenland',                                    'GL',   	'GRL',	'304',    'active',    'DKK'),
+('GD',    'Grenada',                                      'GD',   	'GRD',	'308',    'active',    'XCD'),
+('GP',    'Guadeloupe',                                   'GP',   	'GLP',	'312',    'active',    'EUR'),
+('GU',    'Guam',                                         'GU',   	'GUM',	'316',    'active',    'USD'),
+('GT',    'Guatemala',                                    'GT',   	'GTM',	'320',    'active',    'GTQ'),
+('GN',    'Guinea',                                       'GN',   	'GIN',	'324',    'active',    'GNF'),
+('GY',    'Guyana',                                       'GY',   	'GUY',	'328',    'active',    'GYD'),
+('HT',    'Haiti',                                        'HT',   	'HTI',	'332',    'active',    'HTG'),
+('HM',    'Heard and Mcdonald Islands',                   'HM',   	'HMD',	'334',    'active',    'AUD'),
+('VA',    'Holy See (Vatican City State)',                'VA',   	'VAT',	'336',    'active',    'EUR'),
+('HN',    'Honduras',                                     'HN',   	'HND',	'340',    'active',    'HNL'),
+('HK',    'Hong Kong, SAR China',                         'HK',   	'HKG',	'344',    'active',    'HKD'),
+('HU',    'Hungary',                                      'HU',   	'HUN',	'348',    'active',    'HUF'),
+('IS',    'Iceland',                                      'IS',   	'ISL',	'352',    'active',    'ISK'),
+('IN',    'India',                                        'IN',   	'IND',	'356',    'active',    'INR'),
+('ID',    'Indonesia',                                    'ID',   	'IDN',	'360',    'active',    'IDR'),
+('IR',    'Iran, Islamic Republic of',                    'IR',   	'IRN',	'364',    'active',    'IRR'),
+('IQ',    'Iraq',                                         'IQ',   	'IRQ',	'368',    'active',    'IQD'),
+('IE',    'Ireland',                                      'IE',   	'IRL',	'372',    'active',    'EUR'),
+('IL',    'Israel',                                       'IL',   	'ISR',	'376',    'active',    'ILS'),
+('IT',    'Italy',                                        'IT',   	'ITA',	'380',    'active',    'EUR'),
+('CI',    'Côte d''Ivoire',                                'CI',   	'CIV',	'384',    'active',    'XOF'),
+('JM',    'Jamaica',                                      'JM',   	'JAM',	'388',    'active',    'JMD'),
+('JP',    'Japan',                                        'JP',   	'JPN',	'392',    'active',    'JPY'),
+('KZ',    'Kazakhstan',                                   'KZ',   	'KAZ',	'398',    'active',    'KZT'),
+('JO',    'Jordan',                                       'JO',   	'JOR',	'400',    'active',    'JOD'),
+('KE',    'Kenya',                                        'KE',   	'KEN',	'404',    'active',    'KES'),
+('KP',    'Korea (North)',                                'KP',   	'PRK',	'408',    'active',    'KPW'),
+('KR',    'Korea (South)',                                'KR',   	'KOR',	'410',    'active',    'KRW'),
+('KW',    'Kuwait',                                       'KW',   	'KWT',	'414',    'active',    'KWD'),
+('KG',    'Kyrgyzstan',                                   'KG',   	'KGZ',	'417',    'active',    'KGS'),
+('LA',    'Lao PDR',                                      'LA',   	'LAO',	'418',    'active',    'LAK'),
+('LB',    'Lebanon',                                      'LB',   	'LBN',	'422',    'active',    'LBP'),
+('LS',    'Lesotho',                                      'LS',   	'LSO',	'426',    'active',    'LSL'),
+('LV',    'Latvia',                                       'LV',   	'LVA',	'428',    'active',    'EUR'),
+('LR',    'Liberia',                                      'LR',   	'LBR',	'430',    'active',    'LRD'),
+('LY',    'Libya',                                        'LY',   	'LBY',	'434',    'active',    'LYD'),
+('LI',    'Liechtenstein',                                'LI',   	'LIE',	'438',    'active',    'CHF'),
+('LT',    'Lithuania',                                    'LT',   	'LTU',	'440',    'active',    'EUR'),
+('LU',    'Luxembourg',                                   'LU',   	'LUX',	'442',    'active',    'EUR'),
+('MO',    'Macao, SAR China',                             'MO',   	'MAC',	'446',    'active',    'MOP'),
+('MG',    'Madagascar',                                   'MG',   	'MDG',	'450',    'active',    'MGA'),
+('MW',    'Malawi',                                       'MW',   	'MWI',	'454',    'active',    'MWK'),
+('MY',    'Malaysia',                                     'MY',   	'MYS',	'458',    'active',    'MYR'),
+('MV',    'Maldives',                                     'MV',   	'MDV',	'462',    'active',    'MVR'),
+('ML',    'Mali',                                         'ML',   	'MLI',	'466',    'active',    'XOF'),
+('MT',    'Malta',                                        'MT',   	'MLT',	'470',    'active',    'EUR'),
+('MQ',    'Martinique',                                   'MQ',   	'MTQ',	'474',    'active',    'EUR'),
+('MR',    'Mauritania',                                   'MR',   	'MRT',	'478',    'active',    'MRU'),
+('MU',    'Mauritius',                                    'MU',   	'MUS',	'480',    'active',    'MUR'),
+('MX',    'Mexico',                                       'MX',   	'MEX',	'484',    'active',    'MXN'),
+('MC',    'Monaco',                                       'MC',   	'MCO',	'492',    'active',    'EUR'),
+('MN',    'Mongolia',                                     'MN',   	'MNG',	'496',    'active',    'MNT'),
+('MD',    'Moldova',                                      'MD',   	'MDA',	'498',    'active',    'MDL'),
+('ME',    'Montenegro',                                   'ME',   	'MNE',	'499',    'active',    'EUR'),
+('MS',    'Montserrat',                                   'MS',   	'MSR',	'500',    'active',    'XCD'),
+('MA',    'Morocco',                                      'MA',   	'MAR',	'504',    'active',    'MAD'),
+('MZ',    'Mozambique',                                   'MZ',   	'MOZ',	'508',    'active',    'MZN'),
+('OM',    'Oman',                                         'OM',   	'OMN',	'512',    'active',    'OMR'),
+('NA',    'Namibia',                                      'NA',   	'NAM',	'516',    'active',    'NAD'),
+('NR',    'Nauru',                                        'NR',   	'NRU',	'520',    'active',    'AUD'),
+('NP',    'Nepal',                                        'NP',   	'NPL',	'524',    'active',    'NPR'),
+('NL',    'Netherlands',                                  'NL',   	'NLD',	'528',    'active',    'EUR'),
+('AN',    'Netherlands Antilles',                         'AN',   	'ANT',	'530',    'active',    'ANG'),
+('AW',    'Aruba',                                        'AW',   	'ABW',	'533',    'active',    'AWG'),
+('NC',    'New Caledonia',                                'NC',   	'NCL',	'540',    'active',    'XPF'),
+('VU',    'Vanuatu',                                      'VU',   	'VUT',	'548',    'active',    'VUV'),
+('NZ',    'New Zealand',                                  'NZ',   	'NZL',	'554',    'active',    'NZD'),
+('NI',    'Nicaragua',                                    'NI',   	'NIC',	'558',    'active',    'NIO'),
+('NE',    'Niger',                                        'NE',   	'NER',	'562',    'active',    'XOF'),
+('NG',    'Nigeria',                                      'NG',   	'NGA',	'566',    'active',    'NGN'),
+('NU',    'Niue',                                         'NU',   	'NIU',	'570',    'active',    'NZD'),
+('NF',    'Norfolk Island',                               'NF',   	'NFK',	'574',    'active',    'AUD'),
+('NO',    'Norway',                                       'NO',   	'NOR',	'578',    'active',    'NOK'),
+('MP',    'Northern Mariana Islands',                     'MP',   	'MNP',	'580',    'active',    'USD'),
+('UM',    'US Minor Outlying Islands',                    'UM',   	'UMI',	'581',    'active',    'USD'),
+('FM',    'Micronesia, Federated States of',              'FM',   	'FSM',	'583',    'active',    'USD'),
+('MH',    'Marshall Islands',                             'MH',   	'MHL',	'584',    'active',    'USD'),
+('PW',    'Palau',                                        'PW',   	'PLW',	'585',    'active',    'USD'),
+('PK',    'Pakistan',                                     'PK',   	'PAK',	'586',    'active',    'PKR'),
+('PA',    'Panama',                                       'PA',   	'PAN',	'591',    'active',    'PAB'),
+('PG',    'Papua New Guinea',                             'PG',   	'PNG',	'598',    'active',    'PGK'),
+('PY',    'Paraguay',                                     'PY',   	'PRY',	'600',    'active',    'PYG'),
+('PE',    'Peru',                                         'PE',   	'PER',	'604',    'active',    'PEN'),
+('PH',    'Philippines',                                  'PH',   	'PHL',	'608',    'active',    'PHP'),
+('PN',    'Pitcairn',                                     'PN',   	'PCN',	'612',    'active',    'NZD'),
+('PL',    'Poland',                                       'PL',   	'POL',	'616',    'active',    'PLN'),
+('PT',    'Portugal',                                     'PT',   	'PRT',	'620',    'active',    'EUR'),
+('GW',    'Guinea-Bissau',                                'GW',   	'GNB',	'624',    'active',    'XOF'),
+('TL',    'Timor-Leste',                                  'TL',   	'TLS',	'626',    'active',    'USD'),
+('PR',    'Puerto Rico',                                  'PR',   	'PRI',	'630',    'active',    'USD'),
+('QA',    'Qatar',                                        'QA',   	'QAT',	'634',    'active',    'QAR'),
+('RE',    'Réunion',                                      'RE',   	'REU',	'638',    'active',    'EUR'),
+('RO',    'Romania',                                      'RO',   	'ROU',	'642',    'active',    'RON'),
+('RU',    'Russian Federation',                           'RU',   	'RUS',	'643',    'active',    'RUB'),
+('RW',    'Rwanda',                                       'RW',   	'RWA',	'646',    'active',    'RWF'),
+('BL',    'Saint-Barthélemy',                             'BL',   	'BLM',	'652',    'active',    'EUR'),
+('SH',    'Saint Helena',                                 'SH',   	'SHN',	'654',    'active',    'SHP'),
+('KN',    'Saint Kitts and Nevis',                        'KN',   	'KNA',	'659',    'active',    'XCD'),
+('AI',    'Anguilla',                                     'AI',   	'AIA',	'660',    'active',    'XCD'),
+('LC',    'Saint Lucia',                                  'LC',   	'LCA',	'662',    'active',    'XCD'),
+('MF',    'Saint-Martin (French part)',                   'MF',   	'MAF',	'663',    'active',    'EUR'),
+('PM',    'Saint Pierre and Miquelon',                    'PM',   	'SPM',	'666',    'active',    'EUR'),
+('VC',    'Saint Vincent and Grenadines',                 'VC',   	'VCT',	'670',    'active',    'XCD'),
+('SM',    'San Marino',                                   'SM',   	'SMR',	'674',    'active',    'EUR'),
+('ST',    'Sao Tome and Principe',                        'ST',   	'STP',	'678',    'active',    'STN'),
+('SA',    'Saudi Arabia',                                 'SA',   	'SAU',	'682',    'active',    'SAR'),
+('SN',    'Senegal',                                      'SN',   	'SEN',	'686',    'active',    'XOF'),
+('RS',    'Serbia',                                       'RS',   	'SRB',	'688',    'active',    'RSD'),
+('SC',    'Seychelles',                                   'SC',   	'SYC',	'690',    'active',    'SCR'),
+('SL',    'Sierra Leone',                                 'SL',   	'SLE',	'694',    'active',    'SLE'),
+('SG',    'Singapore',                                    'SG',   	'SGP',	'702',    'active',    'SGD'),
+('SK',    'Slovakia',                                     'SK',   	'SVK',	'703',    'active',    'EUR'),
+('VN',    'Viet Nam',                                     'VN',   	'VNM',	'704',    'active',    'VND'),
+('SI',    'Slovenia',                                     'SI',   	'SVN',	'705',    'active',    'EUR'),
+('SO',    'Somalia',                                      'SO',   	'SOM',	'706',    'active',    'SOS'),
+('ZA',    'South Africa',                                 'ZA',   	'ZAF',	'710',    'active',    'ZAR'),
+('ZW',    'Zimbabwe',                                     'ZW',   	'ZWE',	'716',    'active',    'ZWG'),
+('ES',    'Spain',                                        'ES',   	'ESP',	'724',    'active',    'EUR'),
+('SS',    'South Sudan',                                  'SS',   	'SSD',	'728',    'active',    'SSP'),
+('EH',    'Western Sahara',                               'EH',   	'ESH',	'732',    'active',    'MAD'),
+('SD',    'Sudan',                                        'SD',   	'SDN',	'736',    'active',    'SDG'),
+('SR',    'Suriname',                                     'SR',   	'SUR',	'740',    'active',    'SRD'),
+('SJ',    'Svalbard and Jan Mayen Islands',               'SJ',   	'SJM',	'744',    'active',    'NOK'),
+('SZ',    'Swaziland',                                    'SZ',   	'SWZ',	'748',    'active',    'SZL'),
+('SE',    'Sweden',                                       'SE',   	'SWE',	'752',    'active',    'SEK'),
+('CH',    'Switzerland',                                  'CH',   	'CHE',	'756',    'active',    'CHF'),
+('SY',    'Syrian Arab Republic (Syria)',                 'SY',   	'SYR',	'760',    'active',    'SYP'),
+('TJ',    'Tajikistan',                                   'TJ',   	'TJK',	'762',    'active',    'TJS'),
+('TH',    'Thailand',                                     'TH',   	'THA',	'764',    'active',    'THB'),
+('TG',    'Togo',                                         'TG',   	'TGO',	'768',    'active',    'XOF'),
+('TK',    'Tokelau',                                      'TK',   	'TKL',	'772',    'active',    'NZD'),
+('TO',    'Tonga',                                        'TO',   	'TON',	'776',    'active',    'TOP'),
+('TT',    'Trinidad and Tobago',                          'TT',   	'TTO',	'780',    'active',    'TTD'),
+('AE',    'United Arab Emirates',                         'AE',   	'ARE',	'784',    'active',    'AED'),
+('TN',    'Tunisia',                                      'TN',   	'TUN',	'788',    'active',    'TND'),
+('TR',    'Turkey',                                       'TR',   	'TUR',	'792',    'active',    'TRY'),
+('TM',    'Turkmenistan',                                 'TM',   	'TKM',	'795',    'active',    'TMT'),
+('TC',    'Turks and Caicos Islands',                     'TC',   	'TCA',	'796',    'active',    'USD'),
+('TV',    'Tuvalu',                                       'TV',   	'TUV',	'798',    'active',    'AUD'),
+('UG',    'Uganda',                                       'UG',   	'UGA',	'800',    'active',    'UGX'),
+('UA',    'Ukraine',                                      'UA',   	'UKR',	'804',    'active',    'UAH'),
+('MK',    'Macedonia, Republic of',                       'MK',   	'MKD',	'807',    'active',    'MKD'),
+('EG',    'Egypt',                                        'EG',   	'EGY',	'818',    'active',    'EGP'),
+('GB',    'United Kingdom',                               'GB',   	'GBR',	'826',    'active',    'GBP'),
+('GG',    'Guernsey',                                     'GG',   	'GGY',	'831',    'active',    'GBP'),
+('JE',    'Jersey',                                       'JE',   	'JEY',	'832',    'active',    'GBP'),
+('IM',    'Isle of Man',                                  'IM',   	'IMN',	'833',    'active',    'GBP'),
+('TZ',    'Tanzania, United Republic of',                 'TZ',   	'TZA',	'834',    'active',    'TZS'),
+('US',    'United States of America',                     'US',   	'USA',	'840',    'active',    'USD'),
+('VI',    'Virgin Islands, US',                           'VI',   	'VIR',	'850',    'active',    'USD'),
+('BF',    'Burkina Faso',                                 'BF',   	'BFA',	'854',    'active',    'XOF'),
+('UY',    'Uruguay',                                      'UY',   	'URY',	'858',    'active',    'UYU'),
+('UZ',    'Uzbekistan',                                   'UZ',   	'UZB',	'860',    'active',    'UZS'),
+('VE',    'Venezuela (Bolivarian Republic)',              'VE',   	'VEN',	'862',    'active',    'VES'),
+('WF',    'Wallis and Futuna Islands',                    'WF',   	'WLF',	'876',    'active',    'XPF'),
+('WS',    'Samoa',                                        'WS',   	'WSM',	'882',    'active',    'WST'),
+('YE',    'Yemen',                                        'YE',   	'YEM',	'887',    'active',    'YER'),
+('ZM',    'Zambia',                                       'ZM',   	'ZMB',	'894',    'active',    'ZMW')
+ON CONFLICT (id) DO NOTHING;`
 
+func init() {
+	migrate.RegisterSeed(2, "country", countrySQL)
+}
@@ -0,0 +1,175 @@
+package seeds
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+// currencySQL - ISO 4217 currencies
+var currencySQL = `INSERT INTO currency (id, name, numeric_code, minor_units, status) VALUES
+('AED',   'UAE Dirham',                       '784',    2,    'active'),
+('AFN',   'Afghani',                          '971',    2,    'active'),
+('ALL',   'Lek',                              '008',    2,    'active'),
+('AMD',   'Armenian Dram',                    '051',    2,    'active'),
+('ANG',   'Netherlands Antillean Guilder',    '532',    2,    'active'),
+('AOA',   'Kwanza',                           '973',    2,    'active'),
+('ARS',   'Argentine Peso',                   '032',    2,    'active'),
+('AUD',   'Australian Dollar',                '036',    2,    'active'),
+('AWG',   'Aruban Florin',                    '533',    2,    'active'),
+('AZN',   'Azerbaijan Manat',                 '944',    2,    'active'),
+('BAM',   'Convertible Mark',                 '977',    2,    'active'),
+('BBD',   'Barbados Dollar',                  '052',    2,    'active'),
+('BDT',   'Taka',                             '050',    2,    'active'),
+('BGN',   'Bulgarian Lev',                    '975',    2,    'inactive'),
+('BHD',   'Bahraini Dinar',                   '048',    3,    'active'),
+('BIF',   'Burundi Franc',                    '108',    0,    'active'),
+('BMD',   'Bermudian Dollar',                 '060',    2,    'active'),
+('BND',   'Brunei Dollar',                    '096',    2,    'active'),
+('BOB',   'Boliviano',                        '068',    2,    'active'),
+('BRL',   'Brazilian Real',                   '986',    2,    'active'),
+('BSD',   'Bahamian Dollar',                  '044',    2,    'active'),
+('BTN',   'Ngultrum',                         '064',    2,    'active'),
+('BWP',   'Pula',                             '072',    2,    'active'),
+('BYN',   'Belarusian Ruble',                 '933',    2,    'active'),
+('BZD',   'Belize Dollar',                    '084',    2,    'active'),
+('CAD',   'Canadian Dollar',                  '124',    2,    'active'),
+('CDF',   'Congolese Franc',                  '976',    2,    'active'),
+('CHF',   'Swiss Franc',                      '756',    2,    'active'),
+('CLP',   'Chilean Peso',                     '152',    0,    'active'),
+('CNY',   'Yuan Renminbi',                    '156',    2,    'active'),
+('COP',   'Colombian Peso',                   '170',    2,    'active'),
+('CRC',   'Costa Rican Colon',                '188',    2,    'active'),
+('CUP',   'Cuban Peso',                       '192',    2,    'active'),
+('CVE',   'Cabo Verde Escudo',                '132',    2,    'active'),
+('CZK',   'Czech Koruna',                     '203',    2,    'active'),
+('DJF',   'Djibouti Franc',                   '262',    0,    'active'),
+('DKK',   'Danish Krone',                     '208',    2,    'active'),
+('DOP',   'Dominican Peso',                   '214',    2,    'active'),
+('DZD',   'Algerian Dinar',                   '012',    2,    'active'),
+('EGP',   'Egyptian Pound',                   '818',    2,    'active'),
+('ERN',   'Nakfa',                            '232',    2,    'active'),
+('ETB',   'Ethiopian Birr',                   '230',    2,    'active'),
+('EUR',   'Euro',                             '978',    2,    'active'),
+('FJD',   'Fiji Dollar',                      '242',    2,    'active'),
+('FKP',   'Falkland Islands Pound',           '238',    2,    'active'),
+('GBP',   'Pound Sterling',                   '826',    2,    'active'),
+('GEL',   'Lari',                             '981',    2,    'active'),
+('GHS',   'Ghana Cedi',                       '936',    2,    'active'),
+('GIP',   'Gibraltar Pound',                  '292',    2,    'active'),
+('GMD',   'Dalasi',                           '270',    2,    'active'),
+('GNF',   'Guinean Franc',                    '324',    0,    'active'),
+('GTQ',   'Quetzal',                          '320',    2,    'active'),
+('GYD',   'Guyana Dollar',                    '328',    2,    'active'),
+('HKD',   'Hong Kong Dollar',                 '344',    2,    'active'),
+('HNL',   'Lempira',                          '340',    2,    'active'),
+('HTG',   'Gourde',                           '332',    2,    'active'),
+('HUF',   'Forint',                           '348',    2,    'active'),
+('IDR',   'Rupiah',                           '360',    2,    'active'),
+('ILS',   'New Israeli Sheqel',               '376',    2,    'active'),
+('INR',   'Indian Rupee',                     '356',    2,    'active'),
+('IQD',   'Iraqi Dinar',                      '368',    3,    'active'),
+('IRR',   'Iranian Rial',                     '364',    2,    'active'),
+('ISK',   'Iceland Krona',                    '352',    0,    'active'),
+('JMD',   'Jamaican Dollar',                  '388',    2,    'active'),
+('JOD',   'Jordanian Dinar',                  '400',    3,    'active'),
+('JPY',   'Yen',                              '392',    0,    'active'),
+('KES',   'Kenyan Shilling',                  '404',    2,    'active'),
+('KGS',   'Som',                              '417',    2,    'active'),
+('KHR',   'Riel',                             '116',    2,    'active'),
+('KMF',   'Comorian Franc',                   '174',    0,    'active'),
+('KPW',   'North Korean Won',                 '408',    2,    'active'),
+('KRW',   'Won',                              '410',    0,    'active'),
+('KWD',   'Kuwaiti Dinar',                    '414',    3,    'active'),
+('KYD',   'Cayman Islands Dollar',            '136',    2,    'active'),
+('KZT',   'Tenge',                            '398',    2,    'active'),
+('LAK',   'Lao Kip',                          '418',    2,    'active'),
+('LBP',   'Lebanese Pound',                   '422',    2,    'active'),
+('LKR',   'Sri Lanka Rupee',                  '144',    2,    'active'),
+('LRD',   'Liberian Dollar',                  '430',    2,    'active'),
+('LSL',   'Loti',                             '426',    2,    'active'),
+('LYD',   'Libyan Dinar',                     '434',    3,    'active'),
+('MAD',   'Moroccan Dirham',                  '504',    2,    'active'),
+('MDL',   'Moldovan Leu',                     '498',    2,    'active'),
+('MGA',   'Malagasy Ariary',                  '969',    2,    'active'),
+('MKD',   'Denar',                            '807',    2,    'active'),
+('MMK',   'Kyat',                             '104',    2,    'active'),
+('MNT',   'Tugrik',                           '496',    2,    'active'),
+('MOP',   'Pataca',                           '446',    2,    'active'),
+('MRU',   'Ouguiya',                          '929',    2,    'active'),
+('MUR',   'Mauritius Rupee',                  '480',    2,    'active'),
+('MVR',   'Rufiyaa',                          '462',    2,    'active'),
+('MWK',   'Malawi Kwacha',                    '454',    2,    'active'),
+('MXN',   'Mexican Peso',                     '484',    2,    'active'),
+('MYR',   'Malaysian Ringgit',                '458',    2,    'active'),
+('MZN',   'Mozambique Metical',               '943',    2,    'active'),
+('NAD',   'Namibia Dollar',                   '516',    2,    'active'),
+('NGN',   'Naira',                            '566',    2,    'active'),
+('NIO',   'Cordoba Oro',                      '558',    2,    'active'),
+('NOK',   'Norwegian Krone',                  '578',    2,    'active'),
+('NPR',   'Nepalese Rupee',                   '524',    2,    'active'),
+('NZD',   'New Zealand Dollar',               '554',    2,    'active'),
+('OMR',   'Rial Omani',                       '512',    3,    'active'),
+('PAB',   'Balboa',                           '590',    2,    'active'),
+('PEN',   'Sol',                              '604',    2,    'active'),
+('PGK',   'Kina',                             '598',    2,    'active'),
+('PHP',   'Philippine Peso',                  '608',    2,    'active'),
+('PKR',   'Pakistan Rupee',                   '586',    2,    'active'),
+('PLN',   'Zloty',                            '985',    2,    'active'),
+('PYG',   'Guarani',                          '600',    0,    'active'),
+('QAR',   'Qatari Rial',                      '634',    2,    'active'),
+('RON',   'Romanian Leu',                     '946',    2,    'active'),
+('RSD',   'Serbian Dinar',                    '941',    2,    'active'),
+('RUB',   'Russian Ruble',                    '643',    2,    'active'),
+('RWF',   'Rwanda Franc',                     '646',    0,    'active'),
+('SAR',   'Saudi Riyal',                      '682',    2,    'active'),
+('SBD',   'Solomon Islands Dollar',           '090',    2,    'active'),
+('SCR',   'Seychelles Rupee',                 '690',    2,    'active'),
+('SDG',   'Sudanese Pound',                   '938',    2,    'active'),
+('SEK',   'Swedish Krona',                    '752',    2,    'active'),
+('SGD',   'Singapore Dollar',                 '702',    2,    'active'),
+('SHP',   'Saint Helena Pound',               '654',    2,    'active'),
+('SLE',   'Leone',                            '925',    2,    'active'),
+('SOS',   'Somali Shilling',                  '706',    2,    'active'),
+('SRD',   'Surinam Dollar',                   '968',    2,    'active'),
+('SSP',   'South Sudanese Pound',             '728',    2,    'active'),
+('STN',   'Dobra',                            '930',    2,    'active'),
+('SVC',   'El Salvador Colon',                '222',    2,    'active'),
+('SYP',   'Syrian Pound',                     '760',    2,    'active'),
+('SZL',   'Lilangeni',                        '748',    2,    'active'),
+('THB',   'Baht',                             '764',    2,    'active'),
+('TJS',   'Somoni',                           '972',    2,    'active'),
+('TMT',   'Turkmenistan New Manat',           '934',    2,    'active'),
+('TND',   'Tunisian Dinar',                   '788',    3,    'active'),
+('TOP',   'Pa''anga',                         '776',    2,    'active'),
+('TRY',   'Turkish Lira',                     '949',    2,    'active'),
+('TTD',   'Trinidad and Tobago Dollar',       '780',    2,    'active'),
+('TWD',   'New Taiwan Dollar',                '901',    2,    'active'),
+('TZS',   'Tanzanian Shilling',               '834',    2,    'active'),
+('UAH',   'Hryvnia',                          '980',    2,    'active'),
+('UGX',   'Uganda Shilling',                  '800',    0,    'active'),
+('USD',   'US Dollar',                        '840',    2,    'active'),
+('UYU',   'Peso Uruguayo',                    '858',    2,    'active'),
+('UZS',   'Uzbekistan Sum',                   '860',    2,    'active'),
+('VES',   'Bolivar Soberano',                 '928',    2,    'active'),
+('VND',   'Dong',                             '704',    0,    'active'),
+('VUV',   'Vatu',                             '548',    0,    'active'),
+('WST',   'Tala',                             '882',    2,    'active'),
+('XAF',   'CFA Franc BEAC',                   '950',    0,    'active'),
+('XCD',   'East Caribbean Dollar',            '951',    2,    'active'),
+('XOF',   'CFA Franc BCEAO',                  '952',    0,    'active'),
+('XPF',   'CFP Franc',                        '953',    0,    'active'),
+('YER',   'Yemeni Rial',                      '886',    2,    'active'),
+('ZAR',   'Rand',                             '710',    2,    'active'),
+('ZMW',   'Zambian Kwacha',                   '967',    2,    'active'),
+('ZWG',   'Zimbabwe Gold',                    '924',    2,    'active')
+ON CONFLICT (id) DO UPDATE SET
+	name = EXCLUDED.name,
+	numeric_code = EXCLUDED.numeric_code,
+	minor_units = EXCLUDED.minor_units,
+	status = EXCLUDED.status,
+	updated_at = now()
+WHERE (currency.name, currency.numeric_code, currency.minor_units, currency.status)
+	IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.numeric_code, EXCLUDED.minor_units, EXCLUDED.status);`
+
+func init() {
+	migrate.RegisterSeed(1, "currency", currencySQL)
+}
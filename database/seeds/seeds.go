@@ -0,0 +1,5 @@
+// Package seeds registers the reference data upserted after migrations by
+// test-api migrate up and at startup. Seeds run on every migrate up so they
+// must be safe to repeat, edit the data here rather than adding data
+// migrations.
+package seeds
@@ -1,19 +1,12 @@
-CREATE TYPE e_timezone_status AS ENUM (
-  'active', 
-  'inactive' 
-);
+package seeds
 
-CREATE TABLE timezone (
-  id              TEXT                NOT NULL,
-  status          e_timezone_status   NOT NULL DEFAULT 'active',
-	created_at      TIMESTAMP           NOT NULL DEFAULT now(),
-	updated_at      TIMESTAMP           NULL,
-	deleted_at      TIMESTAMP           NULL,
-  CONSTRAINT timezone_pk PRIMARY KEY (id)
-);
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
 
--- Data
-INSERT INTO timezone (id, status) VALUES
+// timezoneSQL - IANA time zones present before zones were synced from
+// tzdata. The sync job owns the table, so existing zones are left alone.
+var timezoneSQL = `INSERT INTO timezone (id, status) VALUES
 ('Turkey',		                                'active'),
 ('Iran',		                                'active'),
 ('GMT+0',		                                'active'),
@@ -1227,5 +1220,9 @@ INSERT INTO timezone (id, status) VALUES
 ('Eire',		                                'active'),
 ('GB-Eire',		                                'active'),
 ('HST',		                                    'active'),
-('NZ',		                                    'active');
+('NZ',		                                    'active')
+ON CONFLICT (id) DO NOTHING;`
 
+func init() {
+	migrate.RegisterSeed(3, "timezone", timezoneSQL)
+}
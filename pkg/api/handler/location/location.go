@@ -0,0 +1,420 @@
+package location
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/location"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/timezone"
+	"github.com/vegh1010/test/pkg/modelstore"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Data -
+type Data struct {
+	ID        string `json:"id"`
+	Merchant  string `json:"merchant"`
+	Name      string `json:"name"`
+	Timezone  string `json:"timezone"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// CollectionResponse -
+type CollectionResponse struct {
+	Data []*Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// Handler -
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/merchants/{merchant_id}/locations",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+			LockResources: map[string]map[string]string{
+				http.MethodPut: {"location": "id"},
+			},
+		},
+	}
+	return &h
+}
+
+// recordData -
+func recordData(rec *location.Record) *Data {
+	return &Data{
+		ID:        rec.ID,
+		Merchant:  rec.MerchantID,
+		Name:      rec.Name,
+		Timezone:  rec.TimezoneID,
+		Status:    rec.Status,
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt.String,
+	}
+}
+
+// getMerchant returns the merchant a location belongs to
+func (h *Handler) getMerchant(ms *modelstore.ModelStore, params handler.Params) (*merchant.Record, error) {
+
+	mm, err := ms.GetMerchantModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return mm.GetByID(params["merchant_id"].(string))
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetLocationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Get with params %v", params)
+
+	// get
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	res := Response{
+		Data: recordData(recs[0]),
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Location fetched OK")
+}
+
+// GetCollection -
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetLocationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetCollection with params %v", params)
+
+	// merchant must exist
+	_, err = h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	ed := []*Data{}
+	for _, rec := range recs {
+		ed = append(ed, recordData(rec))
+	}
+
+	res := CollectionResponse{
+		Data: ed,
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Locations fetched OK")
+}
+
+// Post -
+func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// model
+	m, err := ms.GetLocationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	// timezone aliases are stored as the canonical zone
+	timezoneID, err := timezone.Normalise(ms, req.Data.Timezone)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// record
+	rec := m.NewRecord()
+	rec.MerchantID = mrec.ID
+	rec.Name = req.Data.Name
+	rec.TimezoneID = timezoneID
+
+	vrec, err := m.ValidateRecord(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if vrec.TimezoneID.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidTimezone)
+		return
+	}
+
+	// create
+	err = m.Create(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(&rec),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Location created OK")
+}
+
+// Put -
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetLocationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+	if !util.StringInSlice(req.Data.Status, []string{location.StatusActive, location.StatusInactive, location.StatusTerminated}) {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidLocationStatus)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// timezone aliases are stored as the canonical zone
+	timezoneID, err := timezone.Normalise(ms, req.Data.Timezone)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// record
+	rec := recs[0]
+
+	if rec.Status == location.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedLocationCannotBeModified)
+		return
+	}
+
+	// update record properties
+	rec.Name = req.Data.Name
+	rec.TimezoneID = timezoneID
+	rec.Status = req.Data.Status
+
+	vrec, err := m.ValidateRecord(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if vrec.TimezoneID.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidTimezone)
+		return
+	}
+
+	// update
+	err = m.Update(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(rec),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Location updated OK")
+}
+
+// Delete -
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Delete location with params %v", params)
+
+	// model
+	m, err := ms.GetLocationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// delete
+	err = m.Delete(recs[0].ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Location deleted OK")
+
+	h.SendResponse(w, r, nil)
+}
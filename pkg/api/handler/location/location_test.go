@@ -0,0 +1,30 @@
+package location
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("name")},
+		{&Data{Name: "Sydney CBD"}, resperror.ValidationRequired("timezone")},
+		{&Data{Name: "Sydney CBD", Timezone: "Australia/Sydney"}, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
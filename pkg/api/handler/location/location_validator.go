@@ -0,0 +1,22 @@
+package location
+
+import (
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// Validate validates location request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	if req.Data.Name == "" {
+		return resperror.ValidationRequired("name")
+	}
+	if req.Data.Timezone == "" {
+		return resperror.ValidationRequired("timezone")
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package loglevel
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/logger"
+)
+
+// Data -
+type Data struct {
+	Level    string            `json:"level"`
+	Packages map[string]string `json:"packages"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// Handler - the log levels of this process, changes are lost on restart
+// and replaced by the configured levels on SIGHUP. Levels are shared by
+// every tenant so only operators may read or change them.
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/admin/log-levels",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+		},
+	}
+	return &h
+}
+
+func levelsData(lv *logger.Levels) *Data {
+	return &Data{
+		Level:    lv.Level,
+		Packages: lv.Packages,
+	}
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	err := h.RequireOperator(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: levelsData(logger.GetLevels()),
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Log levels fetched OK")
+}
+
+// Put - replaces the log level and every package level
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	err := h.RequireOperator(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	lv := &logger.Levels{Level: req.Data.Level, Packages: req.Data.Packages}
+
+	err = logger.SetLevels(lv)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Warn().Msgf("Log levels set to %s", lv)
+
+	res := Response{
+		Data: levelsData(logger.GetLevels()),
+	}
+
+	h.DebugStruct("Put Response", res)
+
+	h.SendResponse(w, r, &res)
+}
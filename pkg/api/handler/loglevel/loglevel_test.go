@@ -0,0 +1,33 @@
+package loglevel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("level")},
+		{&Data{Level: "loud"}, resperror.ErrorInvalidLogLevel},
+		{&Data{Level: "info", Packages: map[string]string{"db": "loud"}}, resperror.ErrorInvalidLogLevel},
+		{&Data{Level: "info", Packages: map[string]string{"": "debug"}}, resperror.ValidationRequired("package")},
+		{&Data{Level: "info"}, nil},
+		{&Data{Level: "error", Packages: map[string]string{"db": "debug"}}, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
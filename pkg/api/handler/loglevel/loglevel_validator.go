@@ -0,0 +1,33 @@
+package loglevel
+
+import (
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// Validate validates log level request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	if req.Data.Level == "" {
+		return resperror.ValidationRequired("level")
+	}
+
+	if _, err := logger.ParseLevel(req.Data.Level); err != nil {
+		return resperror.ErrorInvalidLogLevel
+	}
+
+	for name, lvl := range req.Data.Packages {
+		if name == "" {
+			return resperror.ValidationRequired("package")
+		}
+		if _, err := logger.ParseLevel(lvl); err != nil {
+			return resperror.ErrorInvalidLogLevel
+		}
+	}
+
+	return nil
+}
@@ -1,25 +1,35 @@
 package merchant
 
 import (
+	"database/sql"
 	"net/http"
+	"time"
 	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/businesshours"
 	"github.com/vegh1010/test/pkg/handler"
 	"github.com/vegh1010/test/pkg/resperror"
 	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/timezone"
 	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/util"
 )
 
 // Data -
 type Data struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	ShortName string `json:"short_name"`
-	DBAName   string `json:"dba_name"`
-	Country   string `json:"country"`
-	Timezone  string `json:"timezone"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID           string   `json:"id"`
+	Organisation string   `json:"organisation"`
+	Name         string   `json:"name"`
+	ShortName    string   `json:"short_name"`
+	DBAName      string   `json:"dba_name"`
+	Country      string   `json:"country"`
+	Timezone     string   `json:"timezone"`
+	LocalTime    string   `json:"local_time"`
+	UTCOffset    string   `json:"utc_offset"`
+	Currencies   []string `json:"currencies"`
+	Status       string   `json:"status"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+	DeletedAt    string   `json:"deleted_at,omitempty"`
 }
 
 // Response -
@@ -32,6 +42,9 @@ type CollectionResponse struct {
 	Data []*Data `json:"data"`
 }
 
+// FeaturePatch - feature flag turning on PATCH, partial updates
+const FeaturePatch = "merchant_patch"
+
 // Request -
 type Request struct {
 	Data *Data `json:"data"`
@@ -53,7 +66,8 @@ func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
 			Env:             e,
 			Logger:          l,
 			LockResources: map[string]map[string]string{
-				http.MethodPut: {"merchant": "id"},
+				http.MethodPut:   {"merchant": "id"},
+				http.MethodPatch: {"merchant": "id"},
 			},
 		},
 	}
@@ -98,17 +112,7 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	rec := recs[0]
 
 	res := Response{
-		Data: &Data{
-			ID:        rec.ID,
-			Name:      rec.Name,
-			ShortName: rec.ShortName,
-			DBAName:   rec.DBAName,
-			Country:   rec.CountryID,
-			Timezone:  rec.TimezoneID,
-			Status:    rec.Status,
-			CreatedAt: rec.CreatedAt,
-			UpdatedAt: rec.UpdatedAt.String,
-		},
+		Data: recordData(rec),
 	}
 
 	h.DebugStruct("Get Response", res)
@@ -139,32 +143,37 @@ func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
 
 	log.Debug().Msgf("GetCollection with params %v", params)
 
-	recs, _ := m.GetByParam(params)
-	if recs != nil {
-
-		var ed []*Data
-
-		for _, rec := range recs {
-			ed = append(ed, &Data{
-				ID:        rec.ID,
-				Name:      rec.Name,
-				ShortName: rec.ShortName,
-				DBAName:   rec.DBAName,
-				Country:   rec.CountryID,
-				Timezone:  rec.TimezoneID,
-				Status:    rec.Status,
-				CreatedAt: rec.CreatedAt,
-				UpdatedAt: rec.UpdatedAt.String,
-			})
+	// soft deleted merchants are only visible to admins
+	includeDeleted := r.URL.Query().Get("include_deleted") == "true"
+	if includeDeleted {
+		err = h.RequireAdmin(r)
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
 		}
+	}
 
-		res := CollectionResponse{
-			Data: ed,
-		}
+	// merchants are written as they are read
+	enc := h.NewCollectionEncoder(w, r)
 
-		h.DebugStruct("Get Response", res)
+	each := func(rec *merchant.Record) error {
+		return enc.Encode(recordData(rec))
+	}
 
-		h.SendResponse(w, r, &res)
+	if includeDeleted {
+		err = m.EachByParamIncludingDeleted(params, each)
+	} else {
+		err = m.EachByParam(params, each)
+	}
+	if err != nil {
+		enc.Error(err)
+		return
+	}
+
+	err = enc.Close()
+	if err != nil {
+		enc.Error(err)
+		return
 	}
 
 	log.Debug().Msgf("Merchant fetched OK")
@@ -208,6 +217,13 @@ func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// timezone aliases are stored as the canonical zone
+	timezoneID, err := timezone.Normalise(ms, req.Data.Timezone)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
 	// record
 	// example: rec.XxxxID = params["xxx_id"].(string)
 	// - NOTE
@@ -215,11 +231,13 @@ func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
 	// - properties can and cannot be set
 	// - based on role?
 	rec := m.NewRecord()
+	rec.OrganisationID = util.ToNullString(req.Data.Organisation)
 	rec.Name = req.Data.Name
 	rec.ShortName = req.Data.ShortName
 	rec.DBAName = req.Data.DBAName
 	rec.CountryID = req.Data.Country
-	rec.TimezoneID = req.Data.Timezone
+	rec.TimezoneID = timezoneID
+	rec.Currencies = req.Data.Currencies
 
 	log.Debug().Msgf("Validate with record %v", rec)
 	vrec, err := m.ValidateRecord(&rec)
@@ -236,6 +254,26 @@ func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
 		h.SendErrorResponse(w, r, resperror.ErrorInvalidTimezone)
 		return
 	}
+	if vrec.OrganisationID.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidOrganisation)
+		return
+	}
+	if vrec.Currencies.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidCurrency)
+		return
+	}
+
+	// merchants trade in their country's currency unless told otherwise
+	if len(rec.Currencies) == 0 {
+		currency, err := m.GetCountryCurrency(rec.CountryID)
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
+		}
+		if currency != "" {
+			rec.Currencies = []string{currency}
+		}
+	}
 
 	log.Debug().Msgf("Create with record %v", rec)
 
@@ -248,17 +286,7 @@ func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
 
 	if rec.ID != "" {
 		res := Response{
-			Data: &Data{
-				ID:        rec.ID,
-				Name:      rec.Name,
-				ShortName: rec.ShortName,
-				DBAName:   rec.DBAName,
-				Country:   rec.CountryID,
-				Timezone:  rec.TimezoneID,
-				Status:    rec.Status,
-				CreatedAt: rec.CreatedAt,
-				UpdatedAt: rec.UpdatedAt.String,
-			},
+			Data: recordData(&rec),
 		}
 
 		h.DebugStruct("Post Response", res)
@@ -269,8 +297,26 @@ func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
 	log.Debug().Msgf("Merchant created OK")
 }
 
-// Put -
+// Put - replaces the merchant's properties
 func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, false)
+}
+
+// Patch - updates the properties in the request, leaving the others
+// unchanged, for principals with the merchant_patch feature flag
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+
+	if !h.FeatureEnabled(r, FeaturePatch) {
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	h.update(w, r, true)
+}
+
+// update replaces the merchant's properties from the request, or only
+// those present in the request when patch is set
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, patch bool) {
 
 	// logger
 	log := h.Logger
@@ -290,8 +336,19 @@ func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
 
 	log.Debug().Msgf("Put with params %v", params)
 
-	// decode request body
+	// get current record
+	recs, _ := m.GetByParam(params)
+	if len(recs) != 1 || recs[0].ID != params["id"].(string) {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// decode request body, over the current properties when patching
 	req := Request{}
+	if patch {
+		req.Data = recordData(recs[0])
+	}
 	err = h.DecodeRequest(r, &req)
 	if err != nil {
 		h.SendErrorResponse(w, r, err)
@@ -307,11 +364,10 @@ func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// get current record
-	recs, _ := m.GetByParam(params)
-	if len(recs) != 1 || recs[0].ID != params["id"].(string) {
-		// not found
-		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+	// timezone aliases are stored as the canonical zone
+	timezoneID, err := timezone.Normalise(ms, req.Data.Timezone)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
 		return
 	}
 
@@ -328,13 +384,19 @@ func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
 	// - This is where we would decide which
 	// - properties can and cannot be updated
 	// - based on role?
+	rec.OrganisationID = util.ToNullString(req.Data.Organisation)
 	rec.Name = req.Data.Name
 	rec.ShortName = req.Data.ShortName
 	rec.DBAName = req.Data.DBAName
 	rec.CountryID = req.Data.Country
-	rec.TimezoneID = req.Data.Timezone
+	rec.TimezoneID = timezoneID
 	rec.Status = req.Data.Status
 
+	// currencies are left unchanged when not provided
+	if req.Data.Currencies != nil {
+		rec.Currencies = req.Data.Currencies
+	}
+
 	log.Debug().Msgf("Validate with record %v", rec)
 	vrec, err := m.ValidateRecord(rec)
 	if err != nil {
@@ -350,6 +412,14 @@ func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
 		h.SendErrorResponse(w, r, resperror.ErrorInvalidTimezone)
 		return
 	}
+	if vrec.OrganisationID.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidOrganisation)
+		return
+	}
+	if vrec.Currencies.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidCurrency)
+		return
+	}
 
 	// update
 	err = m.Update(rec)
@@ -360,17 +430,7 @@ func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
 
 	if rec.ID != "" {
 		res := Response{
-			Data: &Data{
-				ID:        rec.ID,
-				Name:      rec.Name,
-				ShortName: rec.ShortName,
-				DBAName:   rec.DBAName,
-				Country:   rec.CountryID,
-				Timezone:  rec.TimezoneID,
-				Status:    rec.Status,
-				CreatedAt: rec.CreatedAt,
-				UpdatedAt: rec.UpdatedAt.String,
-			},
+			Data: recordData(rec),
 		}
 
 		h.DebugStruct("Put Response", res)
@@ -402,6 +462,32 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// purge hard deletes the merchant, including merchants
+	// that have already been soft deleted
+	if r.URL.Query().Get("purge") == "true" {
+
+		err = h.RequireAdmin(r)
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
+		}
+
+		err = m.Remove(params["id"].(string))
+		if err == sql.ErrNoRows {
+			h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+			return
+		}
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
+		}
+
+		log.Debug().Msgf("Merchant purged OK")
+
+		h.SendResponse(w, r, nil)
+		return
+	}
+
 	// get current record
 	recs, _ := m.GetByParam(params)
 	if len(recs) != 1 || recs[0].ID != params["id"].(string) {
@@ -421,3 +507,84 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	h.SendResponse(w, r, nil)
 }
+
+// Restore - restores a soft deleted merchant
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Restore merchant with params %v", params)
+
+	err = h.RequireAdmin(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// restore, not found when the merchant does
+	// not exist or has not been deleted
+	rec, err := m.Restore(params["id"].(string))
+	if err == sql.ErrNoRows {
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(rec),
+	}
+
+	h.DebugStruct("Restore Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Merchant restored OK")
+}
+
+// recordData -
+func recordData(rec *merchant.Record) *Data {
+	currencies := []string(rec.Currencies)
+	if currencies == nil {
+		currencies = []string{}
+	}
+	d := Data{
+		ID:           rec.ID,
+		Organisation: rec.OrganisationID.String,
+		Name:         rec.Name,
+		ShortName:    rec.ShortName,
+		DBAName:      rec.DBAName,
+		Country:      rec.CountryID,
+		Timezone:     rec.TimezoneID,
+		Currencies:   currencies,
+		Status:       rec.Status,
+		CreatedAt:    rec.CreatedAt,
+		UpdatedAt:    rec.UpdatedAt.String,
+		DeletedAt:    rec.DeletedAt.String,
+	}
+
+	// local time is left empty for zones missing from the host tz database
+	if loc, err := businesshours.Location(rec.TimezoneID); err == nil {
+		now := time.Now().In(loc)
+		d.LocalTime = now.Format(time.RFC3339)
+		d.UTCOffset = now.Format("-07:00")
+	}
+
+	return &d
+}
@@ -0,0 +1,140 @@
+package merchant
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/vegh1010/test/pkg/model/auditlog"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+	"github.com/vegh1010/test/pkg/validator"
+)
+
+// epoch - default start of the audit time range filter
+const epoch = "1970-01-01T00:00:00Z"
+
+// AuditData -
+type AuditData struct {
+	ID        string          `json:"id"`
+	Operation string          `json:"operation"`
+	ActorID   string          `json:"actor_id"`
+	ActorName string          `json:"actor_name"`
+	RequestID string          `json:"request_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Diff      json.RawMessage `json:"diff"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// AuditCollectionResponse -
+type AuditCollectionResponse struct {
+	Data []*AuditData `json:"data"`
+}
+
+func auditData(rec *auditlog.Record) *AuditData {
+	ad := &AuditData{
+		ID:        rec.ID,
+		Operation: rec.Operation,
+		ActorID:   rec.ActorID,
+		ActorName: rec.ActorName,
+		RequestID: rec.RequestID,
+		Before:    json.RawMessage("null"),
+		After:     json.RawMessage("null"),
+		Diff:      json.RawMessage(rec.Diff),
+		CreatedAt: rec.CreatedAt,
+	}
+	if rec.Before.Valid {
+		ad.Before = json.RawMessage(rec.Before.JSONText)
+	}
+	if rec.After.Valid {
+		ad.After = json.RawMessage(rec.After.JSONText)
+	}
+	return ad
+}
+
+// GetAudit - the audit trail for a merchant, newest first.
+//
+// Supports filtering by actor with ?actor=<actor id> and by time range
+// with ?from=<RFC3339>&to=<RFC3339>. Merchants that have since been
+// deleted or removed can still be audited.
+func (h *Handler) GetAudit(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetAuditLogModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetAudit with params %v", params)
+
+	q := r.URL.Query()
+
+	from := q.Get("from")
+	if from == "" {
+		from = epoch
+	} else if !validator.ValidateTimestampFormat(from) {
+		h.SendErrorResponse(w, r, resperror.ValidationTimestampFormat("from"))
+		return
+	}
+
+	to := q.Get("to")
+	if to == "" {
+		to = util.GetTime()
+	} else if !validator.ValidateTimestampFormat(to) {
+		h.SendErrorResponse(w, r, resperror.ValidationTimestampFormat("to"))
+		return
+	}
+
+	aparams := map[string]interface{}{
+		"entity_type": merchant.AggregateType,
+		"entity_id":   params["id"],
+		"created_at":  from + "," + to,
+	}
+	operators := map[string]string{
+		"created_at":      "between",
+		"__order_by_desc": "created_at",
+	}
+
+	if actor := q.Get("actor"); actor != "" {
+		aparams["actor_id"] = actor
+	}
+
+	if limit := q.Get("limit"); limit != "" {
+		if _, err := strconv.ParseUint(limit, 10, 32); err != nil {
+			h.SendErrorResponse(w, r, resperror.ValidationInvalidIntegerFormat("limit"))
+			return
+		}
+		operators["__limit"] = limit
+	}
+
+	recs, err := m.GetByParam(aparams, operators)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	ad := []*AuditData{}
+	for _, rec := range recs {
+		ad = append(ad, auditData(rec))
+	}
+
+	res := AuditCollectionResponse{
+		Data: ad,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Merchant audit fetched OK")
+}
@@ -0,0 +1,138 @@
+package merchant
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vegh1010/test/pkg/principalcontext"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/search"
+	"github.com/vegh1010/test/pkg/txcontext"
+)
+
+// maxSearchLength - longest accepted search text
+const maxSearchLength = 200
+
+// SearchData -
+type SearchData struct {
+	*Data
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// SearchCollectionResponse -
+type SearchCollectionResponse struct {
+	Data []*SearchData `json:"data"`
+}
+
+// Search - merchants matching ?q= by name, short name or DBA name, best
+// matches first. Supports ?limit= up to search.MaxLimit.
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Search with params %v", params)
+
+	q := r.URL.Query()
+
+	sq := &search.Query{
+		Text: strings.TrimSpace(q.Get("q")),
+	}
+	if sq.Text == "" {
+		h.SendErrorResponse(w, r, resperror.ValidationRequired("q"))
+		return
+	}
+	if len(sq.Text) > maxSearchLength {
+		h.SendErrorResponse(w, r, resperror.ValidationInvalid("q"))
+		return
+	}
+
+	if limit := q.Get("limit"); limit != "" {
+		l, err := strconv.ParseUint(limit, 10, 32)
+		if err != nil {
+			h.SendErrorResponse(w, r, resperror.ValidationInvalidIntegerFormat("limit"))
+			return
+		}
+		sq.Limit = int(l)
+	}
+
+	// an external index holds every tenant's merchants
+	p, err := principalcontext.GetContext(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+	sq.TenantID = p.TenantID
+
+	tx, err := txcontext.GetContext(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	s, err := search.NewSearcher(h.Env, h.Logger, tx)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	results, err := s.SearchMerchants(r.Context(), sq)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	var ids []string
+	for _, res := range results {
+		ids = append(ids, res.ID)
+	}
+
+	recs, err := m.GetByIDs(ids)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	data := map[string]*Data{}
+	for _, rec := range recs {
+		data[rec.ID] = recordData(rec)
+	}
+
+	// results keep search order, merchants deleted since an
+	// external index was last synced are skipped
+	sd := []*SearchData{}
+	for _, res := range results {
+		d, ok := data[res.ID]
+		if !ok {
+			continue
+		}
+		sd = append(sd, &SearchData{
+			Data:       d,
+			Score:      res.Score,
+			Highlights: res.Highlights,
+		})
+	}
+
+	res := SearchCollectionResponse{
+		Data: sd,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Merchant search OK")
+}
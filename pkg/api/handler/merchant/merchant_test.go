@@ -1 +1,72 @@
 package merchant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	data := func(currencies ...string) *Data {
+		return &Data{
+			Name:       "Acme Pty Ltd",
+			ShortName:  "Acme",
+			DBAName:    "Acme",
+			Country:    "AU",
+			Timezone:   "Australia/Sydney",
+			Currencies: currencies,
+		}
+	}
+
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("name")},
+		{data(), nil},
+		{data("AUD", "USD"), nil},
+		{data("aud"), resperror.ErrorInvalidCurrency},
+		{data("AUD", "AUD"), resperror.ErrorInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	h := NewHandler(nil, zerolog.Nop()).(*Handler)
+
+	// properties missing from the body keep the values decoded over
+	r := httptest.NewRequest(http.MethodPatch, "/api/merchants/1", strings.NewReader(`{"data":{"name":"Acme Holdings","currencies":["AUD"]}}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	req := Request{Data: &Data{Name: "Acme Pty Ltd", ShortName: "Acme", Country: "AU"}}
+	err := h.DecodeRequest(r, &req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "Acme Holdings", req.Data.Name)
+	assert.Equal(t, "Acme", req.Data.ShortName)
+	assert.Equal(t, "AU", req.Data.Country)
+	assert.Equal(t, []string{"AUD"}, req.Data.Currencies)
+
+	// without the feature flag PATCH is not found
+	r = httptest.NewRequest(http.MethodPatch, "/api/merchants/1", strings.NewReader(`{"data":{}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.Patch(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
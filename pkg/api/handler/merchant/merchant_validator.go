@@ -2,6 +2,8 @@ package merchant
 
 import (
 	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+	"github.com/vegh1010/test/pkg/validator"
 )
 
 // Validate valiates merchant request Data.
@@ -26,6 +28,14 @@ func (req *Request) Validate() error {
 	if req.Data.Timezone == "" {
 		return resperror.ValidationRequired("timezone")
 	}
+	if req.Data.Organisation != "" && !util.ValidateUUID(req.Data.Organisation) {
+		return resperror.ErrorInvalidOrganisation
+	}
+	for i, c := range req.Data.Currencies {
+		if !validator.ValidateCurrencyCode(c) || util.StringInSlice(c, req.Data.Currencies[:i]) {
+			return resperror.ErrorInvalidCurrency
+		}
+	}
 
 	return nil
 }
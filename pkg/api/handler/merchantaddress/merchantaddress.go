@@ -0,0 +1,444 @@
+package merchantaddress
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/merchantaddress"
+	"github.com/vegh1010/test/pkg/modelstore"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// Data -
+type Data struct {
+	ID         string `json:"id"`
+	Merchant   string `json:"merchant"`
+	Type       string `json:"type"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// CollectionResponse -
+type CollectionResponse struct {
+	Data []*Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// Handler -
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/merchants/{merchant_id}/addresses",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+			LockResources: map[string]map[string]string{
+				http.MethodPut: {"merchant_address": "id"},
+			},
+		},
+	}
+	return &h
+}
+
+// recordData -
+func recordData(rec *merchantaddress.Record) *Data {
+	return &Data{
+		ID:         rec.ID,
+		Merchant:   rec.MerchantID,
+		Type:       rec.Type,
+		Line1:      rec.Line1,
+		Line2:      rec.Line2,
+		City:       rec.City,
+		Region:     rec.Region,
+		PostalCode: rec.PostalCode,
+		Country:    rec.CountryID,
+		CreatedAt:  rec.CreatedAt,
+		UpdatedAt:  rec.UpdatedAt.String,
+	}
+}
+
+// getMerchant returns the merchant an address belongs to
+func (h *Handler) getMerchant(ms *modelstore.ModelStore, params handler.Params) (*merchant.Record, error) {
+
+	mm, err := ms.GetMerchantModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return mm.GetByID(params["merchant_id"].(string))
+}
+
+// validateAddress checks the country and region ValidateRecord found,
+// regions of countries with subdivisions are stored as the code
+func validateAddress(rec *merchantaddress.Record, vrec *merchantaddress.ValidateResult) error {
+
+	if vrec.CountryID.Bool == false {
+		return resperror.ErrorInvalidCountry
+	}
+
+	if rec.Region != "" && vrec.HasSubdivisions {
+		if !vrec.RegionCode.Valid {
+			return resperror.ErrorInvalidRegion
+		}
+		rec.Region = vrec.RegionCode.String
+	}
+
+	return nil
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantAddressModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Get with params %v", params)
+
+	// get
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	res := Response{
+		Data: recordData(recs[0]),
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Address fetched OK")
+}
+
+// GetCollection -
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantAddressModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetCollection with params %v", params)
+
+	// merchant must exist
+	_, err = h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	ed := []*Data{}
+	for _, rec := range recs {
+		ed = append(ed, recordData(rec))
+	}
+
+	res := CollectionResponse{
+		Data: ed,
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Addresses fetched OK")
+}
+
+// Post -
+func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantAddressModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	// record
+	rec := m.NewRecord()
+	rec.MerchantID = mrec.ID
+	rec.Type = req.Data.Type
+	rec.Line1 = req.Data.Line1
+	rec.Line2 = req.Data.Line2
+	rec.City = req.Data.City
+	rec.Region = req.Data.Region
+	rec.PostalCode = req.Data.PostalCode
+	rec.CountryID = req.Data.Country
+
+	vrec, err := m.ValidateRecord(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	err = validateAddress(&rec, vrec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// create
+	err = m.Create(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(&rec),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Address created OK")
+}
+
+// Put -
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantAddressModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// record
+	rec := recs[0]
+
+	// merchant must not be terminated
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	// update record properties
+	rec.Type = req.Data.Type
+	rec.Line1 = req.Data.Line1
+	rec.Line2 = req.Data.Line2
+	rec.City = req.Data.City
+	rec.Region = req.Data.Region
+	rec.PostalCode = req.Data.PostalCode
+	rec.CountryID = req.Data.Country
+
+	vrec, err := m.ValidateRecord(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	err = validateAddress(rec, vrec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// update
+	err = m.Update(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(rec),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Address updated OK")
+}
+
+// Delete -
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Delete address with params %v", params)
+
+	// model
+	m, err := ms.GetMerchantAddressModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// delete
+	err = m.Delete(recs[0].ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Address deleted OK")
+
+	h.SendResponse(w, r, nil)
+}
@@ -0,0 +1,64 @@
+package merchantaddress
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/model/merchantaddress"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("type")},
+		{&Data{Type: "home"}, resperror.ErrorInvalidAddressType},
+		{&Data{Type: "trading"}, resperror.ValidationRequired("line1")},
+		{&Data{Type: "trading", Line1: "1 George St"}, resperror.ValidationRequired("city")},
+		{&Data{Type: "trading", Line1: "1 George St", City: "Sydney"}, resperror.ValidationRequired("country")},
+		{&Data{Type: "trading", Line1: "1 George St", City: "Sydney", Country: "AU"}, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	country := sql.NullBool{Bool: true, Valid: true}
+	nsw := sql.NullString{String: "NSW", Valid: true}
+
+	tests := []struct {
+		region string
+		vrec   merchantaddress.ValidateResult
+		want   string
+		err    error
+	}{
+		{"NSW", merchantaddress.ValidateResult{}, "", resperror.ErrorInvalidCountry},
+		{"New South Wales", merchantaddress.ValidateResult{CountryID: country, RegionCode: nsw, HasSubdivisions: true}, "NSW", nil},
+		{"Nowhere", merchantaddress.ValidateResult{CountryID: country, HasSubdivisions: true}, "", resperror.ErrorInvalidRegion},
+		{"Anywhere", merchantaddress.ValidateResult{CountryID: country}, "Anywhere", nil},
+		{"", merchantaddress.ValidateResult{CountryID: country, HasSubdivisions: true}, "", nil},
+	}
+
+	for _, tt := range tests {
+		rec := merchantaddress.Record{Region: tt.region}
+		err := validateAddress(&rec, &tt.vrec)
+		if tt.err != nil {
+			assert.Equal(t, tt.err, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, rec.Region)
+	}
+}
@@ -0,0 +1,33 @@
+package merchantaddress
+
+import (
+	"github.com/vegh1010/test/pkg/model/merchantaddress"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Validate validates merchant address request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	if req.Data.Type == "" {
+		return resperror.ValidationRequired("type")
+	}
+	if !util.StringInSlice(req.Data.Type, merchantaddress.Types) {
+		return resperror.ErrorInvalidAddressType
+	}
+	if req.Data.Line1 == "" {
+		return resperror.ValidationRequired("line1")
+	}
+	if req.Data.City == "" {
+		return resperror.ValidationRequired("city")
+	}
+	if req.Data.Country == "" {
+		return resperror.ValidationRequired("country")
+	}
+
+	return nil
+}
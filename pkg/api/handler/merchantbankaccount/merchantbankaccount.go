@@ -0,0 +1,472 @@
+package merchantbankaccount
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/merchantbankaccount"
+	"github.com/vegh1010/test/pkg/modelstore"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Data -
+type Data struct {
+	ID            string `json:"id"`
+	Merchant      string `json:"merchant"`
+	AccountName   string `json:"account_name"`
+	Country       string `json:"country"`
+	Currency      string `json:"currency"`
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+	IsDefault     bool   `json:"is_default"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// CollectionResponse -
+type CollectionResponse struct {
+	Data []*Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// Handler -
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/merchants/{merchant_id}/bank-accounts",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+			LockResources: map[string]map[string]string{
+				http.MethodPut: {"merchant_bank_account": "id"},
+			},
+		},
+	}
+	return &h
+}
+
+// recordData - account numbers are decrypted and only ever returned
+// with all but the last four characters masked.
+func recordData(m *merchantbankaccount.Model, rec *merchantbankaccount.Record) (*Data, error) {
+	accountNumber, err := m.AccountNumber(rec)
+	if err != nil {
+		return nil, err
+	}
+	return &Data{
+		ID:            rec.ID,
+		Merchant:      rec.MerchantID,
+		AccountName:   rec.AccountName,
+		Country:       rec.CountryID,
+		Currency:      rec.Currency,
+		BankCode:      rec.BankCode,
+		AccountNumber: util.MaskLastFourCharactersClear(accountNumber),
+		IsDefault:     rec.IsDefault,
+		CreatedAt:     rec.CreatedAt,
+		UpdatedAt:     rec.UpdatedAt.String,
+	}, nil
+}
+
+// getMerchant returns the merchant a bank account belongs to
+func (h *Handler) getMerchant(ms *modelstore.ModelStore, params handler.Params) (*merchant.Record, error) {
+
+	mm, err := ms.GetMerchantModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return mm.GetByID(params["merchant_id"].(string))
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantBankAccountModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Get with params %v", params)
+
+	// get
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	data, err := recordData(m, recs[0])
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: data,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Bank account fetched OK")
+}
+
+// GetCollection -
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantBankAccountModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetCollection with params %v", params)
+
+	// merchant must exist
+	_, err = h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	ed := []*Data{}
+	for _, rec := range recs {
+		data, err := recordData(m, rec)
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
+		}
+		ed = append(ed, data)
+	}
+
+	res := CollectionResponse{
+		Data: ed,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Bank accounts fetched OK")
+}
+
+// Post -
+func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// validate
+	req.normalise()
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantBankAccountModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	if !util.StringInSlice(req.Data.Currency, mrec.Currencies) {
+		h.SendErrorResponse(w, r, resperror.ErrorUnsupportedCurrency)
+		return
+	}
+
+	// record
+	rec := m.NewRecord()
+	rec.MerchantID = mrec.ID
+	rec.AccountName = req.Data.AccountName
+	rec.CountryID = req.Data.Country
+	rec.Currency = req.Data.Currency
+	rec.BankCode = req.Data.BankCode
+	rec.IsDefault = req.Data.IsDefault
+
+	vrec, err := m.ValidateRecord(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if vrec.CountryID.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidCountry)
+		return
+	}
+
+	err = m.SetAccountNumber(&rec, req.Data.AccountNumber)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// create
+	err = m.Create(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := recordData(m, &rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: data,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Bank account created OK")
+}
+
+// Put - the account number may be left out to keep the current one.
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantBankAccountModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// record
+	rec := recs[0]
+
+	// current account number is validated against the new country
+	if req.Data != nil && req.Data.AccountNumber == "" {
+		req.Data.AccountNumber, err = m.AccountNumber(rec)
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	// validate
+	req.normalise()
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// merchant must not be terminated
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	if !util.StringInSlice(req.Data.Currency, mrec.Currencies) {
+		h.SendErrorResponse(w, r, resperror.ErrorUnsupportedCurrency)
+		return
+	}
+
+	// update record properties
+	rec.AccountName = req.Data.AccountName
+	rec.CountryID = req.Data.Country
+	rec.Currency = req.Data.Currency
+	rec.BankCode = req.Data.BankCode
+	rec.IsDefault = req.Data.IsDefault
+
+	vrec, err := m.ValidateRecord(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if vrec.CountryID.Bool == false {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidCountry)
+		return
+	}
+
+	err = m.SetAccountNumber(rec, req.Data.AccountNumber)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// update
+	err = m.Update(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := recordData(m, rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: data,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Bank account updated OK")
+}
+
+// Delete -
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Delete bank account with params %v", params)
+
+	// model
+	m, err := ms.GetMerchantBankAccountModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// delete
+	err = m.Delete(recs[0].ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Bank account deleted OK")
+
+	h.SendResponse(w, r, nil)
+}
@@ -0,0 +1,72 @@
+package merchantbankaccount
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("account_name")},
+		{&Data{AccountName: "Acme"}, resperror.ValidationRequired("country")},
+		{&Data{AccountName: "Acme", Country: "GB"}, resperror.ValidationRequired("currency")},
+		{&Data{AccountName: "Acme", Country: "GB", Currency: "POUND"}, resperror.ErrorInvalidCurrency},
+		{&Data{AccountName: "Acme", Country: "GB", Currency: "GBP"}, resperror.ValidationRequired("account_number")},
+
+		// IBAN
+		{&Data{AccountName: "Acme", Country: "GB", Currency: "GBP", AccountNumber: "GB82WEST12345698765431"}, resperror.ErrorInvalidIBAN},
+		{&Data{AccountName: "Acme", Country: "GB", Currency: "GBP", AccountNumber: "DE89370400440532013000"}, resperror.ErrorInvalidIBAN},
+		{&Data{AccountName: "Acme", Country: "GB", Currency: "GBP", AccountNumber: "GB82WEST12345698765432"}, nil},
+
+		// US
+		{&Data{AccountName: "Acme", Country: "US", Currency: "USD", AccountNumber: "123456789"}, resperror.ValidationRequired("bank_code")},
+		{&Data{AccountName: "Acme", Country: "US", Currency: "USD", AccountNumber: "123456789", BankCode: "021000022"}, resperror.ErrorInvalidRoutingNumber},
+		{&Data{AccountName: "Acme", Country: "US", Currency: "USD", AccountNumber: "12", BankCode: "021000021"}, resperror.ErrorInvalidAccountNumber},
+		{&Data{AccountName: "Acme", Country: "US", Currency: "USD", AccountNumber: "123456789", BankCode: "021000021"}, nil},
+
+		// AU
+		{&Data{AccountName: "Acme", Country: "AU", Currency: "AUD", AccountNumber: "12345678", BankCode: "06200"}, resperror.ErrorInvalidBSB},
+		{&Data{AccountName: "Acme", Country: "AU", Currency: "AUD", AccountNumber: "1234567890", BankCode: "062-000"}, resperror.ErrorInvalidAccountNumber},
+		{&Data{AccountName: "Acme", Country: "AU", Currency: "AUD", AccountNumber: "12345678", BankCode: "062-000"}, nil},
+
+		// other
+		{&Data{AccountName: "Acme", Country: "NZ", Currency: "NZD", AccountNumber: "12-3456"}, resperror.ErrorInvalidAccountNumber},
+		{&Data{AccountName: "Acme", Country: "NZ", Currency: "NZD", AccountNumber: "123456789"}, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	req := Request{Data: &Data{
+		Country:       "au",
+		Currency:      "aud",
+		BankCode:      "062 000",
+		AccountNumber: "1234 5678",
+	}}
+	req.normalise()
+
+	assert.Equal(t, "AU", req.Data.Country)
+	assert.Equal(t, "AUD", req.Data.Currency)
+	assert.Equal(t, "062-000", req.Data.BankCode)
+	assert.Equal(t, "12345678", req.Data.AccountNumber)
+
+	req = Request{Data: &Data{Country: "gb", AccountNumber: "gb82 west 1234 5698 7654 32"}}
+	req.normalise()
+
+	assert.Equal(t, "GB82WEST12345698765432", req.Data.AccountNumber)
+}
@@ -0,0 +1,98 @@
+package merchantbankaccount
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/validator"
+)
+
+// account number formats by country, other countries use IBAN or a
+// generic alphanumeric account number
+var (
+	usAccountNumber    = regexp.MustCompile(`^[0-9]{4,17}$`)
+	auAccountNumber    = regexp.MustCompile(`^[0-9]{5,9}$`)
+	otherAccountNumber = regexp.MustCompile(`^[A-Z0-9]{4,34}$`)
+	bsbDigits          = regexp.MustCompile(`^[0-9]{6}$`)
+)
+
+// normalise tidies up request data before validation, codes are upper
+// cased and spaces removed from account numbers and bank codes.
+func (req *Request) normalise() {
+	if req.Data == nil {
+		return
+	}
+
+	d := req.Data
+	d.Country = strings.ToUpper(strings.TrimSpace(d.Country))
+	d.Currency = strings.ToUpper(strings.TrimSpace(d.Currency))
+	d.AccountNumber = strings.ToUpper(strings.Replace(d.AccountNumber, " ", "", -1))
+	d.BankCode = strings.Replace(d.BankCode, " ", "", -1)
+
+	// BSB numbers are commonly written without the dash
+	if d.Country == "AU" && bsbDigits.MatchString(d.BankCode) {
+		d.BankCode = d.BankCode[:3] + "-" + d.BankCode[3:]
+	}
+}
+
+// Validate validates merchant bank account request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	d := req.Data
+
+	if d.AccountName == "" {
+		return resperror.ValidationRequired("account_name")
+	}
+	if d.Country == "" {
+		return resperror.ValidationRequired("country")
+	}
+	if d.Currency == "" {
+		return resperror.ValidationRequired("currency")
+	}
+	if !validator.ValidateCurrencyCode(d.Currency) {
+		return resperror.ErrorInvalidCurrency
+	}
+	if d.AccountNumber == "" {
+		return resperror.ValidationRequired("account_number")
+	}
+
+	switch d.Country {
+	case "US":
+		if d.BankCode == "" {
+			return resperror.ValidationRequired("bank_code")
+		}
+		if !validator.ValidateABARoutingNumber(d.BankCode) {
+			return resperror.ErrorInvalidRoutingNumber
+		}
+		if !usAccountNumber.MatchString(d.AccountNumber) {
+			return resperror.ErrorInvalidAccountNumber
+		}
+	case "AU":
+		if d.BankCode == "" {
+			return resperror.ValidationRequired("bank_code")
+		}
+		if !validator.ValidateBSB(d.BankCode) {
+			return resperror.ErrorInvalidBSB
+		}
+		if !auAccountNumber.MatchString(d.AccountNumber) {
+			return resperror.ErrorInvalidAccountNumber
+		}
+	default:
+		if _, ok := validator.IBANLengths[d.Country]; ok {
+			if !strings.HasPrefix(d.AccountNumber, d.Country) || !validator.ValidateIBAN(d.AccountNumber) {
+				return resperror.ErrorInvalidIBAN
+			}
+			break
+		}
+		if !otherAccountNumber.MatchString(d.AccountNumber) {
+			return resperror.ErrorInvalidAccountNumber
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package merchantbusinesshours
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/businesshours"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/merchantbusinesshours"
+	"github.com/vegh1010/test/pkg/modelstore"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// HoursData - times are HH:MM in the merchant's timezone, hours closing
+// at or before they open close the next day
+type HoursData struct {
+	Day    string `json:"day"`
+	Opens  string `json:"opens"`
+	Closes string `json:"closes"`
+}
+
+// HolidayData - hours replacing the weekly hours on a date
+type HolidayData struct {
+	Date   string `json:"date"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+	Opens  string `json:"opens,omitempty"`
+	Closes string `json:"closes,omitempty"`
+}
+
+// Data -
+type Data struct {
+	Merchant string         `json:"merchant"`
+	Timezone string         `json:"timezone"`
+	Hours    []*HoursData   `json:"hours"`
+	Holidays []*HolidayData `json:"holidays"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// Handler -
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/merchants/{merchant_id}/business-hours",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+			LockResources: map[string]map[string]string{
+				http.MethodPut: {"merchant": "merchant_id"},
+			},
+		},
+	}
+	return &h
+}
+
+// days - weekdays by lower case name
+var days = map[string]time.Weekday{}
+
+func init() {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		days[dayName(d)] = d
+	}
+}
+
+func dayName(d time.Weekday) string {
+	return strings.ToLower(d.String())
+}
+
+// recordData -
+func recordData(mrec *merchant.Record, recs []*merchantbusinesshours.Record, holidays []*merchantbusinesshours.HolidayRecord) *Data {
+	hd := []*HoursData{}
+	for _, rec := range recs {
+		hd = append(hd, &HoursData{
+			Day:    dayName(time.Weekday(rec.DayOfWeek)),
+			Opens:  businesshours.Clock(rec.Opens).String(),
+			Closes: businesshours.Clock(rec.Closes).String(),
+		})
+	}
+	od := []*HolidayData{}
+	for _, rec := range holidays {
+		d := HolidayData{
+			Date:   rec.Date.Format(businesshours.DateFormat),
+			Name:   rec.Name.String,
+			Closed: !rec.Opens.Valid,
+		}
+		if rec.Opens.Valid {
+			d.Opens = businesshours.Clock(rec.Opens.Int64).String()
+			d.Closes = businesshours.Clock(rec.Closes.Int64).String()
+		}
+		od = append(od, &d)
+	}
+	return &Data{
+		Merchant: mrec.ID,
+		Timezone: mrec.TimezoneID,
+		Hours:    hd,
+		Holidays: od,
+	}
+}
+
+// records converts validated request hours and holidays to records
+func records(m *merchantbusinesshours.Model, req *Request) ([]*merchantbusinesshours.Record, []*merchantbusinesshours.HolidayRecord) {
+	recs := []*merchantbusinesshours.Record{}
+	for _, h := range req.Data.Hours {
+		hours, _ := parseHours(h.Opens, h.Closes)
+
+		rec := m.NewRecord()
+		rec.DayOfWeek = int(days[h.Day])
+		rec.Opens = int(hours.Opens)
+		rec.Closes = int(hours.Closes)
+		recs = append(recs, &rec)
+	}
+
+	holidays := []*merchantbusinesshours.HolidayRecord{}
+	for _, h := range req.Data.Holidays {
+		rec := m.NewHolidayRecord()
+		rec.Date, _ = time.Parse(businesshours.DateFormat, h.Date)
+		rec.Name = util.ToNullString(h.Name)
+		if !h.Closed {
+			hours, _ := parseHours(h.Opens, h.Closes)
+			rec.Opens = util.ToNullInt64(int64(hours.Opens))
+			rec.Closes = util.ToNullInt64(int64(hours.Closes))
+		}
+		holidays = append(holidays, &rec)
+	}
+
+	return recs, holidays
+}
+
+// getMerchant returns the merchant business hours belong to
+func (h *Handler) getMerchant(ms *modelstore.ModelStore, params handler.Params) (*merchant.Record, error) {
+
+	mm, err := ms.GetMerchantModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return mm.GetByID(params["merchant_id"].(string))
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantBusinessHoursModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Get with params %v", params)
+
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	recs, err := m.GetByMerchantID(mrec.ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	holidays, err := m.GetHolidays(mrec.ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(mrec, recs, holidays),
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Business hours fetched OK")
+}
+
+// Put - replaces the merchant's weekly hours and holidays
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantBusinessHoursModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	recs, holidays := records(m, &req)
+
+	// replace
+	err = m.Replace(mrec.ID, recs, holidays)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(mrec, recs, holidays),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Business hours updated OK")
+}
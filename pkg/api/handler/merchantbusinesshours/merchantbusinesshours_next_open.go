@@ -0,0 +1,110 @@
+package merchantbusinesshours
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vegh1010/test/pkg/businesshours"
+	"github.com/vegh1010/test/pkg/model/merchantbusinesshours"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// NextOpenData - times are RFC3339 in the merchant's timezone. OpensAt
+// and ClosesAt are empty when the merchant does not open within a year.
+type NextOpenData struct {
+	Merchant  string `json:"merchant"`
+	Timezone  string `json:"timezone"`
+	LocalTime string `json:"local_time"`
+	Open      bool   `json:"open"`
+	OpensAt   string `json:"opens_at"`
+	ClosesAt  string `json:"closes_at"`
+}
+
+// NextOpenResponse -
+type NextOpenResponse struct {
+	Data *NextOpenData `json:"data"`
+}
+
+// nextOpenData -
+func nextOpenData(merchantID, timezoneID string, at time.Time, loc *time.Location, win *businesshours.Window) *NextOpenData {
+	d := NextOpenData{
+		Merchant:  merchantID,
+		Timezone:  timezoneID,
+		LocalTime: at.In(loc).Format(time.RFC3339),
+	}
+	if win != nil {
+		d.Open = win.OpenAt(at)
+		d.OpensAt = win.Opens.In(loc).Format(time.RFC3339)
+		d.ClosesAt = win.Closes.In(loc).Format(time.RFC3339)
+	}
+	return &d
+}
+
+// NextOpen - when the merchant is next open from now or ?at=, the hours
+// it is open in when it is open at that time
+func (h *Handler) NextOpen(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("NextOpen with params %v", params)
+
+	at := time.Now()
+	if s := r.URL.Query().Get("at"); s != "" {
+		at, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			h.SendErrorResponse(w, r, resperror.ValidationTimestampFormat("at"))
+			return
+		}
+	}
+
+	// model
+	m, err := ms.GetMerchantBusinessHoursModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// zones removed from the tz database, or never in the host's copy,
+	// can not be loaded
+	loc, err := businesshours.Location(mrec.TimezoneID)
+	if err != nil {
+		log.Warn().Msgf("Could not load timezone %s of merchant %s: %v", mrec.TimezoneID, mrec.ID, err)
+		h.SendErrorResponse(w, r, resperror.ErrorUnknownTimezone)
+		return
+	}
+
+	recs, err := m.GetByMerchantID(mrec.ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	holidays, err := m.GetHolidays(mrec.ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	win := merchantbusinesshours.Schedule(recs, holidays).Next(at, loc)
+
+	res := NextOpenResponse{
+		Data: nextOpenData(mrec.ID, mrec.TimezoneID, at, loc, win),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Next open calculated OK")
+}
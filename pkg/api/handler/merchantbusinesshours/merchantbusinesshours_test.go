@@ -0,0 +1,74 @@
+package merchantbusinesshours
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/businesshours"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	hours := func(day, opens, closes string) *HoursData {
+		return &HoursData{Day: day, Opens: opens, Closes: closes}
+	}
+	holiday := func(date string, closed bool, opens, closes string) *HolidayData {
+		return &HolidayData{Date: date, Closed: closed, Opens: opens, Closes: closes}
+	}
+
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, nil},
+		{&Data{Hours: []*HoursData{hours("monday", "09:00", "17:00")}}, nil},
+		{&Data{Hours: []*HoursData{hours("friday", "22:00", "02:00")}}, nil},
+		{&Data{Hours: []*HoursData{hours("sunday", "00:00", "24:00")}}, nil},
+		{&Data{Hours: []*HoursData{hours("Monday", "09:00", "17:00")}}, resperror.ErrorInvalidBusinessHours},
+		{&Data{Hours: []*HoursData{hours("monday", "9:00", "17:00")}}, resperror.ErrorInvalidBusinessHours},
+		{&Data{Hours: []*HoursData{hours("monday", "09:00", "09:00")}}, resperror.ErrorInvalidBusinessHours},
+		{&Data{Hours: []*HoursData{hours("monday", "24:00", "02:00")}}, resperror.ErrorInvalidBusinessHours},
+		{&Data{Hours: []*HoursData{nil}}, resperror.ErrorInvalidBusinessHours},
+		{&Data{Holidays: []*HolidayData{holiday("2024-12-25", true, "", "")}}, nil},
+		{&Data{Holidays: []*HolidayData{holiday("2024-12-24", false, "09:00", "12:00")}}, nil},
+		{&Data{Holidays: []*HolidayData{holiday("25/12/2024", true, "", "")}}, resperror.ErrorInvalidHoliday},
+		{&Data{Holidays: []*HolidayData{holiday("2024-12-25", true, "09:00", "12:00")}}, resperror.ErrorInvalidHoliday},
+		{&Data{Holidays: []*HolidayData{holiday("2024-12-24", false, "", "")}}, resperror.ErrorInvalidHoliday},
+		{&Data{Holidays: []*HolidayData{holiday("2024-12-25", true, "", ""), holiday("2024-12-25", false, "09:00", "12:00")}}, resperror.ErrorInvalidHoliday},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
+
+func TestNextOpenData(t *testing.T) {
+	loc, err := businesshours.Location("Australia/Sydney")
+	if err != nil {
+		t.Skipf("No time zone Australia/Sydney: %v", err)
+	}
+
+	at := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	win := &businesshours.Window{
+		Opens:  time.Date(2024, time.January, 14, 22, 0, 0, 0, time.UTC),
+		Closes: time.Date(2024, time.January, 15, 6, 0, 0, 0, time.UTC),
+	}
+
+	d := nextOpenData("m1", "Australia/Sydney", at, loc, win)
+	assert.Equal(t, "2024-01-15T11:00:00+11:00", d.LocalTime)
+	assert.True(t, d.Open)
+	assert.Equal(t, "2024-01-15T09:00:00+11:00", d.OpensAt)
+	assert.Equal(t, "2024-01-15T17:00:00+11:00", d.ClosesAt)
+
+	d = nextOpenData("m1", "Australia/Sydney", at, loc, nil)
+	assert.False(t, d.Open)
+	assert.Equal(t, "", d.OpensAt)
+}
@@ -0,0 +1,78 @@
+package merchantbusinesshours
+
+import (
+	"time"
+
+	"github.com/vegh1010/test/pkg/businesshours"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// maxNameLength - holiday names are stored as VARCHAR(100)
+const maxNameLength = 100
+
+// parseHours parses opens and closes times, HH:MM
+func parseHours(opens, closes string) (businesshours.Hours, error) {
+	o, err := businesshours.ParseClock(opens)
+	if err != nil {
+		return businesshours.Hours{}, err
+	}
+	c, err := businesshours.ParseClock(closes)
+	if err != nil {
+		return businesshours.Hours{}, err
+	}
+	h := businesshours.Hours{Opens: o, Closes: c}
+	if !h.Valid() {
+		return businesshours.Hours{}, businesshours.ErrInvalidClock
+	}
+	return h, nil
+}
+
+// Validate validates business hours request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	for _, h := range req.Data.Hours {
+		if h == nil {
+			return resperror.ErrorInvalidBusinessHours
+		}
+		if _, ok := days[h.Day]; !ok {
+			return resperror.ErrorInvalidBusinessHours
+		}
+		if _, err := parseHours(h.Opens, h.Closes); err != nil {
+			return resperror.ErrorInvalidBusinessHours
+		}
+	}
+
+	// a date is either closed or has hours
+	closed := map[string]bool{}
+	for _, h := range req.Data.Holidays {
+		if h == nil {
+			return resperror.ErrorInvalidHoliday
+		}
+		if _, err := time.Parse(businesshours.DateFormat, h.Date); err != nil {
+			return resperror.ErrorInvalidHoliday
+		}
+		if len(h.Name) > maxNameLength {
+			return resperror.ValidationInvalid("name")
+		}
+		if c, ok := closed[h.Date]; ok && c != h.Closed {
+			return resperror.ErrorInvalidHoliday
+		}
+		closed[h.Date] = h.Closed
+
+		if h.Closed {
+			if h.Opens != "" || h.Closes != "" {
+				return resperror.ErrorInvalidHoliday
+			}
+			continue
+		}
+		if _, err := parseHours(h.Opens, h.Closes); err != nil {
+			return resperror.ErrorInvalidHoliday
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package merchantcontact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("role")},
+		{&Data{Role: "sales"}, resperror.ErrorInvalidContactRole},
+		{&Data{Role: "billing"}, resperror.ValidationRequired("name")},
+		{&Data{Role: "billing", Name: "Jo"}, resperror.ValidationRequired("email")},
+		{&Data{Role: "billing", Name: "Jo", Email: "jo"}, resperror.ErrorInvalidEmail},
+		{&Data{Role: "billing", Name: "Jo", Email: "jo@example.com", Phone: "555 1234"}, resperror.ErrorInvalidPhone},
+		{&Data{Role: "billing", Name: "Jo", Email: "jo@example.com"}, nil},
+		{&Data{Role: "legal", Name: "Jo", Email: "jo@example.com", Phone: "+61291234567"}, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
@@ -0,0 +1,37 @@
+package merchantcontact
+
+import (
+	"github.com/vegh1010/test/pkg/model/merchantcontact"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+	"github.com/vegh1010/test/pkg/validator"
+)
+
+// Validate validates merchant contact request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	if req.Data.Role == "" {
+		return resperror.ValidationRequired("role")
+	}
+	if !util.StringInSlice(req.Data.Role, merchantcontact.Roles) {
+		return resperror.ErrorInvalidContactRole
+	}
+	if req.Data.Name == "" {
+		return resperror.ValidationRequired("name")
+	}
+	if req.Data.Email == "" {
+		return resperror.ValidationRequired("email")
+	}
+	if !validator.ValidateEmail(req.Data.Email) {
+		return resperror.ErrorInvalidEmail
+	}
+	if req.Data.Phone != "" && !validator.ValidatePhone(req.Data.Phone) {
+		return resperror.ErrorInvalidPhone
+	}
+
+	return nil
+}
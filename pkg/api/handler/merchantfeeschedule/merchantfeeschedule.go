@@ -0,0 +1,509 @@
+package merchantfeeschedule
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/merchantfeeschedule"
+	"github.com/vegh1010/test/pkg/modelstore"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+	"github.com/vegh1010/test/pkg/util/decimalutil"
+)
+
+// TierData - amounts and percentages are exact decimal strings
+type TierData struct {
+	MinAmount  string `json:"min_amount"`
+	Percentage string `json:"percentage"`
+	FixedFee   string `json:"fixed_fee"`
+}
+
+// Data -
+type Data struct {
+	ID        string      `json:"id"`
+	Merchant  string      `json:"merchant"`
+	Currency  string      `json:"currency"`
+	Tiers     []*TierData `json:"tiers"`
+	CreatedAt string      `json:"created_at"`
+	UpdatedAt string      `json:"updated_at"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// CollectionResponse -
+type CollectionResponse struct {
+	Data []*Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// Handler -
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/merchants/{merchant_id}/fee-schedules",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+			LockResources: map[string]map[string]string{
+				http.MethodPut: {"merchant_fee_schedule": "id"},
+			},
+		},
+	}
+	return &h
+}
+
+// recordData - amounts are formatted with the currency's minor units,
+// which fails when they are not known
+func recordData(rec *merchantfeeschedule.Record, tiers []*merchantfeeschedule.TierRecord) (*Data, error) {
+	td := []*TierData{}
+	for _, t := range tiers {
+		minAmount, err := decimalutil.MinorUnitsToString(t.MinAmount, rec.Currency)
+		if err != nil {
+			return nil, err
+		}
+		fixedFee, err := decimalutil.MinorUnitsToString(t.FixedFee, rec.Currency)
+		if err != nil {
+			return nil, err
+		}
+		td = append(td, &TierData{
+			MinAmount:  minAmount,
+			Percentage: t.Percentage.String(),
+			FixedFee:   fixedFee,
+		})
+	}
+	return &Data{
+		ID:        rec.ID,
+		Merchant:  rec.MerchantID,
+		Currency:  rec.Currency,
+		Tiers:     td,
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt.String,
+	}, nil
+}
+
+// tierRecords converts validated request tiers to minor unit records
+func tierRecords(m *merchantfeeschedule.Model, req *Request) ([]*merchantfeeschedule.TierRecord, error) {
+	recs := []*merchantfeeschedule.TierRecord{}
+	for _, t := range req.Data.Tiers {
+		rec := m.NewTierRecord()
+
+		var err error
+		rec.MinAmount, err = parseAmount("min_amount", t.MinAmount, req.Data.Currency)
+		if err != nil {
+			return nil, err
+		}
+		rec.Percentage, err = parsePercentage(t.Percentage)
+		if err != nil {
+			return nil, err
+		}
+		rec.FixedFee, err = parseAmount("fixed_fee", t.FixedFee, req.Data.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		recs = append(recs, &rec)
+	}
+	return recs, nil
+}
+
+// getMerchant returns the merchant a fee schedule belongs to
+func (h *Handler) getMerchant(ms *modelstore.ModelStore, params handler.Params) (*merchant.Record, error) {
+
+	mm, err := ms.GetMerchantModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return mm.GetByID(params["merchant_id"].(string))
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantFeeScheduleModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Get with params %v", params)
+
+	// get
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	tiers, err := m.GetTiers(recs[0].ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := recordData(recs[0], tiers)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: data,
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Fee schedule fetched OK")
+}
+
+// GetCollection -
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantFeeScheduleModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetCollection with params %v", params)
+
+	// merchant must exist
+	_, err = h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	ed := []*Data{}
+	for _, rec := range recs {
+		tiers, err := m.GetTiers(rec.ID)
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
+		}
+		data, err := recordData(rec, tiers)
+		if err != nil {
+			h.SendErrorResponse(w, r, err)
+			return
+		}
+		ed = append(ed, data)
+	}
+
+	res := CollectionResponse{
+		Data: ed,
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Fee schedules fetched OK")
+}
+
+// Post -
+func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantFeeScheduleModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	if !util.StringInSlice(req.Data.Currency, mrec.Currencies) {
+		h.SendErrorResponse(w, r, resperror.ErrorUnsupportedCurrency)
+		return
+	}
+
+	// record
+	rec := m.NewRecord()
+	rec.MerchantID = mrec.ID
+	rec.Currency = req.Data.Currency
+
+	tiers, err := tierRecords(m, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	vrec, err := m.ValidateRecord(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if vrec.DuplicateCurrency.Bool {
+		h.SendErrorResponse(w, r, resperror.ErrorDuplicateFeeSchedule)
+		return
+	}
+
+	// create
+	err = m.Create(&rec, tiers)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := recordData(&rec, tiers)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: data,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Fee schedule created OK")
+}
+
+// Put -
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantFeeScheduleModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// record
+	rec := recs[0]
+
+	// merchant must not be terminated
+	mrec, err := h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if mrec.Status == merchant.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedMerchantCannotBeModified)
+		return
+	}
+
+	if !util.StringInSlice(req.Data.Currency, mrec.Currencies) {
+		h.SendErrorResponse(w, r, resperror.ErrorUnsupportedCurrency)
+		return
+	}
+
+	// update record properties
+	rec.Currency = req.Data.Currency
+
+	tiers, err := tierRecords(m, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	vrec, err := m.ValidateRecord(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if vrec.DuplicateCurrency.Bool {
+		h.SendErrorResponse(w, r, resperror.ErrorDuplicateFeeSchedule)
+		return
+	}
+
+	// update
+	err = m.Update(rec, tiers)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := recordData(rec, tiers)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: data,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Fee schedule updated OK")
+}
+
+// Delete -
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Delete fee schedule with params %v", params)
+
+	// model
+	m, err := ms.GetMerchantFeeScheduleModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// get current record
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	// delete
+	err = m.Delete(recs[0].ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Fee schedule deleted OK")
+
+	h.SendResponse(w, r, nil)
+}
@@ -0,0 +1,148 @@
+package merchantfeeschedule
+
+import (
+	"net/http"
+
+	"github.com/vegh1010/test/pkg/fees"
+	"github.com/vegh1010/test/pkg/model/merchantfeeschedule"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util/decimalutil"
+	"github.com/vegh1010/test/pkg/validator"
+)
+
+// CalculationData - amounts are exact decimal strings in the currency
+type CalculationData struct {
+	Currency      string `json:"currency"`
+	Amount        string `json:"amount"`
+	Percentage    string `json:"percentage"`
+	PercentageFee string `json:"percentage_fee"`
+	FixedFee      string `json:"fixed_fee"`
+	Fee           string `json:"fee"`
+	Net           string `json:"net"`
+}
+
+// CalculationResponse -
+type CalculationResponse struct {
+	Data *CalculationData `json:"data"`
+}
+
+// calculationData -
+func calculationData(currency string, fee *fees.Fee) (*CalculationData, error) {
+	data := &CalculationData{
+		Currency:   currency,
+		Percentage: fee.Tier.Percentage.String(),
+	}
+
+	amounts := []struct {
+		str   *string
+		units int64
+	}{
+		{&data.Amount, fee.Amount},
+		{&data.PercentageFee, fee.PercentageFee},
+		{&data.FixedFee, fee.FixedFee},
+		{&data.Fee, fee.Total},
+		{&data.Net, fee.Net},
+	}
+	for _, a := range amounts {
+		str, err := decimalutil.MinorUnitsToString(a.units, currency)
+		if err != nil {
+			return nil, err
+		}
+		*a.str = str
+	}
+
+	return data, nil
+}
+
+// Calculate - fee the merchant is charged for ?amount= in ?currency=
+// using the merchant's fee schedule for the currency
+func (h *Handler) Calculate(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Calculate with params %v", params)
+
+	q := r.URL.Query()
+
+	currency := q.Get("currency")
+	if currency == "" {
+		h.SendErrorResponse(w, r, resperror.ValidationRequired("currency"))
+		return
+	}
+	if !validator.ValidateCurrencyCode(currency) {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidCurrency)
+		return
+	}
+
+	amount, err := parseAmount("amount", q.Get("amount"), currency)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+	if amount == 0 {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidAmount)
+		return
+	}
+
+	// model
+	m, err := ms.GetMerchantFeeScheduleModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// merchant must exist
+	_, err = h.getMerchant(ms, params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	recs, err := m.GetByParam(map[string]interface{}{
+		"merchant_id": params["merchant_id"],
+		"currency":    currency,
+	})
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// no fee schedule for the currency
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	tiers, err := m.GetTiers(recs[0].ID)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	fee, err := fees.Calculate(merchantfeeschedule.Tiers(tiers), amount, currency)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := calculationData(currency, fee)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := CalculationResponse{
+		Data: data,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Fee calculated OK")
+}
@@ -0,0 +1,66 @@
+package merchantfeeschedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util/decimalutil"
+)
+
+// minor units as loaded from the currency table
+func init() {
+	decimalutil.SetExponents(map[string]int32{"AUD": 2, "JPY": 0, "KWD": 3})
+}
+
+func TestValidate(t *testing.T) {
+	tier := func(min, pct, fixed string) *TierData {
+		return &TierData{MinAmount: min, Percentage: pct, FixedFee: fixed}
+	}
+
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("currency")},
+		{&Data{Currency: "aud"}, resperror.ErrorInvalidCurrency},
+		// minor units not loaded
+		{&Data{Currency: "XXX"}, resperror.ErrorInvalidCurrency},
+		{&Data{Currency: "AUD"}, resperror.ValidationRequired("tiers")},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("", "2.9", "0.30")}}, resperror.ValidationRequired("min_amount")},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("-1", "2.9", "0.30")}}, resperror.ErrorInvalidAmount},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("0", "2.9", "abc")}}, resperror.ErrorInvalidAmount},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("0", "2.9", "0.301")}}, resperror.ErrorInvalidAmountPrecision},
+		{&Data{Currency: "JPY", Tiers: []*TierData{tier("0", "2.9", "0.5")}}, resperror.ErrorInvalidAmountPrecision},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("0", "101", "0.30")}}, resperror.ErrorInvalidPercentage},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("0", "2.12345", "0.30")}}, resperror.ErrorInvalidPercentage},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("10.00", "2.9", "0.30")}}, resperror.ErrorInvalidFeeTiers},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("0", "2.9", "0.30"), tier("0.00", "1.5", "0.10")}}, resperror.ErrorInvalidFeeTiers},
+		{&Data{Currency: "AUD", Tiers: []*TierData{tier("0", "2.9", "0.30"), tier("1000.00", "1.5", "0.10")}}, nil},
+		{&Data{Currency: "JPY", Tiers: []*TierData{tier("0", "3.6", "30")}}, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	u, err := parseAmount("amount", "0.29", "AUD")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(29), u)
+
+	u, err = parseAmount("amount", "1.234", "KWD")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234), u)
+
+	_, err = parseAmount("amount", "1.00", "XXX")
+	assert.Equal(t, resperror.ErrorInvalidCurrency, err)
+}
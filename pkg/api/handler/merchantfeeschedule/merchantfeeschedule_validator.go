@@ -0,0 +1,94 @@
+package merchantfeeschedule
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util/decimalutil"
+	"github.com/vegh1010/test/pkg/validator"
+)
+
+// maxPercentage - percentages are stored as NUMERIC(7,4) from 0 to 100
+var maxPercentage = decimal.New(100, 0)
+
+// parseAmount parses a decimal string amount into minor units of the
+// currency, amounts may not be negative or have more decimal places
+// than the currency allows. Currencies without known minor units are
+// invalid.
+func parseAmount(field, amount, currency string) (int64, error) {
+	if _, ok := decimalutil.Exponent(currency); !ok {
+		return 0, resperror.ErrorInvalidCurrency
+	}
+	if amount == "" {
+		return 0, resperror.ValidationRequired(field)
+	}
+	d, err := decimalutil.DecimalFromString(amount)
+	if err != nil || d.Sign() < 0 {
+		return 0, resperror.ErrorInvalidAmount
+	}
+	if !decimalutil.ValidPrecision(d, currency) {
+		return 0, resperror.ErrorInvalidAmountPrecision
+	}
+	return decimalutil.DecimalToMinorUnits(d, currency)
+}
+
+// parsePercentage parses a decimal string percentage from 0 to 100
+func parsePercentage(percentage string) (decimal.Decimal, error) {
+	if percentage == "" {
+		return decimal.Decimal{}, resperror.ValidationRequired("percentage")
+	}
+	d, err := decimalutil.DecimalFromString(percentage)
+	if err != nil || d.Sign() < 0 || d.Cmp(maxPercentage) > 0 || !d.Equal(d.Truncate(4)) {
+		return decimal.Decimal{}, resperror.ErrorInvalidPercentage
+	}
+	return d, nil
+}
+
+// Validate validates merchant fee schedule request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	if req.Data.Currency == "" {
+		return resperror.ValidationRequired("currency")
+	}
+	if !validator.ValidateCurrencyCode(req.Data.Currency) {
+		return resperror.ErrorInvalidCurrency
+	}
+	// amounts can not be converted to minor units
+	if _, ok := decimalutil.Exponent(req.Data.Currency); !ok {
+		return resperror.ErrorInvalidCurrency
+	}
+	if len(req.Data.Tiers) == 0 {
+		return resperror.ValidationRequired("tiers")
+	}
+
+	seen := map[int64]bool{}
+	for _, t := range req.Data.Tiers {
+		if t == nil {
+			return resperror.ErrorInvalidFeeTiers
+		}
+		minAmount, err := parseAmount("min_amount", t.MinAmount, req.Data.Currency)
+		if err != nil {
+			return err
+		}
+		if _, err := parsePercentage(t.Percentage); err != nil {
+			return err
+		}
+		if _, err := parseAmount("fixed_fee", t.FixedFee, req.Data.Currency); err != nil {
+			return err
+		}
+		if seen[minAmount] {
+			return resperror.ErrorInvalidFeeTiers
+		}
+		seen[minAmount] = true
+	}
+
+	// every amount must fall in a tier
+	if !seen[0] {
+		return resperror.ErrorInvalidFeeTiers
+	}
+
+	return nil
+}
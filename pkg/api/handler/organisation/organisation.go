@@ -0,0 +1,467 @@
+package organisation
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/organisation"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Data -
+type Data struct {
+	ID        string `json:"id"`
+	Parent    string `json:"parent"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// CollectionResponse -
+type CollectionResponse struct {
+	Data []*Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// SummaryData - counts by status of everything beneath an organisation,
+// including the organisation itself
+type SummaryData struct {
+	Organisations map[string]int `json:"organisations"`
+	Merchants     map[string]int `json:"merchants"`
+	Locations     map[string]int `json:"locations"`
+}
+
+// SummaryResponse -
+type SummaryResponse struct {
+	Data *SummaryData `json:"data"`
+}
+
+// Handler -
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/organisations",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+			LockResources: map[string]map[string]string{
+				http.MethodPut: {"organisation": "id"},
+			},
+		},
+	}
+	return &h
+}
+
+// recordData -
+func recordData(rec *organisation.Record) *Data {
+	return &Data{
+		ID:        rec.ID,
+		Parent:    rec.ParentID.String,
+		Name:      rec.Name,
+		Status:    rec.Status,
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt.String,
+	}
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetOrganisationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Get with params %v", params)
+
+	// get
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	res := Response{
+		Data: recordData(recs[0]),
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Organisation fetched OK")
+}
+
+// GetCollection - organisations, or with ?parent=<id> the organisations
+// directly beneath an organisation
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetOrganisationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if parent := r.URL.Query().Get("parent"); parent != "" {
+		params["parent_id"] = parent
+	}
+
+	log.Debug().Msgf("GetCollection with params %v", params)
+
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	ed := []*Data{}
+	for _, rec := range recs {
+		ed = append(ed, recordData(rec))
+	}
+
+	res := CollectionResponse{
+		Data: ed,
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Organisations fetched OK")
+}
+
+// GetSummary - counts of organisations, merchants and locations by status
+// across an organisation's subtree
+func (h *Handler) GetSummary(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetOrganisationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetSummary with params %v", params)
+
+	// organisation must exist
+	_, err = m.GetByID(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	recs, err := m.GetSummary(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	sd := &SummaryData{
+		Organisations: map[string]int{},
+		Merchants:     map[string]int{},
+		Locations:     map[string]int{},
+	}
+
+	for _, rec := range recs {
+		switch rec.EntityType {
+		case "organisation":
+			sd.Organisations[rec.Status] = rec.Count
+		case "merchant":
+			sd.Merchants[rec.Status] = rec.Count
+		case "location":
+			sd.Locations[rec.Status] = rec.Count
+		}
+	}
+
+	res := SummaryResponse{
+		Data: sd,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Organisation summary fetched OK")
+}
+
+// Post -
+func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// model
+	m, err := ms.GetOrganisationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// record
+	rec := m.NewRecord()
+	rec.ParentID = util.ToNullString(req.Data.Parent)
+	rec.Name = req.Data.Name
+
+	verr = h.validateParent(m, &rec)
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// create
+	err = m.Create(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(&rec),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Organisation created OK")
+}
+
+// Put - terminating an organisation terminates everything beneath it
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetOrganisationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+	if !util.StringInSlice(req.Data.Status, []string{organisation.StatusActive, organisation.StatusInactive, organisation.StatusTerminated}) {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidOrganisationStatus)
+		return
+	}
+
+	// get current record
+	rec, err := m.GetByID(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if rec.Status == organisation.StatusTerminated {
+		h.SendErrorResponse(w, r, resperror.ErrTerminatedOrganisationCannotBeModified)
+		return
+	}
+
+	// update record properties
+	rec.ParentID = util.ToNullString(req.Data.Parent)
+	rec.Name = req.Data.Name
+	rec.Status = req.Data.Status
+
+	verr = h.validateParent(m, rec)
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// update
+	err = m.Update(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(rec),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Organisation updated OK")
+}
+
+// Delete - only organisations with nothing beneath them can be deleted
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Delete organisation with params %v", params)
+
+	// model
+	m, err := ms.GetOrganisationModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// get current record
+	_, err = m.GetByID(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	has, err := m.HasChildren(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+	if has {
+		h.SendErrorResponse(w, r, resperror.ErrOrganisationHasChildren)
+		return
+	}
+
+	// delete
+	err = m.Delete(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Organisation deleted OK")
+
+	h.SendResponse(w, r, nil)
+}
+
+// validateParent checks a parent organisation exists, has not been
+// terminated and is not the organisation itself or beneath it
+func (h *Handler) validateParent(m *organisation.Model, rec *organisation.Record) error {
+
+	if !rec.ParentID.Valid {
+		return nil
+	}
+
+	if !util.ValidateUUID(rec.ParentID.String) {
+		return resperror.ErrorInvalidOrganisationParent
+	}
+
+	parent, err := m.GetByID(rec.ParentID.String)
+	if err != nil || parent.Status == organisation.StatusTerminated {
+		return resperror.ErrorInvalidOrganisationParent
+	}
+
+	if rec.ID == "" {
+		return nil
+	}
+
+	cyclic, err := m.IsDescendant(parent.ID, rec.ID)
+	if err != nil {
+		return err
+	}
+	if cyclic {
+		return resperror.ErrorInvalidOrganisationParent
+	}
+
+	return nil
+}
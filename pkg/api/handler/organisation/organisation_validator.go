@@ -0,0 +1,19 @@
+package organisation
+
+import (
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// Validate validates organisation request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	if req.Data.Name == "" {
+		return resperror.ValidationRequired("name")
+	}
+
+	return nil
+}
@@ -0,0 +1,442 @@
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/webhook"
+	"github.com/vegh1010/test/pkg/model/webhookdelivery"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Data -
+type Data struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+	Secret      string   `json:"secret"`
+	Status      string   `json:"status"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// Response -
+type Response struct {
+	Data *Data `json:"data"`
+}
+
+// CollectionResponse -
+type CollectionResponse struct {
+	Data []*Data `json:"data"`
+}
+
+// Request -
+type Request struct {
+	Data *Data `json:"data"`
+}
+
+// DeliveryData -
+type DeliveryData struct {
+	ID             string `json:"id"`
+	Sequence       int64  `json:"sequence"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	EventSequence  int64  `json:"event_sequence"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  string `json:"next_attempt_at"`
+	ResponseStatus int64  `json:"response_status"`
+	LastError      string `json:"last_error"`
+	DeliveredAt    string `json:"delivered_at"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// DeliveryCollectionResponse -
+type DeliveryCollectionResponse struct {
+	Data []*DeliveryData `json:"data"`
+}
+
+// Handler -
+type Handler struct {
+	handler.Base
+}
+
+// NewHandler -
+func NewHandler(e *env.Env, l zerolog.Logger) handler.Handler {
+	h := Handler{
+		handler.Base{
+			Path:            "/api/webhooks",
+			Unauthenticated: false, // Requires authentication
+			Unauthorized:    false, // Requires authorization
+			Versioned:       true,
+			Env:             e,
+			Logger:          l,
+			LockResources: map[string]map[string]string{
+				http.MethodPut: {"webhook": "id"},
+			},
+		},
+	}
+	return &h
+}
+
+// recordData - the secret is only ever returned in full when the webhook
+// is created, otherwise it is masked.
+func recordData(rec *webhook.Record, showSecret bool) *Data {
+	secret := util.MaskLastFourCharactersClear(rec.Secret)
+	if showSecret {
+		secret = rec.Secret
+	}
+	eventTypes := []string(rec.EventTypes)
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return &Data{
+		ID:          rec.ID,
+		URL:         rec.URL,
+		Description: rec.Description,
+		EventTypes:  eventTypes,
+		Secret:      secret,
+		Status:      rec.Status,
+		CreatedAt:   rec.CreatedAt,
+		UpdatedAt:   rec.UpdatedAt.String,
+	}
+}
+
+func deliveryData(rec *webhookdelivery.Record) *DeliveryData {
+	return &DeliveryData{
+		ID:             rec.ID,
+		Sequence:       rec.Sequence,
+		EventID:        rec.OutboxEventID,
+		EventType:      rec.EventType,
+		EventSequence:  rec.EventSequence,
+		Status:         rec.Status,
+		Attempts:       rec.Attempts,
+		NextAttemptAt:  rec.NextAttemptAt,
+		ResponseStatus: rec.ResponseStatus.Int64,
+		LastError:      rec.LastError.String,
+		DeliveredAt:    rec.DeliveredAt.String,
+		CreatedAt:      rec.CreatedAt,
+	}
+}
+
+// Get -
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetWebhookModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Get with params %v", params)
+
+	// get
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	if len(recs) != 1 {
+		// not found
+		h.SendErrorResponse(w, r, resperror.ErrorNotFound)
+		return
+	}
+
+	res := Response{
+		Data: recordData(recs[0], false),
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Webhook fetched OK")
+}
+
+// GetCollection -
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetWebhookModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetCollection with params %v", params)
+
+	recs, err := m.GetByParam(params)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	ed := []*Data{}
+	for _, rec := range recs {
+		ed = append(ed, recordData(rec, false))
+	}
+
+	res := CollectionResponse{
+		Data: ed,
+	}
+
+	h.DebugStruct("Get Response", res)
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Webhooks fetched OK")
+}
+
+// GetDeliveries - the delivery log for a webhook
+func (h *Handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetWebhookModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	dm, err := ms.GetWebhookDeliveryModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("GetDeliveries with params %v", params)
+
+	// webhook must exist
+	_, err = m.GetByID(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	dparams := map[string]interface{}{"webhook_id": params["id"]}
+	if status := r.URL.Query().Get("status"); status != "" {
+		dparams["status"] = status
+	}
+
+	recs, err := dm.GetByParam(dparams)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	dd := []*DeliveryData{}
+	for _, rec := range recs {
+		dd = append(dd, deliveryData(rec))
+	}
+
+	res := DeliveryCollectionResponse{
+		Data: dd,
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Webhook deliveries fetched OK")
+}
+
+// Post -
+func (h *Handler) Post(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Post with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+
+	// model
+	m, err := ms.GetWebhookModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// record
+	rec := m.NewRecord()
+	rec.URL = req.Data.URL
+	rec.Description = req.Data.Description
+	rec.EventTypes = req.Data.EventTypes
+
+	// create
+	err = m.Create(&rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(&rec, true),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Webhook created OK")
+}
+
+// Put -
+func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// model
+	m, err := ms.GetWebhookModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with params %v", params)
+
+	// decode request body
+	req := Request{}
+	err = h.DecodeRequest(r, &req)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Put with data %v", req)
+
+	// validate
+	verr := req.Validate()
+	if verr != nil {
+		h.SendErrorResponse(w, r, verr)
+		return
+	}
+	if req.Data.Status != webhook.StatusActive && req.Data.Status != webhook.StatusInactive {
+		h.SendErrorResponse(w, r, resperror.ErrorInvalidWebhookStatus)
+		return
+	}
+
+	// get current record
+	rec, err := m.GetByID(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// update record properties
+	rec.URL = req.Data.URL
+	rec.Description = req.Data.Description
+	rec.EventTypes = req.Data.EventTypes
+	rec.Status = req.Data.Status
+
+	// update
+	err = m.Update(rec)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	res := Response{
+		Data: recordData(rec, false),
+	}
+
+	h.SendResponse(w, r, &res)
+
+	log.Debug().Msgf("Webhook updated OK")
+}
+
+// Delete -
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// logger
+	log := h.Logger
+
+	ms, params, err := h.PreHandlerChecks(r)
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Delete webhook with params %v", params)
+
+	// model
+	m, err := ms.GetWebhookModel()
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// get current record
+	_, err = m.GetByID(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	// delete
+	err = m.Delete(params["id"].(string))
+	if err != nil {
+		h.SendErrorResponse(w, r, err)
+		return
+	}
+
+	log.Debug().Msgf("Webhook deleted OK")
+
+	h.SendResponse(w, r, nil)
+}
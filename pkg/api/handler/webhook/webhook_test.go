@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data *Data
+		err  error
+	}{
+		{nil, resperror.ValidationRequired("request data")},
+		{&Data{}, resperror.ValidationRequired("url")},
+		{&Data{URL: "ftp://example.com"}, resperror.ErrorInvalidWebhookURL},
+		{&Data{URL: "/relative"}, resperror.ErrorInvalidWebhookURL},
+		{&Data{URL: "https://example.com/hook", EventTypes: []string{"nope"}}, resperror.ErrorInvalidWebhookEventType},
+		{&Data{URL: "https://example.com/hook", EventTypes: []string{"*"}}, nil},
+		{&Data{URL: "https://example.com/hook", EventTypes: []string{"merchant.created"}}, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Data: tt.data}
+		err := req.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, tt.err, err)
+	}
+}
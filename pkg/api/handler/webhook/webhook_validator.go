@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"net/url"
+
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/webhook"
+	"github.com/vegh1010/test/pkg/resperror"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Validate validates webhook request Data.
+func (req *Request) Validate() error {
+	// First check if data is present.
+	if req.Data == nil {
+		return resperror.ValidationRequired("request data")
+	}
+
+	if req.Data.URL == "" {
+		return resperror.ValidationRequired("url")
+	}
+
+	u, err := url.Parse(req.Data.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return resperror.ErrorInvalidWebhookURL
+	}
+
+	for _, et := range req.Data.EventTypes {
+		if et != webhook.EventTypeAll && !util.StringInSlice(et, merchant.EventTypes) {
+			return resperror.ErrorInvalidWebhookEventType
+		}
+	}
+
+	return nil
+}
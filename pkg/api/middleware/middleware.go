@@ -5,28 +5,61 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog"
 	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/middleware/auth"
+	"github.com/vegh1010/test/pkg/middleware/ratelimit"
+	"github.com/vegh1010/test/pkg/middleware/requestid"
 	"github.com/vegh1010/test/pkg/middleware/tx"
 	"github.com/vegh1010/test/pkg/env"
 )
 
 // Middleware -
 type Middleware struct {
-	e  *env.Env
-	l  zerolog.Logger
-	db *sqlx.DB
+	e       *env.Env
+	l       zerolog.Logger
+	db      *sqlx.DB
+	limiter *ratelimit.Limiter
+	decode  *handler.DecodeOptions
 }
 
 // NewMiddleware returns a handler with all appropriate middleware applied to a specified handler.
-func NewMiddleware(e *env.Env, l zerolog.Logger, db *sqlx.DB) *Middleware {
-	return &Middleware{e: e, l: l, db: db}
+func NewMiddleware(e *env.Env, l zerolog.Logger, db *sqlx.DB) (*Middleware, error) {
+
+	// rate limits are shared by all routes
+	lim, err := ratelimit.NewLimiter(e, db)
+	if err != nil {
+		return nil, err
+	}
+
+	// request body decoding, resolved once rather than on each request
+	do := handler.NewDecodeOptions(e)
+
+	return &Middleware{e: e, l: l, db: db, limiter: lim, decode: do}, nil
 }
 
 // Apply - Applies selected middleware to handler chain
+//
+// Middleware is applied inside out, the last applied runs first.
 func (mw *Middleware) Apply(h handler.Handler, hf http.HandlerFunc, path string) http.Handler {
 	var nh http.Handler = hf
 
+	// request body decoding
+	h.SetDecodeOptions(mw.decode)
+
 	// tx
 	nh = tx.NewTx(mw.e, mw.l, mw.db, nh)
 
+	// rate limit - before the tx so rejected requests do not begin one.
+	// The postgres store takes both buckets in a single statement outside
+	// the request tx, using a pool connection only for that statement.
+	if mw.limiter != nil {
+		nh = ratelimit.NewRateLimit(mw.e, mw.l, mw.limiter, nh)
+	}
+
+	// auth
+	nh = auth.NewAuth(mw.e, mw.l, mw.db, h, nh)
+
+	// request id
+	nh = requestid.NewRequestID(mw.l, nh)
+
 	return nh
 }
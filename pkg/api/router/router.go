@@ -7,8 +7,20 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog"
 	"github.com/vegh1010/test/pkg/api/middleware"
+	"github.com/vegh1010/test/pkg/middleware/compress"
+	"github.com/vegh1010/test/pkg/middleware/cors"
 	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/featureflag"
+	"github.com/vegh1010/test/pkg/api/handler/location"
+	"github.com/vegh1010/test/pkg/api/handler/loglevel"
 	"github.com/vegh1010/test/pkg/api/handler/merchant"
+	"github.com/vegh1010/test/pkg/api/handler/merchantaddress"
+	"github.com/vegh1010/test/pkg/api/handler/merchantbankaccount"
+	"github.com/vegh1010/test/pkg/api/handler/merchantcontact"
+	"github.com/vegh1010/test/pkg/api/handler/merchantfeeschedule"
+	"github.com/vegh1010/test/pkg/api/handler/merchantbusinesshours"
+	"github.com/vegh1010/test/pkg/api/handler/organisation"
+	"github.com/vegh1010/test/pkg/api/handler/webhook"
 )
 
 // Router -
@@ -41,17 +53,110 @@ func (rt *Router) init() error {
 
 	m := mux.NewRouter()
 
-	mw := middleware.NewMiddleware(rt.Env, rt.Logger, rt.db)
+	mw, err := middleware.NewMiddleware(rt.Env, rt.Logger, rt.db)
+	if err != nil {
+		return err
+	}
+
+	// feature flags, checked by handlers with FeatureEnabled, such as
+	// merchant PATCH
+	featureflag.SetDefault(featureflag.NewStore(rt.Env, rt.Logger, rt.db))
 
 	// Merchants
 	mh := merchant.NewHandler(rt.Env, rt.Logger)
 	m.Handle(mh.GetPath(), mw.Apply(mh, mh.Post, "merchants")).Methods(http.MethodPost)
 	m.Handle(mh.GetPath(), mw.Apply(mh, mh.GetCollection, "merchants")).Methods(http.MethodGet)
+	m.Handle(mh.GetPath()+"/search", mw.Apply(mh, mh.(*merchant.Handler).Search, "merchants")).Methods(http.MethodGet)
 	m.Handle(mh.GetPath()+"/{id}", mw.Apply(mh, mh.Get, "merchants")).Methods(http.MethodGet)
 	m.Handle(mh.GetPath()+"/{id}", mw.Apply(mh, mh.Delete, "merchants")).Methods(http.MethodDelete)
 	m.Handle(mh.GetPath()+"/{id}", mw.Apply(mh, mh.Put, "merchants")).Methods(http.MethodPut)
+	m.Handle(mh.GetPath()+"/{id}", mw.Apply(mh, mh.(*merchant.Handler).Patch, "merchants")).Methods(http.MethodPatch)
+	m.Handle(mh.GetPath()+"/{id}/restore", mw.Apply(mh, mh.(*merchant.Handler).Restore, "merchants")).Methods(http.MethodPost)
+	m.Handle(mh.GetPath()+"/{id}/audit", mw.Apply(mh, mh.(*merchant.Handler).GetAudit, "merchants")).Methods(http.MethodGet)
+
+	// Locations
+	lh := location.NewHandler(rt.Env, rt.Logger)
+	m.Handle(lh.GetPath(), mw.Apply(lh, lh.Post, "locations")).Methods(http.MethodPost)
+	m.Handle(lh.GetPath(), mw.Apply(lh, lh.GetCollection, "locations")).Methods(http.MethodGet)
+	m.Handle(lh.GetPath()+"/{id}", mw.Apply(lh, lh.Get, "locations")).Methods(http.MethodGet)
+	m.Handle(lh.GetPath()+"/{id}", mw.Apply(lh, lh.Delete, "locations")).Methods(http.MethodDelete)
+	m.Handle(lh.GetPath()+"/{id}", mw.Apply(lh, lh.Put, "locations")).Methods(http.MethodPut)
+
+	// Merchant addresses
+	ah := merchantaddress.NewHandler(rt.Env, rt.Logger)
+	m.Handle(ah.GetPath(), mw.Apply(ah, ah.Post, "merchant_addresses")).Methods(http.MethodPost)
+	m.Handle(ah.GetPath(), mw.Apply(ah, ah.GetCollection, "merchant_addresses")).Methods(http.MethodGet)
+	m.Handle(ah.GetPath()+"/{id}", mw.Apply(ah, ah.Get, "merchant_addresses")).Methods(http.MethodGet)
+	m.Handle(ah.GetPath()+"/{id}", mw.Apply(ah, ah.Delete, "merchant_addresses")).Methods(http.MethodDelete)
+	m.Handle(ah.GetPath()+"/{id}", mw.Apply(ah, ah.Put, "merchant_addresses")).Methods(http.MethodPut)
+
+	// Merchant contacts
+	ch := merchantcontact.NewHandler(rt.Env, rt.Logger)
+	m.Handle(ch.GetPath(), mw.Apply(ch, ch.Post, "merchant_contacts")).Methods(http.MethodPost)
+	m.Handle(ch.GetPath(), mw.Apply(ch, ch.GetCollection, "merchant_contacts")).Methods(http.MethodGet)
+	m.Handle(ch.GetPath()+"/{id}", mw.Apply(ch, ch.Get, "merchant_contacts")).Methods(http.MethodGet)
+	m.Handle(ch.GetPath()+"/{id}", mw.Apply(ch, ch.Delete, "merchant_contacts")).Methods(http.MethodDelete)
+	m.Handle(ch.GetPath()+"/{id}", mw.Apply(ch, ch.Put, "merchant_contacts")).Methods(http.MethodPut)
+
+	// Merchant bank accounts
+	bh := merchantbankaccount.NewHandler(rt.Env, rt.Logger)
+	m.Handle(bh.GetPath(), mw.Apply(bh, bh.Post, "merchant_bank_accounts")).Methods(http.MethodPost)
+	m.Handle(bh.GetPath(), mw.Apply(bh, bh.GetCollection, "merchant_bank_accounts")).Methods(http.MethodGet)
+	m.Handle(bh.GetPath()+"/{id}", mw.Apply(bh, bh.Get, "merchant_bank_accounts")).Methods(http.MethodGet)
+	m.Handle(bh.GetPath()+"/{id}", mw.Apply(bh, bh.Delete, "merchant_bank_accounts")).Methods(http.MethodDelete)
+	m.Handle(bh.GetPath()+"/{id}", mw.Apply(bh, bh.Put, "merchant_bank_accounts")).Methods(http.MethodPut)
+
+	// Merchant fee schedules
+	fh := merchantfeeschedule.NewHandler(rt.Env, rt.Logger)
+	m.Handle(fh.GetPath(), mw.Apply(fh, fh.Post, "merchant_fee_schedules")).Methods(http.MethodPost)
+	m.Handle(fh.GetPath(), mw.Apply(fh, fh.GetCollection, "merchant_fee_schedules")).Methods(http.MethodGet)
+	m.Handle(fh.GetPath()+"/calculate", mw.Apply(fh, fh.(*merchantfeeschedule.Handler).Calculate, "merchant_fee_schedules")).Methods(http.MethodGet)
+	m.Handle(fh.GetPath()+"/{id}", mw.Apply(fh, fh.Get, "merchant_fee_schedules")).Methods(http.MethodGet)
+	m.Handle(fh.GetPath()+"/{id}", mw.Apply(fh, fh.Delete, "merchant_fee_schedules")).Methods(http.MethodDelete)
+	m.Handle(fh.GetPath()+"/{id}", mw.Apply(fh, fh.Put, "merchant_fee_schedules")).Methods(http.MethodPut)
 
-	rt.handler = m
+	// Merchant business hours
+	hh := merchantbusinesshours.NewHandler(rt.Env, rt.Logger).(*merchantbusinesshours.Handler)
+	m.Handle(hh.GetPath(), mw.Apply(hh, hh.Get, "merchant_business_hours")).Methods(http.MethodGet)
+	m.Handle(hh.GetPath(), mw.Apply(hh, hh.Put, "merchant_business_hours")).Methods(http.MethodPut)
+	m.Handle(hh.GetPath()+"/next-open", mw.Apply(hh, hh.NextOpen, "merchant_business_hours")).Methods(http.MethodGet)
+
+	// Organisations
+	oh := organisation.NewHandler(rt.Env, rt.Logger).(*organisation.Handler)
+	m.Handle(oh.GetPath(), mw.Apply(oh, oh.Post, "organisations")).Methods(http.MethodPost)
+	m.Handle(oh.GetPath(), mw.Apply(oh, oh.GetCollection, "organisations")).Methods(http.MethodGet)
+	m.Handle(oh.GetPath()+"/{id}", mw.Apply(oh, oh.Get, "organisations")).Methods(http.MethodGet)
+	m.Handle(oh.GetPath()+"/{id}", mw.Apply(oh, oh.Delete, "organisations")).Methods(http.MethodDelete)
+	m.Handle(oh.GetPath()+"/{id}", mw.Apply(oh, oh.Put, "organisations")).Methods(http.MethodPut)
+	m.Handle(oh.GetPath()+"/{id}/summary", mw.Apply(oh, oh.GetSummary, "organisations")).Methods(http.MethodGet)
+	m.Handle(oh.GetPath()+"/{organisation_id}/merchants", mw.Apply(mh, mh.GetCollection, "merchants")).Methods(http.MethodGet)
+
+	// Webhooks
+	wh := webhook.NewHandler(rt.Env, rt.Logger).(*webhook.Handler)
+	m.Handle(wh.GetPath(), mw.Apply(wh, wh.Post, "webhooks")).Methods(http.MethodPost)
+	m.Handle(wh.GetPath(), mw.Apply(wh, wh.GetCollection, "webhooks")).Methods(http.MethodGet)
+	m.Handle(wh.GetPath()+"/{id}", mw.Apply(wh, wh.Get, "webhooks")).Methods(http.MethodGet)
+	m.Handle(wh.GetPath()+"/{id}", mw.Apply(wh, wh.Delete, "webhooks")).Methods(http.MethodDelete)
+	m.Handle(wh.GetPath()+"/{id}", mw.Apply(wh, wh.Put, "webhooks")).Methods(http.MethodPut)
+	m.Handle(wh.GetPath()+"/{id}/deliveries", mw.Apply(wh, wh.GetDeliveries, "webhooks")).Methods(http.MethodGet)
+
+	// Log levels
+	gh := loglevel.NewHandler(rt.Env, rt.Logger)
+	m.Handle(gh.GetPath(), mw.Apply(gh, gh.Get, "log_levels")).Methods(http.MethodGet)
+	m.Handle(gh.GetPath(), mw.Apply(gh, gh.Put, "log_levels")).Methods(http.MethodPut)
+
+	// compression
+	zh, err := compress.NewCompress(rt.Env, rt.Logger, m)
+	if err != nil {
+		return err
+	}
+
+	// cors - wraps the router so preflight requests are answered
+	// before route middleware opens a tx
+	rt.handler, err = cors.NewCORS(rt.Env, rt.Logger, zh)
+	if err != nil {
+		return err
+	}
 
 	// Set the not found handler.
 	// TODO: When there's some time for refactoring, replace handler interface's
@@ -0,0 +1,291 @@
+// Package businesshours works out when a merchant is open from a weekly
+// schedule and holiday overrides kept in the merchant's local time.
+//
+// Hours are wall clock times in the merchant's time zone, so a shop that
+// opens at 09:00 opens at 09:00 local time either side of a daylight
+// saving change. Wall times the clocks skip, such as 02:30 when clocks
+// spring forward from 02:00 to 03:00, happen when the clocks skip them.
+// Wall times the clocks repeat, such as 01:30 when clocks fall back from
+// 02:00 to 01:00, happen the first time.
+package businesshours
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DateFormat - holiday dates
+const DateFormat = "2006-01-02"
+
+// MaxDays - how far ahead Next looks for an opening
+const MaxDays = 366
+
+// Midnight - the end of a day, a Clock of 24:00
+const Midnight Clock = 24 * 60
+
+// Clock - minutes since midnight
+type Clock int
+
+// ErrInvalidClock - a time of day is not HH:MM from 00:00 to 24:00
+var ErrInvalidClock = errors.New("Time of day must be HH:MM from 00:00 to 24:00")
+
+// ParseClock parses a time of day, HH:MM
+func ParseClock(s string) (Clock, error) {
+
+	if len(s) != 5 || s[2] != ':' {
+		return 0, ErrInvalidClock
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, ErrInvalidClock
+		}
+	}
+
+	h := int(s[0]-'0')*10 + int(s[1]-'0')
+	m := int(s[3]-'0')*10 + int(s[4]-'0')
+	if m > 59 {
+		return 0, ErrInvalidClock
+	}
+
+	c := Clock(h*60 + m)
+	if c > Midnight {
+		return 0, ErrInvalidClock
+	}
+
+	return c, nil
+}
+
+// String formats the time of day as HH:MM
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// Hours - open from Opens until Closes. Hours closing at or before they
+// open close the next day, 22:00 to 02:00 is open overnight.
+type Hours struct {
+	Opens  Clock
+	Closes Clock
+}
+
+// Valid - hours open before midnight, close by the following midnight and
+// are not empty
+func (h Hours) Valid() bool {
+	return h.Opens >= 0 && h.Opens < Midnight &&
+		h.Closes >= 0 && h.Closes <= Midnight &&
+		h.Opens != h.Closes
+}
+
+// Weekly - regular hours on a day of the week
+type Weekly struct {
+	Day time.Weekday
+	Hours
+}
+
+// Holiday - hours on a date that replace the weekly hours for that date,
+// closed all day when Closed
+type Holiday struct {
+	Date   string
+	Closed bool
+	Hours
+}
+
+// Schedule - a merchant's weekly hours and holidays
+type Schedule struct {
+	Weekly   []Weekly
+	Holidays []Holiday
+}
+
+// Window - a period the merchant is open
+type Window struct {
+	Opens  time.Time
+	Closes time.Time
+}
+
+// OpenAt - whether t falls within the window
+func (w *Window) OpenAt(t time.Time) bool {
+	return !t.Before(w.Opens) && t.Before(w.Closes)
+}
+
+// hours returns the hours for a date, holidays first
+func (s *Schedule) hours(date time.Time) []Hours {
+
+	key := date.Format(DateFormat)
+
+	var hs []Hours
+	holiday := false
+	for _, h := range s.Holidays {
+		if h.Date != key {
+			continue
+		}
+		holiday = true
+		if !h.Closed {
+			hs = append(hs, h.Hours)
+		}
+	}
+	if holiday {
+		return hs
+	}
+
+	for _, w := range s.Weekly {
+		if w.Day == date.Weekday() {
+			hs = append(hs, w.Hours)
+		}
+	}
+
+	return hs
+}
+
+// Windows returns the windows opening on a date in loc, ordered by when
+// they open. Only the year, month and day of date are used.
+func (s *Schedule) Windows(date time.Time, loc *time.Location) []Window {
+
+	y, m, d := date.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	var ws []Window
+	for _, h := range s.hours(day) {
+		closes := h.Closes
+		if closes <= h.Opens {
+			closes += Midnight
+		}
+
+		w := Window{
+			Opens:  Instant(day, h.Opens, loc),
+			Closes: Instant(day, closes, loc),
+		}
+
+		// hours wholly within a skipped hour never open
+		if w.Closes.After(w.Opens) {
+			ws = append(ws, w)
+		}
+	}
+
+	sort.Slice(ws, func(i, j int) bool {
+		return ws[i].Opens.Before(ws[j].Opens)
+	})
+
+	return ws
+}
+
+// Next returns the window the merchant is open in at from, or the next
+// window it opens in, nil when it does not open within MaxDays.
+// Overlapping and back to back windows, such as 22:00 to 24:00 followed
+// by 00:00 to 02:00, are joined.
+func (s *Schedule) Next(from time.Time, loc *time.Location) *Window {
+
+	y, m, d := from.In(loc).Date()
+
+	var cur *Window
+
+	// the day before may have hours open past midnight
+	for i := -1; i <= MaxDays; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, time.UTC)
+
+		// windows open on their date so cur cannot be joined by later ones
+		if cur != nil && cur.Closes.Before(Instant(day, 0, loc)) {
+			if cur.Closes.After(from) {
+				return cur
+			}
+			cur = nil
+		}
+
+		for _, w := range s.Windows(day, loc) {
+			if cur != nil && !w.Opens.After(cur.Closes) {
+				if w.Closes.After(cur.Closes) {
+					cur.Closes = w.Closes
+				}
+				continue
+			}
+			if cur != nil && cur.Closes.After(from) {
+				return cur
+			}
+			c := w
+			cur = &c
+		}
+	}
+
+	if cur != nil && cur.Closes.After(from) {
+		return cur
+	}
+
+	return nil
+}
+
+// Instant returns when the clocks in loc first read c on date. When the
+// clocks skip c it is when they skip it.
+func Instant(date time.Time, c Clock, loc *time.Location) time.Time {
+
+	y, m, d := date.Date()
+	wall := time.Date(y, m, d, 0, int(c), 0, 0, time.UTC)
+
+	// offsets a couple of days either side cover any change near wall
+	var offsets []int
+	for _, p := range []time.Duration{-48 * time.Hour, 0, 48 * time.Hour} {
+		_, o := wall.Add(p).In(loc).Zone()
+		offsets = append(offsets, o)
+	}
+
+	var first time.Time
+	lo, hi := wall, wall
+	for _, o := range offsets {
+		t := wall.Add(-time.Duration(o) * time.Second)
+		if wallClock(t, loc).Equal(wall) && (first.IsZero() || t.Before(first)) {
+			first = t
+		}
+		if t.Before(lo) {
+			lo = t
+		}
+		if t.After(hi) {
+			hi = t
+		}
+	}
+	if !first.IsZero() {
+		return first
+	}
+
+	// the clocks skip wall, find when they jump past it
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if wallClock(mid, loc).Before(wall) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return hi.Truncate(time.Second)
+}
+
+// wallClock returns what the clocks in loc read at t, as a UTC time
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	l := t.In(loc)
+	return time.Date(l.Year(), l.Month(), l.Day(), l.Hour(), l.Minute(), l.Second(), 0, time.UTC)
+}
+
+var (
+	locations   = map[string]*time.Location{}
+	locationsMu sync.Mutex
+)
+
+// Location loads a time zone once, time.LoadLocation reads the tz
+// database every call
+func Location(name string) (*time.Location, error) {
+
+	locationsMu.Lock()
+	defer locationsMu.Unlock()
+
+	if loc, ok := locations[name]; ok {
+		return loc, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations[name] = loc
+
+	return loc, nil
+}
@@ -0,0 +1,152 @@
+package businesshours
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	loc, err := Location(name)
+	if err != nil {
+		t.Skipf("No time zone %s: %v", name, err)
+	}
+	return loc
+}
+
+func date(s string) time.Time {
+	d, _ := time.Parse(DateFormat, s)
+	return d
+}
+
+func TestParseClock(t *testing.T) {
+	for s, c := range map[string]Clock{
+		"00:00": 0,
+		"09:30": 570,
+		"23:59": 1439,
+		"24:00": Midnight,
+	} {
+		got, err := ParseClock(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, c, got, s)
+		assert.Equal(t, s, got.String())
+	}
+
+	for _, s := range []string{"", "9:00", "09:60", "24:01", "+1:00", "09-00", "0900"} {
+		_, err := ParseClock(s)
+		assert.Equal(t, ErrInvalidClock, err, s)
+	}
+}
+
+func TestHoursValid(t *testing.T) {
+	assert.True(t, Hours{Opens: 540, Closes: 1020}.Valid())
+	assert.True(t, Hours{Opens: 1320, Closes: 120}.Valid())
+	assert.True(t, Hours{Opens: 0, Closes: Midnight}.Valid())
+	assert.False(t, Hours{Opens: 540, Closes: 540}.Valid())
+	assert.False(t, Hours{Opens: Midnight, Closes: 60}.Valid())
+}
+
+func TestInstant(t *testing.T) {
+	syd := mustLocation(t, "Australia/Sydney")
+	ny := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		loc  *time.Location
+		date string
+		c    Clock
+		want string
+	}{
+		// ordinary days
+		{syd, "2024-01-15", 540, "2024-01-15T09:00:00+11:00"},
+		{syd, "2024-07-15", 540, "2024-07-15T09:00:00+10:00"},
+		// Sydney springs forward from 02:00 to 03:00
+		{syd, "2024-10-06", 150, "2024-10-06T03:00:00+11:00"},
+		{syd, "2024-10-06", 180, "2024-10-06T03:00:00+11:00"},
+		// Sydney falls back from 03:00 to 02:00, 02:30 happens twice
+		{syd, "2024-04-07", 150, "2024-04-07T02:30:00+11:00"},
+		// New York falls back from 02:00 to 01:00
+		{ny, "2024-11-03", 90, "2024-11-03T01:30:00-04:00"},
+		// New York springs forward from 02:00 to 03:00
+		{ny, "2024-03-10", 135, "2024-03-10T03:00:00-04:00"},
+		// midnight is the start of the next day
+		{ny, "2024-03-09", Midnight, "2024-03-10T00:00:00-05:00"},
+	}
+
+	for _, tt := range tests {
+		got := Instant(date(tt.date), tt.c, tt.loc)
+		assert.Equal(t, tt.want, got.In(tt.loc).Format(time.RFC3339), "%s %s %s", tt.loc, tt.date, tt.c)
+	}
+}
+
+func TestNext(t *testing.T) {
+	syd := mustLocation(t, "Australia/Sydney")
+
+	weekdays := func(opens, closes Clock) []Weekly {
+		var ws []Weekly
+		for d := time.Monday; d <= time.Friday; d++ {
+			ws = append(ws, Weekly{Day: d, Hours: Hours{Opens: opens, Closes: closes}})
+		}
+		return ws
+	}
+
+	at := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	format := func(w *Window) []string {
+		if w == nil {
+			return nil
+		}
+		return []string{w.Opens.In(syd).Format(time.RFC3339), w.Closes.In(syd).Format(time.RFC3339)}
+	}
+
+	s := &Schedule{Weekly: weekdays(540, 1020)}
+
+	// open now
+	w := s.Next(at("2024-01-15T10:00:00+11:00"), syd)
+	assert.Equal(t, []string{"2024-01-15T09:00:00+11:00", "2024-01-15T17:00:00+11:00"}, format(w))
+	assert.True(t, w.OpenAt(at("2024-01-15T10:00:00+11:00")))
+
+	// friday evening opens monday
+	w = s.Next(at("2024-01-19T17:00:00+11:00"), syd)
+	assert.Equal(t, []string{"2024-01-22T09:00:00+11:00", "2024-01-22T17:00:00+11:00"}, format(w))
+	assert.False(t, w.OpenAt(at("2024-01-19T17:00:00+11:00")))
+
+	// the weekend after daylight saving ends opens at 09:00 standard time
+	w = s.Next(at("2024-04-05T18:00:00+11:00"), syd)
+	assert.Equal(t, []string{"2024-04-08T09:00:00+10:00", "2024-04-08T17:00:00+10:00"}, format(w))
+
+	// holidays replace weekly hours
+	s.Holidays = []Holiday{
+		{Date: "2024-01-26", Closed: true},
+		{Date: "2024-01-29", Hours: Hours{Opens: 600, Closes: 840}},
+	}
+	w = s.Next(at("2024-01-25T18:00:00+11:00"), syd)
+	assert.Equal(t, []string{"2024-01-29T10:00:00+11:00", "2024-01-29T14:00:00+11:00"}, format(w))
+
+	// overnight hours and hours from midnight are joined
+	s = &Schedule{Weekly: []Weekly{
+		{Day: time.Saturday, Hours: Hours{Opens: 1320, Closes: Midnight}},
+		{Day: time.Sunday, Hours: Hours{Opens: 0, Closes: 120}},
+		{Day: time.Sunday, Hours: Hours{Opens: 60, Closes: 240}},
+	}}
+	w = s.Next(at("2024-01-21T01:00:00+11:00"), syd)
+	assert.Equal(t, []string{"2024-01-20T22:00:00+11:00", "2024-01-21T04:00:00+11:00"}, format(w))
+
+	// overnight hours through the night clocks spring forward are an hour
+	// shorter
+	s = &Schedule{Weekly: []Weekly{
+		{Day: time.Saturday, Hours: Hours{Opens: 1320, Closes: 360}},
+	}}
+	w = s.Next(at("2024-10-05T12:00:00+10:00"), syd)
+	assert.Equal(t, []string{"2024-10-05T22:00:00+10:00", "2024-10-06T06:00:00+11:00"}, format(w))
+	assert.Equal(t, 7*time.Hour, w.Closes.Sub(w.Opens))
+
+	// never open
+	assert.Nil(t, (&Schedule{}).Next(time.Now(), syd))
+}
@@ -0,0 +1,635 @@
+// Package config loads the application configuration into a typed struct.
+//
+// Each setting is named by its environment variable, APP_DATABASE_HOST,
+// and is resolved from layered sources, later sources overriding earlier
+// ones:
+//
+//	defaults < config file < .env < environment < flags
+//
+// The config file is YAML, $APP_HOME/config.yaml unless -config or
+// APP_CONFIG_FILE names another, or TOML when that file ends in .toml,
+// with settings keyed by their lower case name without the APP_ prefix,
+// database_host. Flags are -set
+// APP_DATABASE_HOST=db and may be repeated.
+//
+// Secrets, such as APP_DATABASE_PASS, may also be read from the file
+// named by APP_DATABASE_PASS_FILE in any source, or from the provider set
+// by APP_SECRETS_PROVIDER, see package secrets.
+//
+// Every invalid value is reported together rather than stopping at the
+// first.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/vegh1010/test/pkg/encryption"
+	"github.com/vegh1010/test/pkg/secrets"
+)
+
+// DefaultFile - the config file, relative to APP_HOME
+const DefaultFile = "config.yaml"
+
+// Sources a value may come from, in increasing precedence
+const (
+	SourceDefault     = "default"
+	SourceFile        = "file"
+	SourceDotEnv      = ".env"
+	SourceEnvironment = "environment"
+	SourceFlag        = "flag"
+)
+
+// Redacted - printed in place of secrets
+const Redacted = "[redacted]"
+
+// Config - the application configuration
+type Config struct {
+	Env         string `env:"APP_ENV" required:"true"`
+	Home        string `env:"APP_HOME" required:"true"`
+	URL         string `env:"APP_URL" default:"localhost"`
+	LogLevel    string `env:"APP_LOG_LEVEL" default:"error" oneof:"debug,info,warn,error"`
+	PrettyLogs  bool   `env:"APP_PRETTY_LOGS"`
+	ServerPort  int    `env:"APP_SERVER_PORT" default:"8080" min:"1" max:"65535"`
+	BuildNumber string `env:"BUILD_NUMBER"`
+	// ConfigFile - the config file loaded, if any
+	ConfigFile string `env:"APP_CONFIG_FILE"`
+	// LogLevels - packages logging at a level other than APP_LOG_LEVEL,
+	// db=debug
+	LogLevels []string `env:"APP_LOG_LEVELS"`
+	// ShutdownTimeout - how long requests in flight may take to finish on
+	// SIGTERM before the server closes them
+	ShutdownTimeout time.Duration `env:"APP_SHUTDOWN_TIMEOUT" default:"30s"`
+
+	Database     Database
+	Jobs         Jobs
+	Webhooks     Webhooks
+	Retention    Retention
+	Search       Search
+	Encryption   Encryption
+	RateLimit    RateLimit
+	CORS         CORS
+	Compression  Compression
+	Requests     Requests
+	Timezones    Timezones
+	Secrets      Secrets
+	FeatureFlags FeatureFlags
+
+	values []*Value
+	// byName - values by name, for Get
+	byName map[string]*Value
+}
+
+// Database -
+type Database struct {
+	// URL - a postgres:// URL or key=value connect string used in place
+	// of the host, port and name, the settings below override its values
+	URL           string `env:"APP_DATABASE_URL" secret:"true"`
+	Host          string `env:"APP_DATABASE_HOST" default:"localhost"`
+	Port          int    `env:"APP_DATABASE_PORT" default:"5432" min:"1" max:"65535"`
+	Name          string `env:"APP_DATABASE_NAME"`
+	User          string `env:"APP_DATABASE_USER"`
+	Pass          string `env:"APP_DATABASE_PASS" secret:"true"`
+	OwnerUser     string `env:"APP_DATABASE_OWNER_USER"`
+	OwnerPass     string `env:"APP_DATABASE_OWNER_PASS" secret:"true"`
+	MaxIdleConns  int    `env:"APP_DATABASE_MAX_IDLE_CONNS" default:"50" min:"0"`
+	MaxOpenConns  int    `env:"APP_DATABASE_MAX_OPEN_CONNS" default:"100" min:"0"`
+	TimedRequests bool   `env:"APP_TIMED_REQUESTS"`
+	// Schema - searched before public, for databases migrated by go-pg
+	// which created every object in a schema named after the database
+	Schema string `env:"APP_DATABASE_SCHEMA"`
+	// MigrateOnStart - check or apply migrations at startup
+	MigrateOnStart string `env:"APP_MIGRATE_ON_START" default:"off" oneof:"off,check,apply"`
+
+	// SSLMode - disable unless APP_DATABASE_URL sets it
+	SSLMode     string `env:"APP_DATABASE_SSL_MODE" oneof:"disable,require,verify-ca,verify-full"`
+	SSLRootCert string `env:"APP_DATABASE_SSL_ROOT_CERT"`
+	SSLCert     string `env:"APP_DATABASE_SSL_CERT"`
+	SSLKey      string `env:"APP_DATABASE_SSL_KEY"`
+	// ApplicationName - shown in pg_stat_activity, the command name when
+	// not set
+	ApplicationName  string        `env:"APP_DATABASE_APPLICATION_NAME"`
+	ConnectTimeout   time.Duration `env:"APP_DATABASE_CONNECT_TIMEOUT" default:"10s"`
+	StatementTimeout time.Duration `env:"APP_DATABASE_STATEMENT_TIMEOUT" default:"0s"`
+	// ConnMaxLifetime, ConnMaxIdleTime - connections are closed once this
+	// old or idle this long, never when 0
+	ConnMaxLifetime time.Duration `env:"APP_DATABASE_CONN_MAX_LIFETIME" default:"0s"`
+	ConnMaxIdleTime time.Duration `env:"APP_DATABASE_CONN_MAX_IDLE_TIME" default:"0s"`
+
+	// ReplicaURL - a read replica for read-only requests, a URL or
+	// key=value connect string overriding the primary's values. Pooled
+	// connections hold one to each server, MaxOpenConns and MaxIdleConns
+	// apply to both.
+	ReplicaURL string `env:"APP_DATABASE_REPLICA_URL" secret:"true"`
+	// ReplicaMaxLag - reads go to the primary while the replica is further
+	// behind
+	ReplicaMaxLag        time.Duration `env:"APP_DATABASE_REPLICA_MAX_LAG" default:"10s"`
+	ReplicaCheckInterval time.Duration `env:"APP_DATABASE_REPLICA_CHECK_INTERVAL" default:"5s"`
+}
+
+// Jobs -
+type Jobs struct {
+	// Workers - job workers run inside test-api, none when 0
+	Workers      int           `env:"APP_JOB_WORKERS" default:"0" min:"0"`
+	PollInterval time.Duration `env:"APP_JOB_POLL_INTERVAL" default:"1s"`
+}
+
+// Webhooks -
+type Webhooks struct {
+	DispatchInterval time.Duration `env:"APP_WEBHOOK_DISPATCH_INTERVAL" default:"5s"`
+	Timeout          time.Duration `env:"APP_WEBHOOK_TIMEOUT" default:"10s"`
+	MaxAttempts      int           `env:"APP_WEBHOOK_MAX_ATTEMPTS" default:"15" min:"1"`
+}
+
+// Retention -
+type Retention struct {
+	// MerchantDays - days soft deleted merchants are kept, forever when 0
+	MerchantDays int `env:"APP_MERCHANT_RETENTION_DAYS" default:"0" min:"0"`
+}
+
+// Search -
+type Search struct {
+	Backend            string `env:"APP_SEARCH_BACKEND" default:"postgres" oneof:"postgres,elasticsearch"`
+	ElasticsearchURL   string `env:"APP_ELASTICSEARCH_URL"`
+	ElasticsearchIndex string `env:"APP_ELASTICSEARCH_INDEX" default:"merchants"`
+}
+
+// Encryption -
+type Encryption struct {
+	Key string `env:"APP_ENCRYPTION_KEY" secret:"true"`
+}
+
+// RateLimit - limits are parsed by the ratelimit middleware
+type RateLimit struct {
+	Client string   `env:"APP_RATE_LIMIT"`
+	Route  string   `env:"APP_RATE_LIMIT_ROUTE"`
+	Routes []string `env:"APP_RATE_LIMIT_ROUTES"`
+	Store  string   `env:"APP_RATE_LIMIT_STORE" default:"memory" oneof:"memory,postgres"`
+}
+
+// CORS -
+type CORS struct {
+	AllowedOrigins   []string `env:"APP_CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   []string `env:"APP_CORS_ALLOWED_METHODS"`
+	AllowedHeaders   []string `env:"APP_CORS_ALLOWED_HEADERS"`
+	ExposedHeaders   []string `env:"APP_CORS_EXPOSED_HEADERS"`
+	AllowCredentials bool     `env:"APP_CORS_ALLOW_CREDENTIALS"`
+	MaxAge           int      `env:"APP_CORS_MAX_AGE" default:"0" min:"0"`
+}
+
+// Compression -
+type Compression struct {
+	// MinSize - smallest response compressed, compression is off when 0
+	MinSize int `env:"APP_COMPRESSION_MIN_SIZE" default:"1024" min:"0"`
+}
+
+// Requests - how request bodies are decoded
+type Requests struct {
+	// MaxBodySize - largest request body accepted, in bytes
+	MaxBodySize int `env:"APP_MAX_BODY_SIZE" default:"1048576" min:"1"`
+	// MaxBodySizes - MaxBodySize for particular routes, METHOD
+	// /path=bytes, see MaxBodySizeRoutes
+	MaxBodySizes []string `env:"APP_MAX_BODY_SIZES"`
+	// UnknownFields - whether JSON fields a request does not have are
+	// rejected or ignored
+	UnknownFields string `env:"APP_JSON_UNKNOWN_FIELDS" default:"reject" oneof:"reject,ignore"`
+}
+
+// Timezones -
+type Timezones struct {
+	// Source - the zoneinfo directory, ZONEINFO or /usr/share/zoneinfo
+	// when empty
+	Source string `env:"APP_TIMEZONE_SOURCE"`
+	// SyncInterval - how often test-worker syncs time zones, never when 0
+	SyncInterval time.Duration `env:"APP_TIMEZONE_SYNC_INTERVAL" default:"0s"`
+}
+
+// Secrets - where secret settings are read from besides the environment,
+// see package secrets
+type Secrets struct {
+	Provider string `env:"APP_SECRETS_PROVIDER" default:"env" oneof:"env,file,encrypted-file"`
+	Dir      string `env:"APP_SECRETS_DIR" default:"/run/secrets"`
+	File     string `env:"APP_SECRETS_FILE"`
+	Key      string `env:"APP_SECRETS_KEY" secret:"true"`
+	// ReloadInterval - how often secrets are read again, only on SIGHUP
+	// when 0
+	ReloadInterval time.Duration `env:"APP_SECRETS_RELOAD_INTERVAL" default:"0s"`
+}
+
+// FeatureFlags -
+type FeatureFlags struct {
+	// TTL - how long flags are cached, read for every check when 0
+	TTL time.Duration `env:"APP_FEATURE_FLAG_TTL" default:"30s"`
+}
+
+// Value - a resolved setting
+type Value struct {
+	Name   string
+	Value  string
+	Source string
+	Secret bool
+}
+
+// Errors - every invalid value found loading the configuration
+type Errors []error
+
+// Error lists each error on its own line
+func (es Errors) Error() string {
+	var lines []string
+	for _, err := range es {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Options - sources that are not read from the environment
+type Options struct {
+	// File - the config file, overrides APP_CONFIG_FILE
+	File string
+	// Flags - values set on the command line, by name
+	Flags Flags
+}
+
+// Flags - repeatable NAME=VALUE command line values
+type Flags map[string]string
+
+// String -
+func (f Flags) String() string {
+	var s []string
+	for k, v := range f {
+		s = append(s, k+"="+v)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// Set adds a NAME=VALUE flag
+func (f Flags) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i < 1 {
+		return fmt.Errorf("Flag %q must be NAME=VALUE", s)
+	}
+	f[s[:i]] = s[i+1:]
+	return nil
+}
+
+// RegisterFlags adds -config and -set to fs, returning the options they
+// fill in as fs is parsed
+func RegisterFlags(fs *flag.FlagSet) *Options {
+	o := Options{Flags: Flags{}}
+	fs.StringVar(&o.File, "config", "", "YAML or TOML config file, default $APP_HOME/"+DefaultFile)
+	fs.Var(o.Flags, "set", "set a config value, NAME=VALUE, may be repeated")
+	return &o
+}
+
+// Load resolves the configuration from its sources. The configuration is
+// returned along with Errors when any value is invalid so it may still be
+// printed.
+func Load(o *Options) (*Config, error) {
+
+	if o == nil {
+		o = &Options{}
+	}
+
+	var errs Errors
+
+	env := environment()
+
+	// APP_HOME locates .env and the config file so cannot come from them
+	home := lookup("APP_HOME", o.Flags, env)
+
+	var dotEnv map[string]string
+	if home != "" {
+		var err error
+		dotEnv, err = godotenv.Read(filepath.Join(home, ".env"))
+		if err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("Invalid .env: %v", err))
+		}
+	}
+
+	file := o.File
+	if file == "" {
+		file = lookup("APP_CONFIG_FILE", o.Flags, env, dotEnv)
+	}
+	required := file != ""
+	if file == "" && home != "" {
+		file = filepath.Join(home, DefaultFile)
+	}
+
+	var fileValues map[string]string
+	if file != "" {
+		var err error
+		fileValues, err = readFile(file)
+		switch {
+		case os.IsNotExist(err) && !required:
+			file = ""
+		case err != nil:
+			errs = append(errs, err)
+		}
+	}
+
+	c := Config{}
+
+	fields := c.fields()
+	known := map[string]bool{}
+	for _, f := range fields {
+		known[f.name] = true
+		if f.secret {
+			known[f.name+secrets.FileSuffix] = true
+		}
+	}
+
+	// config file keys drop the APP_ prefix
+	fileKeys := map[string]string{}
+	for k, v := range fileValues {
+		name := strings.ToUpper(k)
+		if !known[name] && known["APP_"+name] {
+			name = "APP_" + name
+		}
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("Unknown setting %s in %s", k, file))
+			continue
+		}
+		fileKeys[name] = v
+	}
+
+	for name := range o.Flags {
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("Unknown setting %s in -set", name))
+		}
+	}
+
+	// highest precedence first
+	layers := []layer{
+		{SourceFlag, o.Flags},
+		{SourceEnvironment, env},
+		{SourceDotEnv, dotEnv},
+		{SourceFile, fileKeys},
+	}
+
+	values := map[string]*Value{}
+	for _, f := range fields {
+		v, err := resolve(f, layers)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if f.name == "APP_CONFIG_FILE" && file != "" {
+			v.Value = file
+		}
+		values[f.name] = v
+	}
+
+	// the secrets provider is configured by the layers alone, secrets it
+	// has replace those from the layers
+	for _, f := range fields {
+		if strings.HasPrefix(f.name, secretsPrefix) {
+			// invalid values are reported below
+			f.set(values[f.name].Value)
+		}
+	}
+
+	provider, err := c.secretProvider()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, f := range fields {
+		v := values[f.name]
+
+		if provider != nil && f.secret && !strings.HasPrefix(f.name, secretsPrefix) {
+			s, ok, err := provider.Secret(f.name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Invalid %s from %s: %v", f.name, provider, err))
+			}
+			if ok {
+				v.Value, v.Source = s, provider.String()
+			}
+		}
+
+		err := f.set(v.Value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Invalid %s %q from %s: %v", f.name, display(v), v.Source, err))
+		}
+
+		c.values = append(c.values, v)
+	}
+	c.byName = values
+
+	// the database is named by its URL or by its parts
+	if c.Database.URL == "" {
+		for _, f := range fields {
+			switch f.name {
+			case "APP_DATABASE_NAME", "APP_DATABASE_USER", "APP_DATABASE_PASS":
+				if values[f.name].Value == "" {
+					errs = append(errs, fmt.Errorf("Invalid %s \"\" from %s: is required unless APP_DATABASE_URL is set", f.name, values[f.name].Source))
+				}
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if f.name == "APP_LOG_LEVEL" {
+			err := checkLogLevels(c.LogLevels, f.oneof)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if _, err := routeSizes(c.Requests.MaxBodySizes); err != nil {
+		errs = append(errs, err)
+	}
+
+	// browsers refuse credentials from any origin
+	if c.CORS.AllowCredentials && contains(c.CORS.AllowedOrigins, "*") {
+		errs = append(errs, fmt.Errorf("Invalid APP_CORS_ALLOWED_ORIGINS \"*\" from %s: can not be * when APP_CORS_ALLOW_CREDENTIALS is set", values["APP_CORS_ALLOWED_ORIGINS"].Source))
+	}
+
+	if c.Search.Backend == "elasticsearch" && c.Search.ElasticsearchURL == "" {
+		errs = append(errs, fmt.Errorf("Invalid APP_ELASTICSEARCH_URL \"\" from %s: is required when APP_SEARCH_BACKEND is elasticsearch", values["APP_ELASTICSEARCH_URL"].Source))
+	}
+
+	if len(errs) > 0 {
+		return &c, errs
+	}
+
+	return &c, nil
+}
+
+// checkLogLevels checks each APP_LOG_LEVELS item is package=level, with
+// a level APP_LOG_LEVEL accepts
+func checkLogLevels(items []string, levels []string) error {
+	for _, item := range items {
+		i := strings.Index(item, "=")
+		if i < 1 || !contains(levels, strings.TrimSpace(item[i+1:])) {
+			return fmt.Errorf("Invalid APP_LOG_LEVELS %q: must be package=level, level one of %s", item, strings.Join(levels, ", "))
+		}
+	}
+	return nil
+}
+
+// MaxBodySizeRoutes returns the largest request body accepted by
+// particular routes, by method and path template, from APP_MAX_BODY_SIZES
+// as checked by Load
+func (r Requests) MaxBodySizeRoutes() map[string]int64 {
+	routes, _ := routeSizes(r.MaxBodySizes)
+	return routes
+}
+
+// routeSizes parses APP_MAX_BODY_SIZES items, METHOD /path=bytes
+func routeSizes(items []string) (map[string]int64, error) {
+	routes := map[string]int64{}
+	for _, item := range items {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || len(strings.Fields(parts[0])) != 2 {
+			return nil, fmt.Errorf("Invalid APP_MAX_BODY_SIZES %q: must be METHOD /path=bytes", item)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("Invalid APP_MAX_BODY_SIZES %q: bytes must be a positive whole number", item)
+		}
+		routes[strings.Join(strings.Fields(parts[0]), " ")] = n
+	}
+	return routes, nil
+}
+
+// secretsPrefix - settings configuring the secrets provider, which cannot
+// come from the provider
+const secretsPrefix = "APP_SECRETS_"
+
+// layer - values from a source by name
+type layer struct {
+	source string
+	values map[string]string
+}
+
+// resolve returns the value of a field from the first layer that sets it,
+// empty values are unset as in .env.example. Secrets may be set by
+// NAME_FILE as well as NAME.
+func resolve(f *field, layers []layer) (*Value, error) {
+
+	v := &Value{Name: f.name, Value: f.def, Source: SourceDefault, Secret: f.secret}
+
+	for _, l := range layers {
+		if !f.secret {
+			if s := l.values[f.name]; s != "" {
+				v.Value, v.Source = s, l.source
+				break
+			}
+			continue
+		}
+
+		values := l.values
+		p := secrets.Env{Name: l.source, Lookup: func(name string) (string, bool) {
+			s := values[name]
+			return s, s != ""
+		}}
+
+		s, ok, err := p.Secret(f.name)
+		if err != nil {
+			return v, fmt.Errorf("Invalid %s from %s: %v", f.name, l.source, err)
+		}
+		if ok {
+			v.Value, v.Source = s, l.source
+			if l.values[f.name] == "" {
+				v.Source += " " + f.name + secrets.FileSuffix
+			}
+			break
+		}
+	}
+
+	return v, nil
+}
+
+// secretProvider returns the configured secrets provider, nil for env as
+// the layers already read secrets from the environment
+func (c *Config) secretProvider() (secrets.Provider, error) {
+
+	s := c.Secrets
+
+	switch s.Provider {
+	case secrets.ProviderFile:
+		return &secrets.Dir{Path: s.Dir}, nil
+
+	case secrets.ProviderEncryptedFile:
+		if s.File == "" {
+			return nil, fmt.Errorf("APP_SECRETS_FILE is required for the %s secrets provider", s.Provider)
+		}
+		key, err := encryption.Key("APP_SECRETS_KEY", s.Key)
+		if err != nil {
+			return nil, err
+		}
+		return secrets.NewEncryptedFile(s.File, key)
+	}
+
+	return nil, nil
+}
+
+// environment returns the process environment by name
+func environment() map[string]string {
+	vals := map[string]string{}
+	for _, kv := range os.Environ() {
+		i := strings.Index(kv, "=")
+		if i > 0 {
+			vals[kv[:i]] = kv[i+1:]
+		}
+	}
+	return vals
+}
+
+// lookup returns the first value of name set in layers
+func lookup(name string, layers ...map[string]string) string {
+	for _, l := range layers {
+		if v := l[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Get returns a setting by name as resolved, empty when unknown
+func (c *Config) Get(name string) string {
+	if v, ok := c.byName[name]; ok {
+		return v.Value
+	}
+	return ""
+}
+
+// Values returns every setting in declaration order
+func (c *Config) Values() []*Value {
+	return c.values
+}
+
+// Print writes every setting with where it came from, redacting secrets
+func (c *Config) Print(w io.Writer) error {
+	for _, v := range c.values {
+		_, err := fmt.Fprintf(w, "%s=%s # %s\n", v.Name, display(v), v.Source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// display returns a value as it may be shown
+func display(v *Value) string {
+	if v.Secret && v.Value != "" {
+		return Redacted
+	}
+	return v.Value
+}
+
+// fields returns the settings of c, and of its groups, in declaration
+// order
+func (c *Config) fields() []*field {
+	return structFields(reflect.ValueOf(c).Elem())
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setEnv replaces the process environment for the duration of a test
+func setEnv(t *testing.T, vals map[string]string) func() {
+	saved := os.Environ()
+	os.Clearenv()
+	for k, v := range vals {
+		os.Setenv(k, v)
+	}
+	return func() {
+		os.Clearenv()
+		for _, kv := range saved {
+			i := strings.Index(kv, "=")
+			os.Setenv(kv[:i], kv[i+1:])
+		}
+	}
+}
+
+func tempHome(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadLayers(t *testing.T) {
+
+	home := tempHome(t, map[string]string{
+		DefaultFile: `
+env: staging
+database_host: file-host
+database_name: file-name
+database_user: file-user
+database_pass: file-pass
+job_poll_interval: 3s
+log_levels:
+  - db=debug
+cors_allowed_origins:
+  - https://a.example.com
+  - https://b.example.com
+`,
+		".env": `
+export APP_DATABASE_NAME=dotenv-name
+export APP_DATABASE_USER=dotenv-user
+`,
+	})
+	defer os.RemoveAll(home)
+
+	defer setEnv(t, map[string]string{
+		"APP_HOME":          home,
+		"APP_DATABASE_USER": "env-user",
+		"APP_PRETTY_LOGS":   "1",
+	})()
+
+	c, err := Load(&Options{Flags: Flags{"APP_DATABASE_PASS": "flag-pass"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "staging", c.Env)
+	assert.Equal(t, "file-host", c.Database.Host)
+	assert.Equal(t, "dotenv-name", c.Database.Name)
+	assert.Equal(t, "env-user", c.Database.User)
+	assert.Equal(t, "flag-pass", c.Database.Pass)
+	assert.Equal(t, 5432, c.Database.Port)
+	assert.Equal(t, 100, c.Database.MaxOpenConns)
+	assert.Equal(t, 3*time.Second, c.Jobs.PollInterval)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, c.CORS.AllowedOrigins)
+	assert.True(t, c.PrettyLogs)
+	assert.Equal(t, []string{"db=debug"}, c.LogLevels)
+	assert.Equal(t, filepath.Join(home, DefaultFile), c.ConfigFile)
+
+	assert.Equal(t, "file-host", c.Get("APP_DATABASE_HOST"))
+	assert.Equal(t, "5432", c.Get("APP_DATABASE_PORT"))
+
+	out := bytes.Buffer{}
+	assert.NoError(t, c.Print(&out))
+	assert.Contains(t, out.String(), "APP_DATABASE_HOST=file-host # file\n")
+	assert.Contains(t, out.String(), "APP_DATABASE_NAME=dotenv-name # .env\n")
+	assert.Contains(t, out.String(), "APP_DATABASE_USER=env-user # environment\n")
+	assert.Contains(t, out.String(), "APP_DATABASE_PASS=[redacted] # flag\n")
+	assert.Contains(t, out.String(), "APP_DATABASE_PORT=5432 # default\n")
+	assert.NotContains(t, out.String(), "flag-pass")
+}
+
+func TestLoadTOML(t *testing.T) {
+
+	home := tempHome(t, map[string]string{
+		"config.toml": `
+env = "staging"
+database_name = "file-name"
+database_user = "file-user"
+database_pass = "file-pass"
+database_port = 6432
+job_poll_interval = "3s"
+pretty_logs = true
+cors_allowed_origins = ["https://a.example.com", "https://b.example.com"]
+`,
+		"config.ini": "env=staging\n",
+	})
+	defer os.RemoveAll(home)
+
+	defer setEnv(t, map[string]string{
+		"APP_HOME": home,
+	})()
+
+	c, err := Load(&Options{File: filepath.Join(home, "config.toml")})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "staging", c.Env)
+	assert.Equal(t, "file-name", c.Database.Name)
+	assert.Equal(t, 6432, c.Database.Port)
+	assert.Equal(t, 3*time.Second, c.Jobs.PollInterval)
+	assert.True(t, c.PrettyLogs)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, c.CORS.AllowedOrigins)
+	assert.Equal(t, "6432", c.Get("APP_DATABASE_PORT"))
+	assert.Equal(t, "", c.Get("APP_UNKNOWN"))
+
+	// the format is chosen by extension
+	_, err = Load(&Options{File: filepath.Join(home, "config.ini")})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "must end in .yaml, .yml or .toml")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+
+	home := tempHome(t, map[string]string{
+		"custom.yaml": "database_hots: typo\n",
+	})
+	defer os.RemoveAll(home)
+
+	defer setEnv(t, map[string]string{
+		"APP_HOME":                    home,
+		"APP_DATABASE_PORT":           "none",
+		"APP_DATABASE_MAX_IDLE_CONNS": "-1",
+		"APP_DATABASE_SSL_MODE":       "on",
+		"APP_LOG_LEVEL":               "loud",
+		"APP_LOG_LEVELS":              "db=debug,jobs=loud",
+		"APP_PRETTY_LOGS":             "maybe",
+		"APP_JOB_POLL_INTERVAL":       "5",
+		"APP_ENCRYPTION_KEY":          "secret key",
+		"APP_MAX_BODY_SIZES":          "POST /api/merchants=0",
+		"APP_CORS_ALLOWED_ORIGINS":    "*",
+		"APP_CORS_ALLOW_CREDENTIALS":  "true",
+		"APP_SEARCH_BACKEND":          "elasticsearch",
+	})()
+
+	c, err := Load(&Options{File: filepath.Join(home, "custom.yaml"), Flags: Flags{"APP_UNKNOWN": "1"}})
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.NotNil(t, c)
+
+	errs, ok := err.(Errors)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	msg := err.Error()
+	for _, s := range []string{
+		"Unknown setting database_hots",
+		"Unknown setting APP_UNKNOWN",
+		"APP_ENV",
+		"APP_DATABASE_NAME",
+		"APP_DATABASE_USER",
+		"APP_DATABASE_PASS",
+		"APP_DATABASE_PORT",
+		"APP_DATABASE_MAX_IDLE_CONNS",
+		"APP_DATABASE_SSL_MODE",
+		"APP_LOG_LEVEL",
+		"APP_LOG_LEVELS \"jobs=loud\"",
+		"APP_PRETTY_LOGS",
+		"APP_JOB_POLL_INTERVAL",
+		"APP_MAX_BODY_SIZES \"POST /api/merchants=0\"",
+		"APP_CORS_ALLOWED_ORIGINS",
+		"APP_ELASTICSEARCH_URL",
+	} {
+		assert.Contains(t, msg, s)
+	}
+	assert.Len(t, errs, 16)
+
+	// secrets are not shown in errors either
+	assert.NotContains(t, msg, "secret key")
+
+	// a config file named explicitly must exist
+	_, err = Load(&Options{File: filepath.Join(home, "missing.yaml")})
+	assert.Error(t, err)
+}
+
+func TestLoadSecrets(t *testing.T) {
+
+	home := tempHome(t, map[string]string{
+		"db_pass":           "file-pass\n",
+		"APP_DATABASE_PASS": "provider-pass\n",
+	})
+	defer os.RemoveAll(home)
+
+	defer setEnv(t, map[string]string{
+		"APP_HOME":               home,
+		"APP_ENV":                "test",
+		"APP_DATABASE_NAME":      "test",
+		"APP_DATABASE_USER":      "test",
+		"APP_DATABASE_PASS_FILE": filepath.Join(home, "db_pass"),
+	})()
+
+	c, err := Load(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "file-pass", c.Database.Pass)
+
+	out := bytes.Buffer{}
+	c.Print(&out)
+	assert.Contains(t, out.String(), "APP_DATABASE_PASS=[redacted] # environment APP_DATABASE_PASS_FILE\n")
+
+	// the file provider replaces secrets from the environment
+	c, err = Load(&Options{Flags: Flags{
+		"APP_SECRETS_PROVIDER": "file",
+		"APP_SECRETS_DIR":      home,
+	}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "provider-pass", c.Database.Pass)
+
+	// encrypted files need a key
+	_, err = Load(&Options{Flags: Flags{
+		"APP_SECRETS_PROVIDER": "encrypted-file",
+		"APP_SECRETS_FILE":     filepath.Join(home, "secrets.json"),
+	}})
+	assert.Error(t, err)
+}
+
+func TestFlags(t *testing.T) {
+
+	f := Flags{}
+	assert.NoError(t, f.Set("APP_LOG_LEVEL=debug"))
+	assert.NoError(t, f.Set("APP_URL=http://a=b"))
+	assert.Error(t, f.Set("APP_LOG_LEVEL"))
+	assert.Error(t, f.Set("=debug"))
+	assert.Equal(t, "APP_LOG_LEVEL=debug,APP_URL=http://a=b", f.String())
+}
+
+func TestList(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, List(" a, ,b,"))
+	assert.Nil(t, List(""))
+}
+
+func TestMaxBodySizeRoutes(t *testing.T) {
+	r := Requests{MaxBodySizes: []string{"POST /api/merchants=4096", "PUT  /api/merchants/{id}=8192"}}
+	assert.Equal(t, map[string]int64{
+		"POST /api/merchants":     4096,
+		"PUT /api/merchants/{id}": 8192,
+	}, r.MaxBodySizeRoutes())
+
+	for _, items := range [][]string{
+		{"/api/merchants=4096"},
+		{"POST /api/merchants=0"},
+		{"POST /api/merchants=4KB"},
+	} {
+		_, err := routeSizes(items)
+		assert.Error(t, err, "%v", items)
+	}
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	yaml "gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field - a setting and the struct field it is stored in
+type field struct {
+	name     string
+	def      string
+	secret   bool
+	required bool
+	oneof    []string
+	min, max *int64
+	v        reflect.Value
+}
+
+// structFields returns the tagged fields of v, descending into untagged
+// struct fields
+func structFields(v reflect.Value) []*field {
+
+	var fields []*field
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		name := sf.Tag.Get("env")
+		if name == "" {
+			if sf.Type.Kind() == reflect.Struct {
+				fields = append(fields, structFields(v.Field(i))...)
+			}
+			continue
+		}
+
+		f := field{
+			name:     name,
+			def:      sf.Tag.Get("default"),
+			secret:   sf.Tag.Get("secret") == "true",
+			required: sf.Tag.Get("required") == "true",
+			min:      tagInt(sf.Tag.Get("min")),
+			max:      tagInt(sf.Tag.Get("max")),
+			v:        v.Field(i),
+		}
+		if s := sf.Tag.Get("oneof"); s != "" {
+			f.oneof = strings.Split(s, ",")
+		}
+
+		fields = append(fields, &f)
+	}
+
+	return fields
+}
+
+func tagInt(s string) *int64 {
+	if s == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid config tag %s: %v", s, err))
+	}
+	return &n
+}
+
+// set parses and validates s into the field
+func (f *field) set(s string) error {
+
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		if f.required {
+			return fmt.Errorf("is required")
+		}
+		f.v.Set(reflect.Zero(f.v.Type()))
+		return nil
+	}
+
+	if len(f.oneof) > 0 && !contains(f.oneof, s) {
+		return fmt.Errorf("must be one of %s", strings.Join(f.oneof, ", "))
+	}
+
+	switch {
+	case f.v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 30s or 5m")
+		}
+		if d < 0 {
+			return fmt.Errorf("must not be negative")
+		}
+		f.v.SetInt(int64(d))
+
+	case f.v.Kind() == reflect.Int:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be a whole number")
+		}
+		if f.min != nil && n < *f.min {
+			return fmt.Errorf("must be at least %d", *f.min)
+		}
+		if f.max != nil && n > *f.max {
+			return fmt.Errorf("must be at most %d", *f.max)
+		}
+		f.v.SetInt(n)
+
+	case f.v.Kind() == reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		f.v.SetBool(b)
+
+	case f.v.Kind() == reflect.Slice:
+		f.v.Set(reflect.ValueOf(List(s)))
+
+	default:
+		f.v.SetString(s)
+	}
+
+	return nil
+}
+
+// parseBool accepts strconv.ParseBool values, 1, t, true, 0, f, false
+func parseBool(s string) (bool, error) {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("must be true or false")
+	}
+	return b, nil
+}
+
+// List splits a comma separated list, dropping empty items
+func List(s string) []string {
+	var l []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			l = append(l, item)
+		}
+	}
+	return l
+}
+
+func contains(l []string, s string) bool {
+	for _, item := range l {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// readFile reads a YAML or TOML config file of settings, by the file's
+// extension. Lists are joined with commas so every value is read as it
+// would be from the environment.
+func readFile(path string) (map[string]string, error) {
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(b, &raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	default:
+		return nil, fmt.Errorf("Invalid config file %s: must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid config file %s: %v", path, err)
+	}
+
+	vals := map[string]string{}
+	for k, v := range raw {
+		switch tv := v.(type) {
+		case nil:
+			vals[k] = ""
+		case []interface{}:
+			var items []string
+			for _, item := range tv {
+				items = append(items, fmt.Sprint(item))
+			}
+			vals[k] = strings.Join(items, ",")
+		case map[string]interface{}:
+			return nil, fmt.Errorf("Invalid config file %s: %s must be a value or list", path, k)
+		default:
+			vals[k] = fmt.Sprint(tv)
+		}
+	}
+
+	return vals, nil
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/reload"
+)
+
+// connector opens connections with its current connect string, which
+// Reconnect replaces when credentials rotate
+type connector struct {
+	mu     sync.RWMutex
+	dsn    string
+	driver driver.Driver
+}
+
+// dbConnectors - the connectors of a db from NewDB, replica is nil without
+// APP_DATABASE_REPLICA_URL
+type dbConnectors struct {
+	primary *connector
+	replica *connector
+}
+
+// connectors - the connectors of each db from NewDB
+var (
+	connectors   = map[*sqlx.DB]*dbConnectors{}
+	connectorsMu sync.Mutex
+)
+
+// Connect -
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.DSN())
+}
+
+// Driver -
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// DSN - the current connect string
+func (c *connector) DSN() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dsn
+}
+
+func (c *connector) setDSN(dsn string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dsn = dsn
+}
+
+// Reconnect switches d to the database credentials in c when they differ
+// from those it connects with, returning whether they changed. The new
+// credentials are tried before switching, on failure d keeps the old
+// ones. Idle connections are closed so the pool reconnects with the new
+// password, connections in use finish their work first.
+func Reconnect(d *sqlx.DB, c *config.Config) (bool, error) {
+
+	connectorsMu.Lock()
+	cs := connectors[d]
+	connectorsMu.Unlock()
+
+	if cs == nil {
+		return false, fmt.Errorf("Database was not opened by NewDB")
+	}
+
+	dsn, err := connectString(c.Database, c.Database.User, c.Database.Pass)
+	if err != nil {
+		return false, err
+	}
+
+	var rdsn string
+	if cs.replica != nil {
+		rdsn, err = replicaConnectString(c.Database, c.Database.User, c.Database.Pass)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	changed := dsn != cs.primary.DSN()
+	replicaChanged := cs.replica != nil && rdsn != cs.replica.DSN()
+	if !changed && !replicaChanged {
+		return false, nil
+	}
+
+	// both are tried before either is switched
+	if changed {
+		err = tryConnect(cs.primary, dsn)
+		if err != nil {
+			return false, fmt.Errorf("New database credentials failed to connect: %v", err)
+		}
+	}
+	if replicaChanged {
+		err = tryConnect(cs.replica, rdsn)
+		if err != nil {
+			return false, fmt.Errorf("New database credentials failed to connect to the replica: %v", err)
+		}
+	}
+
+	if changed {
+		cs.primary.setDSN(dsn)
+	}
+	if replicaChanged {
+		cs.replica.setDSN(rdsn)
+	}
+
+	// closes idle connections, restoring the limit
+	d.SetMaxIdleConns(0)
+	d.SetMaxIdleConns(c.Database.MaxIdleConns)
+
+	return true, nil
+}
+
+func tryConnect(cn *connector, dsn string) error {
+	conn, err := cn.driver.Open(dsn)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// WatchSecrets reloads the configuration on SIGHUP, and every
+// APP_SECRETS_RELOAD_INTERVAL, reconnecting d when the database
+// credentials have rotated. Call stop on shutdown.
+func WatchSecrets(l zerolog.Logger, e *env.Env, d *sqlx.DB) (stop func()) {
+
+	l = logger.Package(l, "db")
+
+	return reload.Watch(e.Config.Secrets.ReloadInterval, func() {
+
+		c, err := e.Reload()
+		if err != nil {
+			l.Error().Msgf("Reload secrets error, keeping current database credentials: %v", err)
+			return
+		}
+
+		changed, err := Reconnect(d, c)
+		if err != nil {
+			l.Error().Msgf("Reconnect database error, keeping current credentials: %v", err)
+			return
+		}
+
+		if changed {
+			l.Info().Msg("Database credentials rotated, reconnecting")
+		}
+	})
+}
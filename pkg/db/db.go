@@ -2,79 +2,100 @@ package db
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
-	"strconv"
 	"github.com/jmoiron/sqlx"
 	postgres "github.com/lib/pq"
 	"github.com/rs/zerolog"
 	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/logger"
 )
 
-// NewDB gets a new db and returns an error if need be
+// NewDB gets a new db and returns an error if need be. Connections are
+// opened with the credentials current at the time so Reconnect can rotate
+// them.
 func NewDB(l zerolog.Logger, e *env.Env) *sqlx.DB {
-	var conn *sqlx.DB
-	var err error
 
-	connectString := connectString(e)
+	c := e.Config.Database
+
+	l = logger.Package(l, "db")
+
+	var drv driver.Driver = &postgres.Driver{}
 
 	// timed requests
-	if e.Get("APP_TIMED_REQUESTS") != "" && e.Get("APP_TIMED_REQUESTS") != "0" {
-		sql.Register("wrapped-postgres", WrapDriver(&postgres.Driver{}, l))
-		db, err := sql.Open("wrapped-postgres", connectString)
-		if err != nil {
-			panic(fmt.Sprintf("MustGetNewDB error: %v", err))
-		}
-		conn = sqlx.NewDb(db, "postgres")
-	} else {
-		conn, err = sqlx.Connect("postgres", connectString)
+	if c.TimedRequests {
+		drv = WrapDriver(drv, l)
+	}
+
+	dsn, err := connectString(c, c.User, c.Pass)
+	if err != nil {
+		panic(fmt.Sprintf("MustGetNewDB error: %v", err))
+	}
+
+	cs := &dbConnectors{primary: &connector{dsn: dsn, driver: drv}}
+
+	var dc driver.Connector = cs.primary
+
+	// read replica - read-only transactions run on the replica
+	if c.ReplicaURL != "" {
+		rdsn, err := replicaConnectString(c, c.User, c.Pass)
 		if err != nil {
 			panic(fmt.Sprintf("MustGetNewDB error: %v", err))
 		}
+		cs.replica = &connector{dsn: rdsn, driver: drv}
+		dc = newReplicaConnector(l, c, cs.primary, cs.replica)
 	}
 
-	poolConfig(e, conn)
+	conn := sqlx.NewDb(sql.OpenDB(dc), "postgres")
+	err = conn.Ping()
+	if err != nil {
+		panic(fmt.Sprintf("MustGetNewDB error: %v", err))
+	}
+
+	poolConfig(l, e, conn)
+
+	connectorsMu.Lock()
+	connectors[conn] = cs
+	connectorsMu.Unlock()
 
 	return conn
 }
 
-func connectString(e *env.Env) string {
+// NewOwnerDB gets a new db connected as the database owner, for schema
+// migrations. The application user is used when no owner is configured.
+func NewOwnerDB(l zerolog.Logger, e *env.Env) *sqlx.DB {
 
-	host := e.Get("APP_DATABASE_HOST")
-	user := e.Get("APP_DATABASE_USER")
-	pass := e.Get("APP_DATABASE_PASS")
-	port := e.Get("APP_DATABASE_PORT")
-	dbname := e.Get("APP_DATABASE_NAME")
+	c := e.Config.Database
 
-	return fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable", user, pass, dbname, host, port)
+	user, pass := c.OwnerUser, c.OwnerPass
+	if user == "" {
+		user, pass = c.User, c.Pass
+	}
 
-}
+	dsn, err := connectString(c, user, pass)
+	if err != nil {
+		panic(fmt.Sprintf("MustGetNewDB error: %v", err))
+	}
 
-func poolConfig(e *env.Env, d *sqlx.DB) error {
+	conn, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		panic(fmt.Sprintf("MustGetNewDB error: %v", err))
+	}
 
-	maxIdleCons := 50
-	maxOpenCons := 100
-	var err error
+	return conn
+}
 
-	maxIdleConsStr := e.Get("APP_DATABASE_MAX_IDLE_CONNS")
+// poolConfig sizes the connection pool and limits how long connections
+// are kept, the settings are validated when the configuration is loaded
+func poolConfig(l zerolog.Logger, e *env.Env, d *sqlx.DB) {
 
-	if maxIdleConsStr != "" {
-		maxIdleCons, err = strconv.Atoi(maxIdleConsStr)
-		if err != nil {
-			return err
-		}
-	}
+	c := e.Config.Database
 
-	maxOpenConsStr := e.Get("APP_DATABASE_MAX_OPEN_CONNS")
+	d.SetMaxIdleConns(c.MaxIdleConns)
+	d.SetMaxOpenConns(c.MaxOpenConns)
+	d.SetConnMaxLifetime(c.ConnMaxLifetime)
 
-	if maxOpenConsStr != "" {
-		maxOpenCons, err = strconv.Atoi(maxOpenConsStr)
-		if err != nil {
-			return err
-		}
+	if c.ConnMaxIdleTime > 0 && !setConnMaxIdleTime(d, c.ConnMaxIdleTime) {
+		l.Warn().Msg("APP_DATABASE_CONN_MAX_IDLE_TIME needs Go 1.15 or later, ignored")
 	}
-
-	d.SetMaxIdleConns(maxIdleCons)
-	d.SetMaxOpenConns(maxOpenCons)
-
-	return nil
 }
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	postgres "github.com/lib/pq"
+	"github.com/vegh1010/test/pkg/config"
+)
+
+// connectString returns the key=value connect string for the primary,
+// from APP_DATABASE_URL or the host, port and name. user and pass, and the
+// connection settings, override the URL's values when set.
+func connectString(c config.Database, user, pass string) (string, error) {
+
+	base, err := primaryBase(c)
+	if err != nil {
+		return "", err
+	}
+
+	return withOptions(base, c, user, pass), nil
+}
+
+// replicaConnectString returns the connect string for the replica, the
+// primary's with the values of APP_DATABASE_REPLICA_URL in place, so the
+// replica URL may name just the host
+func replicaConnectString(c config.Database, user, pass string) (string, error) {
+
+	base, err := primaryBase(c)
+	if err != nil {
+		return "", err
+	}
+
+	replica, err := keyValues(c.ReplicaURL)
+	if err != nil {
+		return "", fmt.Errorf("Invalid APP_DATABASE_REPLICA_URL: %v", err)
+	}
+
+	return withOptions(join(base, replica), c, user, pass), nil
+}
+
+func primaryBase(c config.Database) (string, error) {
+
+	if c.URL == "" {
+		return join(
+			option("dbname", c.Name),
+			option("host", c.Host),
+			option("port", strconv.Itoa(c.Port)),
+			option("sslmode", "disable"),
+		), nil
+	}
+
+	base, err := keyValues(c.URL)
+	if err != nil {
+		return "", fmt.Errorf("Invalid APP_DATABASE_URL: %v", err)
+	}
+
+	return base, nil
+}
+
+// withOptions appends the settings that are set, later values win
+func withOptions(base string, c config.Database, user, pass string) string {
+
+	name := c.ApplicationName
+	if name == "" {
+		name = filepath.Base(os.Args[0])
+	}
+
+	opts := []string{
+		base,
+		option("user", user),
+		option("password", pass),
+		option("sslmode", c.SSLMode),
+		option("sslrootcert", c.SSLRootCert),
+		option("sslcert", c.SSLCert),
+		option("sslkey", c.SSLKey),
+		option("application_name", name),
+	}
+
+	if c.ConnectTimeout > 0 {
+		// whole seconds, at least one as 0 waits forever
+		secs := int(math.Ceil(c.ConnectTimeout.Seconds()))
+		opts = append(opts, option("connect_timeout", strconv.Itoa(secs)))
+	}
+
+	if c.Schema != "" {
+		opts = append(opts, option("search_path", postgres.QuoteIdentifier(c.Schema)+", public"))
+	}
+
+	if c.StatementTimeout > 0 {
+		ms := int64(c.StatementTimeout / time.Millisecond)
+		opts = append(opts, option("statement_timeout", strconv.FormatInt(ms, 10)))
+	}
+
+	return join(opts...)
+}
+
+// keyValues converts a postgres:// URL to a key=value connect string,
+// which is returned as is
+func keyValues(s string) (string, error) {
+	if strings.HasPrefix(s, "postgres://") || strings.HasPrefix(s, "postgresql://") {
+		return postgres.ParseURL(s)
+	}
+	return s, nil
+}
+
+// option returns key='value', empty when value is
+func option(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `'`, `\'`, -1)
+	return key + "='" + value + "'"
+}
+
+func join(opts ...string) string {
+	var s []string
+	for _, o := range opts {
+		if o != "" {
+			s = append(s, o)
+		}
+	}
+	return strings.Join(s, " ")
+}
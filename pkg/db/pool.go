@@ -0,0 +1,15 @@
+// +build !go1.15
+
+package db
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// setConnMaxIdleTime is not supported before Go 1.15, idle connections are
+// closed by SetMaxIdleConns and SetConnMaxLifetime alone
+func setConnMaxIdleTime(d *sqlx.DB, t time.Duration) bool {
+	return false
+}
@@ -0,0 +1,15 @@
+// +build go1.15
+
+package db
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// setConnMaxIdleTime closes connections idle for longer than t
+func setConnMaxIdleTime(d *sqlx.DB, t time.Duration) bool {
+	d.SetConnMaxIdleTime(t)
+	return true
+}
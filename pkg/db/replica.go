@@ -0,0 +1,406 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/config"
+)
+
+// replicaConnector opens connections to the primary that run read-only
+// transactions on the read replica while it is within APP_DATABASE_REPLICA_MAX_LAG
+// of the primary, and on the primary when it is not or can not be reached
+type replicaConnector struct {
+	primary *connector
+	replica *connector
+	health  *replicaHealth
+	logger  zerolog.Logger
+}
+
+func newReplicaConnector(l zerolog.Logger, c config.Database, primary, replica *connector) *replicaConnector {
+	return &replicaConnector{
+		primary: primary,
+		replica: replica,
+		health:  newReplicaHealth(l, c, replica),
+		logger:  l,
+	}
+}
+
+// Connect -
+func (c *replicaConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.primary.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &routedConn{connector: c, primary: conn}, nil
+}
+
+// Driver -
+func (c *replicaConnector) Driver() driver.Driver {
+	return c.primary.Driver()
+}
+
+// routedConn - a primary connection with a replica connection opened for
+// the first read-only transaction. Both stay open while the conn is pooled
+// so MaxOpenConns and MaxIdleConns apply to each server. database/sql uses
+// a connection from one goroutine at a time so the fields need no locking.
+type routedConn struct {
+	connector *replicaConnector
+	primary   driver.Conn
+	replica   driver.Conn
+	onReplica bool
+}
+
+// conn returns the connection of the current transaction
+func (c *routedConn) conn() driver.Conn {
+	if c.onReplica {
+		return c.replica
+	}
+	return c.primary
+}
+
+func (c *routedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext prepares on the current connection, and on the other when
+// the statement is first used there. Statements are prepared once on the
+// db and used by transactions on either.
+func (c *routedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	conn := c.conn()
+
+	stmt, err := prepare(ctx, conn, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &routedStmt{conn: c, query: query, stmts: map[driver.Conn]driver.Stmt{conn: stmt}, numInput: stmt.NumInput()}, nil
+}
+
+func (c *routedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx begins read-only transactions on the replica when it is healthy,
+// falling back to the primary when it is not
+func (c *routedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+
+	if opts.ReadOnly && c.connector.health.ok() {
+		tx, err := c.beginReplica(ctx, opts)
+		if err == nil {
+			c.onReplica = true
+			return &routedTx{conn: c, parent: tx}, nil
+		}
+		c.connector.logger.Warn().Msgf("Could not begin tx on the read replica, using the primary: %v", err)
+		c.connector.health.failed()
+	}
+
+	return beginTx(ctx, c.primary, opts)
+}
+
+func (c *routedConn) beginReplica(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+
+	if c.replica == nil {
+		conn, err := c.connector.replica.Connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.replica = conn
+	}
+
+	tx, err := beginTx(ctx, c.replica, opts)
+	if err != nil {
+		// opened again for the next read-only tx
+		c.replica.Close()
+		c.replica = nil
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (c *routedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.conn().(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *routedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := c.conn().(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// CheckNamedValue lets the driver convert arguments as it does without a
+// replica
+func (c *routedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.primary.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *routedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.primary.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *routedConn) Close() error {
+	if c.replica != nil {
+		c.replica.Close()
+	}
+	return c.primary.Close()
+}
+
+// routedTx - a transaction on the replica, later statements go to the
+// primary again once it ends
+type routedTx struct {
+	conn   *routedConn
+	parent driver.Tx
+}
+
+func (t *routedTx) Commit() error {
+	t.conn.onReplica = false
+	return t.parent.Commit()
+}
+
+func (t *routedTx) Rollback() error {
+	t.conn.onReplica = false
+	return t.parent.Rollback()
+}
+
+// routedStmt - a statement prepared on the connections it has been used on
+type routedStmt struct {
+	conn     *routedConn
+	query    string
+	stmts    map[driver.Conn]driver.Stmt
+	numInput int
+}
+
+func (s *routedStmt) stmt(ctx context.Context) (driver.Stmt, error) {
+	conn := s.conn.conn()
+
+	if stmt, ok := s.stmts[conn]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := prepare(ctx, conn, s.query)
+	if err != nil {
+		return nil, err
+	}
+	s.stmts[conn] = stmt
+
+	return stmt, nil
+}
+
+func (s *routedStmt) Close() error {
+	var err error
+	for _, stmt := range s.stmts {
+		if cerr := stmt.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (s *routedStmt) NumInput() int {
+	return s.numInput
+}
+
+func (s *routedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	stmt, err := s.stmt(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(args)
+}
+
+func (s *routedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	stmt, err := s.stmt(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(args)
+}
+
+func (s *routedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	stmt, err := s.stmt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if stmtExecContext, ok := stmt.(driver.StmtExecContext); ok {
+		return stmtExecContext.ExecContext(ctx, args)
+	}
+
+	dargs, err := namedValueToValue(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.Exec(dargs)
+}
+
+func (s *routedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	stmt, err := s.stmt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if stmtQueryContext, ok := stmt.(driver.StmtQueryContext); ok {
+		return stmtQueryContext.QueryContext(ctx, args)
+	}
+
+	dargs, err := namedValueToValue(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.Query(dargs)
+}
+
+func prepare(ctx context.Context, conn driver.Conn, query string) (driver.Stmt, error) {
+	if connPrepareCtx, ok := conn.(driver.ConnPrepareContext); ok {
+		return connPrepareCtx.PrepareContext(ctx, query)
+	}
+	return conn.Prepare(query)
+}
+
+func beginTx(ctx context.Context, conn driver.Conn, opts driver.TxOptions) (driver.Tx, error) {
+	if connBeginTx, ok := conn.(driver.ConnBeginTx); ok {
+		return connBeginTx.BeginTx(ctx, opts)
+	}
+	return conn.Begin()
+}
+
+// replicaHealth - whether the replica is close enough to the primary to
+// read from, checked at most every APP_DATABASE_REPLICA_CHECK_INTERVAL
+type replicaHealth struct {
+	logger   zerolog.Logger
+	maxLag   time.Duration
+	interval time.Duration
+
+	mu       sync.Mutex
+	checking bool
+	checked  time.Time
+	healthy  bool
+	lag      func(ctx context.Context) (time.Duration, error)
+}
+
+func newReplicaHealth(l zerolog.Logger, c config.Database, replica *connector) *replicaHealth {
+
+	// a connection of its own, so checks do not wait for requests
+	d := sql.OpenDB(replica)
+	d.SetMaxOpenConns(1)
+	d.SetMaxIdleConns(1)
+
+	h := replicaHealth{
+		logger:   l,
+		maxLag:   c.ReplicaMaxLag,
+		interval: c.ReplicaCheckInterval,
+	}
+	h.lag = func(ctx context.Context) (time.Duration, error) {
+		return replicaLag(ctx, d)
+	}
+
+	return &h
+}
+
+// ok returns whether to read from the replica. One caller checks the lag
+// when the last check is older than the interval, others use its result
+// meanwhile.
+func (h *replicaHealth) ok() bool {
+
+	h.mu.Lock()
+	if h.checking || time.Since(h.checked) < h.interval {
+		healthy := h.healthy
+		h.mu.Unlock()
+		return healthy
+	}
+	h.checking = true
+	first := h.checked.IsZero()
+	h.mu.Unlock()
+
+	timeout := h.interval
+	if timeout < time.Second {
+		timeout = time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	lag, err := h.lag(ctx)
+	cancel()
+
+	healthy := err == nil && lag <= h.maxLag
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if first || healthy != h.healthy {
+		switch {
+		case err != nil:
+			h.logger.Warn().Msgf("Read replica unavailable, reading from the primary: %v", err)
+		case !healthy:
+			h.logger.Warn().Msgf("Read replica %s behind, reading from the primary", lag)
+		default:
+			h.logger.Info().Msg("Reading from the read replica")
+		}
+	}
+
+	h.checking = false
+	h.checked = time.Now()
+	h.healthy = healthy
+
+	return healthy
+}
+
+// failed marks the replica unhealthy until the next check
+func (h *replicaHealth) failed() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checked = time.Now()
+	h.healthy = false
+}
+
+// replicaLag returns how far the replica is behind the primary
+func replicaLag(ctx context.Context, d *sql.DB) (time.Duration, error) {
+
+	var version int
+	err := d.QueryRowContext(ctx, `SELECT current_setting('server_version_num')::int`).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	var secs float64
+	err = d.QueryRowContext(ctx, replicaLagSQL(version)).Scan(&secs)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// replicaLagSQL returns the seconds since the last replayed transaction,
+// or 0 when everything received has been replayed so an idle primary does
+// not look like lag. The functions were renamed in PostgreSQL 10.
+func replicaLagSQL(version int) string {
+
+	receive, replay := "pg_last_wal_receive_lsn()", "pg_last_wal_replay_lsn()"
+	if version < 100000 {
+		receive, replay = "pg_last_xlog_receive_location()", "pg_last_xlog_replay_location()"
+	}
+
+	return `
+SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN ` + receive + ` = ` + replay + ` THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END
+`
+}
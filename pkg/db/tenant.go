@@ -0,0 +1,21 @@
+package db
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// AllTenants - tenant scope that sees every tenant's rows, for workers and
+// tools acting on behalf of the system rather than a tenant
+const AllTenants = "*"
+
+// setTenantSQL - set_config with is_local true is SET LOCAL, which can
+// not take a bind parameter
+const setTenantSQL = `SELECT set_config('app.tenant_id', $1, true)`
+
+// SetTenant scopes the rest of a tx to a tenant. Tenant tables are
+// protected by row level security policies that only expose rows matching
+// app.tenant_id, a tx that has not set a tenant sees no tenant rows.
+func SetTenant(tx *sqlx.Tx, tenantID string) error {
+	_, err := tx.Exec(setTenantSQL, tenantID)
+	return err
+}
@@ -0,0 +1,44 @@
+package encryption
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey = strings.Repeat("ab", KeySize)
+
+func TestParseKey(t *testing.T) {
+	key, err := parseKey(testKey)
+	assert.NoError(t, err)
+	assert.Len(t, key, KeySize)
+
+	_, err = parseKey("abcd")
+	assert.Error(t, err)
+
+	_, err = parseKey("not hex")
+	assert.Error(t, err)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, err := parseKey(testKey)
+	assert.NoError(t, err)
+
+	ct, err := Encrypt(key, "000123456")
+	assert.NoError(t, err)
+	assert.NotContains(t, ct, "000123456")
+
+	// nonce is random so the same plaintext encrypts differently
+	ct2, err := Encrypt(key, "000123456")
+	assert.NoError(t, err)
+	assert.NotEqual(t, ct, ct2)
+
+	pt, err := Decrypt(key, ct)
+	assert.NoError(t, err)
+	assert.Equal(t, "000123456", pt)
+
+	other, _ := parseKey(strings.Repeat("cd", KeySize))
+	_, err = Decrypt(other, ct)
+	assert.Error(t, err)
+}
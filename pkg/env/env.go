@@ -3,128 +3,65 @@ package env
 import (
 	"fmt"
 	"os"
-	"strings"
 
-	"github.com/joho/godotenv"
+	"github.com/vegh1010/test/pkg/config"
 )
 
 // Env - contains environment values
 type Env struct {
-	env map[string]string
+	// Config - the typed configuration
+	Config *config.Config
+	env    map[string]string
+	opts   *config.Options
 }
 
-// NewEnv - Create a new environment
+// NewEnv - Create a new environment from the environment alone, exiting
+// with every invalid value listed when the configuration is invalid
 func NewEnv() *Env {
-
-	e := Env{env: map[string]string{}}
-
-	err := e.init()
-	if err != nil {
-		panic(fmt.Sprintf("NewEnv failed for %v", err))
-	}
-
-	return &e
+	return MustLoad(nil)
 }
 
-func (e *Env) init() error {
-
-	h := os.Getenv("APP_HOME")
+// MustLoad - Create a new environment, exiting with every invalid value
+// listed when the configuration is invalid
+func MustLoad(o *config.Options) *Env {
 
-	if h == "" {
-		panic("APP HOME not set")
-	}
-
-	envFile := fmt.Sprintf("%s/%s", os.Getenv("APP_HOME"), ".env")
-	err := godotenv.Load(envFile)
+	e, err := Load(o)
 	if err != nil {
-		// not fatal
-		fmt.Printf("Missing local .env file %s, not loading\n", envFile)
-	}
-
-	// possible items
-	envItems := []string{
-
-		// general
-		"APP_ENV",
-		"APP_HOME",
-		"APP_URL",
-		"APP_LOG_LEVEL",
-		"APP_TIMED_REQUESTS",
-		"APP_PRETTY_LOGS",
-		"APP_SERVER_PORT",
-		"BUILD_NUMBER",
-
-		// database
-		"APP_DATABASE_HOST",
-		"APP_DATABASE_USER",
-		"APP_DATABASE_PASS",
-		"APP_DATABASE_NAME",
-		"APP_DATABASE_PORT",
-		"APP_DATABASE_MAX_IDLE_CONNS",
-		"APP_DATABASE_MAX_OPEN_CONNS",
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(1)
 	}
 
-	// required items
-	// - Mostly everything is required to exists even if it
-	//   has a bogus value at the moment
-	reqItems := []string{
-
-		// general
-		"APP_ENV",
-		"APP_HOME",
-		"APP_URL",
-		"APP_LOG_LEVEL",
-		"APP_TIMED_REQUESTS",
-		"APP_PRETTY_LOGS",
-		"APP_SERVER_PORT",
-
-		// database
-		"APP_DATABASE_HOST",
-		"APP_DATABASE_USER",
-		"APP_DATABASE_PASS",
-		"APP_DATABASE_NAME",
-		"APP_DATABASE_PORT",
-	}
+	return e
+}
 
-	for _, envItem := range envItems {
-		osEnvValue := os.Getenv(envItem)
+// Load - Create a new environment, see config.Load for the sources values
+// are read from
+func Load(o *config.Options) (*Env, error) {
 
-		// dont show passwords or keys
-		if strings.Contains(envItem, "PASS") == false && strings.Contains(envItem, "KEY") == false {
-			fmt.Printf("ENV Key %s Val %s\n", envItem, osEnvValue)
-		}
-		e.env[envItem] = os.Getenv(envItem)
-	}
-
-	err = e.CheckRequired(reqItems)
+	c, err := config.Load(o)
 	if err != nil {
-		panic(fmt.Errorf("Required environment variables missing %v", err))
+		return nil, err
 	}
 
-	return nil
+	return &Env{Config: c, env: map[string]string{}, opts: o}, nil
 }
 
-// CheckRequired - Tests that the provided list of environment variabled
-// have been set
-func (e *Env) CheckRequired(reqd []string) error {
-
-	// required check
-	for _, reqItem := range reqd {
-		if e.env[reqItem] == "" {
-			return fmt.Errorf("Could not find required env value for : %s", reqItem)
-		}
-	}
-
-	return nil
+// Reload loads the configuration again from the same sources, reading
+// secrets afresh. The Env is unchanged, callers apply what they need from
+// the returned configuration.
+func (e *Env) Reload() (*config.Config, error) {
+	return config.Load(e.opts)
 }
 
 // Get an env key
 func (e *Env) Get(k string) string {
-	v := e.env[k]
-	return v
+	if v, ok := e.env[k]; ok {
+		return v
+	}
+	return e.Config.Get(k)
 }
 
-// Set an env key
+// Set an env key, the typed Config is unchanged
 func (e *Env) Set(k string, v string) {
 	e.env[k] = v
 }
@@ -0,0 +1,199 @@
+// Package featureflag decides whether a feature is on for the tenant and
+// API client making a request, so new behaviour can be released to some
+// callers before others.
+//
+// Flags and their rules are kept in the feature_flag tables, managed with
+// test-featureflag, and cached for APP_FEATURE_FLAG_TTL. A rule for the API
+// client wins over a rule for its tenant, which wins over the flag. Flags
+// that do not exist are off.
+package featureflag
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model/featureflag"
+	"github.com/vegh1010/test/pkg/principalcontext"
+)
+
+// Flag - a flag with its rules by tenant and API client ID
+type Flag struct {
+	Name    string
+	Enabled bool
+	Tenants map[string]bool
+	Clients map[string]bool
+}
+
+// EnabledFor returns whether the flag is on for a principal, p may be nil
+// for unauthenticated requests
+func (f *Flag) EnabledFor(p *principalcontext.Principal) bool {
+
+	if p != nil {
+		if on, ok := f.Clients[p.ID]; ok {
+			return on
+		}
+		if on, ok := f.Tenants[p.TenantID]; ok {
+			return on
+		}
+	}
+
+	return f.Enabled
+}
+
+// Flags returns flags by name from their records
+func Flags(recs []*featureflag.Record, rules []*featureflag.RuleRecord) map[string]*Flag {
+
+	flags := map[string]*Flag{}
+	byID := map[string]*Flag{}
+
+	for _, rec := range recs {
+		f := &Flag{
+			Name:    rec.Name,
+			Enabled: rec.Enabled,
+			Tenants: map[string]bool{},
+			Clients: map[string]bool{},
+		}
+		flags[rec.Name] = f
+		byID[rec.ID] = f
+	}
+
+	for _, rule := range rules {
+		f, ok := byID[rule.FeatureFlagID]
+		if !ok {
+			continue
+		}
+		if rule.APIClientID.Valid {
+			f.Clients[rule.APIClientID.String] = rule.Enabled
+		}
+		if rule.TenantID.Valid {
+			f.Tenants[rule.TenantID.String] = rule.Enabled
+		}
+	}
+
+	return flags
+}
+
+// Store - flags read from the database and cached
+type Store struct {
+	Env    *env.Env
+	Logger zerolog.Logger
+	DB     *sqlx.DB
+	// TTL - how long flags are cached, read for every check when 0
+	TTL time.Duration
+
+	mu      sync.Mutex
+	flags   map[string]*Flag
+	expires time.Time
+	// loading - whether a check is reading flags, others use the flags
+	// last read meanwhile
+	loading bool
+	load    func() (map[string]*Flag, error)
+}
+
+// NewStore returns a store caching flags for APP_FEATURE_FLAG_TTL
+func NewStore(e *env.Env, l zerolog.Logger, db *sqlx.DB) *Store {
+	s := Store{
+		Env:    e,
+		Logger: l,
+		DB:     db,
+		TTL:    e.Config.FeatureFlags.TTL,
+		flags:  map[string]*Flag{},
+	}
+	s.load = s.loadDB
+	return &s
+}
+
+// Enabled returns whether the named flag is on for a principal. Flags
+// that cannot be read are off until they can, or as last read once they
+// have been.
+func (s *Store) Enabled(name string, p *principalcontext.Principal) bool {
+
+	f, ok := s.flag(name)
+	if !ok {
+		return false
+	}
+
+	return f.EnabledFor(p)
+}
+
+// flag returns a flag, reading flags again once they expire. They are
+// read without holding the lock so other checks are not held up by the
+// database, using the flags last read until the new ones are swapped in.
+func (s *Store) flag(name string) (*Flag, bool) {
+
+	now := time.Now()
+
+	s.mu.Lock()
+	load := !now.Before(s.expires) && !s.loading
+	if load {
+		s.loading = true
+	}
+	s.mu.Unlock()
+
+	if load {
+		flags, err := s.load()
+
+		s.mu.Lock()
+		if err != nil {
+			s.Logger.Error().Msgf("Could not read feature flags, using flags last read %v", err)
+		} else {
+			s.flags = flags
+		}
+		// failures are retried after the TTL too rather than on every check
+		s.expires = now.Add(s.TTL)
+		s.loading = false
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	f, ok := s.flags[name]
+	s.mu.Unlock()
+
+	return f, ok
+}
+
+func (s *Store) loadDB() (map[string]*Flag, error) {
+
+	tx, err := s.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	m, err := featureflag.NewModel(s.Env, s.Logger, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	recs, err := m.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := m.GetAllRules()
+	if err != nil {
+		return nil, err
+	}
+
+	return Flags(recs, rules), nil
+}
+
+// defaultStore - set by the router, used by handlers
+var defaultStore *Store
+
+// SetDefault sets the store Enabled checks
+func SetDefault(s *Store) {
+	defaultStore = s
+}
+
+// Enabled returns whether the named flag is on for a principal using the
+// default store, every flag is off when there is none
+func Enabled(name string, p *principalcontext.Principal) bool {
+	if defaultStore == nil {
+		return false
+	}
+	return defaultStore.Enabled(name, p)
+}
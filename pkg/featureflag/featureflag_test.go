@@ -0,0 +1,109 @@
+package featureflag
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/model/featureflag"
+	"github.com/vegh1010/test/pkg/principalcontext"
+)
+
+func TestEnabledFor(t *testing.T) {
+
+	flags := Flags(
+		[]*featureflag.Record{
+			{ID: "f1", Name: "merchant_patch", Enabled: false},
+			{ID: "f2", Name: "new_search", Enabled: true},
+		},
+		[]*featureflag.RuleRecord{
+			{FeatureFlagID: "f1", TenantID: sql.NullString{String: "t1", Valid: true}, Enabled: true},
+			{FeatureFlagID: "f1", APIClientID: sql.NullString{String: "c2", Valid: true}, Enabled: false},
+			{FeatureFlagID: "f2", TenantID: sql.NullString{String: "t2", Valid: true}, Enabled: false},
+			{FeatureFlagID: "missing", TenantID: sql.NullString{String: "t1", Valid: true}, Enabled: true},
+		},
+	)
+
+	tests := []struct {
+		flag string
+		p    *principalcontext.Principal
+		on   bool
+	}{
+		// flag default
+		{"merchant_patch", nil, false},
+		{"merchant_patch", &principalcontext.Principal{ID: "c9", TenantID: "t9"}, false},
+		{"new_search", &principalcontext.Principal{ID: "c1", TenantID: "t1"}, true},
+		// tenant rule
+		{"merchant_patch", &principalcontext.Principal{ID: "c1", TenantID: "t1"}, true},
+		{"new_search", &principalcontext.Principal{ID: "c3", TenantID: "t2"}, false},
+		// API client rule wins over its tenant
+		{"merchant_patch", &principalcontext.Principal{ID: "c2", TenantID: "t1"}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.on, flags[tt.flag].EnabledFor(tt.p), "%s %v", tt.flag, tt.p)
+	}
+}
+
+func TestStore(t *testing.T) {
+
+	loads := 0
+	var err error
+	on := true
+
+	s := Store{Logger: zerolog.Nop(), TTL: time.Hour, flags: map[string]*Flag{}}
+	s.load = func() (map[string]*Flag, error) {
+		loads++
+		if err != nil {
+			return nil, err
+		}
+		return map[string]*Flag{"merchant_patch": {Name: "merchant_patch", Enabled: on}}, nil
+	}
+
+	// cached for the TTL
+	assert.True(t, s.Enabled("merchant_patch", nil))
+	on = false
+	assert.True(t, s.Enabled("merchant_patch", nil))
+	assert.False(t, s.Enabled("unknown", nil))
+	assert.Equal(t, 1, loads)
+
+	// read again once expired
+	s.expires = time.Now()
+	assert.False(t, s.Enabled("merchant_patch", nil))
+	assert.Equal(t, 2, loads)
+
+	// flags last read are kept when they cannot be read
+	on = true
+	err = errors.New("connection refused")
+	s.expires = time.Now()
+	assert.False(t, s.Enabled("merchant_patch", nil))
+	assert.False(t, s.Enabled("merchant_patch", nil))
+	assert.Equal(t, 3, loads)
+
+	// checks while flags are read use those last read rather than wait
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	s.load = func() (map[string]*Flag, error) {
+		close(loading)
+		<-release
+		return map[string]*Flag{}, nil
+	}
+	s.flags = map[string]*Flag{"merchant_patch": {Name: "merchant_patch", Enabled: true}}
+	s.expires = time.Now()
+	done := make(chan bool)
+	go func() {
+		done <- s.Enabled("merchant_patch", nil)
+	}()
+	<-loading
+	assert.True(t, s.Enabled("merchant_patch", nil))
+	close(release)
+	assert.False(t, <-done)
+	assert.False(t, s.Enabled("merchant_patch", nil))
+
+	// no store, every flag is off
+	SetDefault(nil)
+	assert.False(t, Enabled("merchant_patch", nil))
+}
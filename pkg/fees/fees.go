@@ -0,0 +1,74 @@
+package fees
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/shopspring/decimal"
+	"github.com/vegh1010/test/pkg/util/decimalutil"
+)
+
+// Tier - fees charged on amounts from MinAmount up to the next tier.
+// Amounts are in minor units of the schedule currency.
+type Tier struct {
+	MinAmount  int64
+	Percentage decimal.Decimal
+	FixedFee   int64
+}
+
+// Fee - calculated fee for an amount, in minor units
+type Fee struct {
+	Amount        int64
+	Tier          Tier
+	PercentageFee int64
+	FixedFee      int64
+	Total         int64
+	Net           int64
+}
+
+// ErrNoTier - no tier applies to the amount
+var ErrNoTier = errors.New("No fee tier applies to amount")
+
+var hundred = decimal.New(100, 0)
+
+// TierFor returns the tier with the highest minimum amount that the
+// amount reaches.
+func TierFor(tiers []Tier, amount int64) (Tier, error) {
+	sorted := make([]Tier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinAmount > sorted[j].MinAmount })
+
+	for _, t := range sorted {
+		if amount >= t.MinAmount {
+			return t, nil
+		}
+	}
+	return Tier{}, ErrNoTier
+}
+
+// Calculate calculates the fee for an amount in minor units of currency,
+// which must have known minor units. The percentage fee is rounded to
+// whole minor units with banker's rounding, the same as
+// decimalutil.DecimalToMinorUnits.
+func Calculate(tiers []Tier, amount int64, currency string) (*Fee, error) {
+	if _, ok := decimalutil.Exponent(currency); !ok {
+		return nil, decimalutil.ErrUnknownCurrency
+	}
+
+	t, err := TierFor(tiers, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	pf := decimal.New(amount, 0).Mul(t.Percentage).Div(hundred).RoundBank(0).IntPart()
+	total := pf + t.FixedFee
+
+	return &Fee{
+		Amount:        amount,
+		Tier:          t,
+		PercentageFee: pf,
+		FixedFee:      t.FixedFee,
+		Total:         total,
+		Net:           amount - total,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/util/decimalutil"
+)
+
+// minor units as loaded from the currency table
+func init() {
+	decimalutil.SetExponents(map[string]int32{"AUD": 2})
+}
+
+func pct(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+var tiers = []Tier{
+	{MinAmount: 100000, Percentage: pct("1.5"), FixedFee: 10},
+	{MinAmount: 0, Percentage: pct("2.9"), FixedFee: 30},
+}
+
+func TestTierFor(t *testing.T) {
+	tier, err := TierFor(tiers, 99999)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), tier.MinAmount)
+
+	tier, err = TierFor(tiers, 100000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100000), tier.MinAmount)
+
+	_, err = TierFor(tiers[:1], 50)
+	assert.Equal(t, ErrNoTier, err)
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		amount        int64
+		percentageFee int64
+		total         int64
+	}{
+		// 2.9% of 10.00 is 0.29, plus 0.30
+		{1000, 29, 59},
+		// 2.9% of 0.50 is 0.0145, rounds to 0.01
+		{50, 1, 31},
+		// 2.9% of 0.25 is 0.00725, rounds to 0.01
+		{25, 1, 31},
+		// 1.5% of 2000.00 is 30.00, plus 0.10
+		{200000, 3000, 3010},
+	}
+
+	for _, tt := range tests {
+		fee, err := Calculate(tiers, tt.amount, "AUD")
+		assert.NoError(t, err)
+		assert.Equal(t, tt.percentageFee, fee.PercentageFee)
+		assert.Equal(t, tt.total, fee.Total)
+		assert.Equal(t, tt.amount-tt.total, fee.Net)
+	}
+
+	// not loaded
+	_, err := Calculate(tiers, 1000, "XXX")
+	assert.Equal(t, decimalutil.ErrUnknownCurrency, err)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// collectionFlushEvery - records written between flushes so clients and
+// compression receive a large collection as it is encoded
+const collectionFlushEvery = 100
+
+// CollectionEncoder writes a collection response, {"data": [...]}, one
+// record at a time so large collections are not built in memory first.
+//
+// The response is started by the first record. Until then errors may be
+// sent with Error as a normal error response, after that the response can
+// not be changed and Error aborts it. Close must be called once all
+// records are written.
+type CollectionEncoder struct {
+	h       *Base
+	w       http.ResponseWriter
+	r       *http.Request
+	enc     *json.Encoder
+	count   int
+	started bool
+}
+
+// NewCollectionEncoder -
+func (h *Base) NewCollectionEncoder(w http.ResponseWriter, r *http.Request) *CollectionEncoder {
+	return &CollectionEncoder{
+		h:   h,
+		w:   w,
+		r:   r,
+		enc: json.NewEncoder(w),
+	}
+}
+
+// start writes the response header
+func (e *CollectionEncoder) start() error {
+
+	e.started = true
+
+	// content type json
+	e.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	// Status Ok
+	e.w.WriteHeader(http.StatusOK)
+
+	_, err := e.w.Write([]byte(`{"data":[`))
+
+	return err
+}
+
+// Encode writes a record
+func (e *CollectionEncoder) Encode(v interface{}) error {
+
+	if !e.started {
+		err := e.start()
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.count > 0 {
+		_, err := e.w.Write([]byte(","))
+		if err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	err := e.enc.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	if f, ok := e.w.(http.Flusher); ok && e.count%collectionFlushEvery == 0 {
+		f.Flush()
+	}
+
+	return nil
+}
+
+// Close commits the tx and ends the collection. Records are read within
+// the tx as they are written, so unlike SendResponse the tx is committed
+// once all records have been written. An empty collection is sent when no
+// records were written.
+func (e *CollectionEncoder) Close() error {
+
+	err := e.h.commitTx(e.r)
+	if err != nil {
+		return err
+	}
+
+	if !e.started {
+		err = e.start()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = e.w.Write([]byte("]}\n"))
+
+	return err
+}
+
+// Error sends an error response when the response has not started.
+// Otherwise the response is aborted, closing the connection so the client
+// sees an incomplete response rather than a truncated collection.
+func (e *CollectionEncoder) Error(err error) {
+
+	if !e.started {
+		e.h.SendErrorResponse(e.w, e.r, err)
+		return
+	}
+
+	e.h.Logger.Error().Msgf("Aborting collection response after %d records %v", e.count, err)
+
+	e.h.rollbackTx(e.r)
+
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// DefaultMaxBodySize - request bodies larger than this are rejected unless
+// APP_MAX_BODY_SIZE or APP_MAX_BODY_SIZES says otherwise
+const DefaultMaxBodySize = 1 << 20
+
+// Unknown field modes for APP_JSON_UNKNOWN_FIELDS
+const (
+	UnknownFieldsReject = "reject"
+	UnknownFieldsIgnore = "ignore"
+)
+
+// errBodyTooLarge - returned by limitReader once the limit is exceeded
+var errBodyTooLarge = errors.New("request body too large")
+
+// DecodeOptions - how request bodies are decoded
+type DecodeOptions struct {
+	MaxBodySize        int64
+	AllowUnknownFields bool
+	// Routes - largest request body accepted by particular routes, in
+	// place of MaxBodySize, by method and path template
+	Routes map[string]int64
+}
+
+// defaultDecodeOptions - for handlers built without options
+var defaultDecodeOptions = DecodeOptions{MaxBodySize: DefaultMaxBodySize}
+
+// NewDecodeOptions returns the options configured by APP_MAX_BODY_SIZE,
+// APP_MAX_BODY_SIZES and APP_JSON_UNKNOWN_FIELDS
+func NewDecodeOptions(e *env.Env) *DecodeOptions {
+	return decodeOptions(e.Config.Requests)
+}
+
+func decodeOptions(c config.Requests) *DecodeOptions {
+
+	o := DecodeOptions{
+		MaxBodySize:        int64(c.MaxBodySize),
+		AllowUnknownFields: c.UnknownFields == UnknownFieldsIgnore,
+	}
+	if o.MaxBodySize <= 0 {
+		o.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if routes := c.MaxBodySizeRoutes(); len(routes) > 0 {
+		o.Routes = routes
+	}
+
+	return &o
+}
+
+// maxBodySize returns the largest body accepted by the request's route
+func (o *DecodeOptions) maxBodySize(r *http.Request) int64 {
+
+	path := r.URL.Path
+	if cr := mux.CurrentRoute(r); cr != nil {
+		if t, err := cr.GetPathTemplate(); err == nil {
+			path = t
+		}
+	}
+
+	if n, ok := o.Routes[r.Method+" "+path]; ok {
+		return n
+	}
+
+	return o.MaxBodySize
+}
+
+// SetDecodeOptions sets how the handler decodes request bodies, once when
+// routes are registered
+func (h *Base) SetDecodeOptions(o *DecodeOptions) {
+	h.Decode = o
+}
+
+// DecodeRequest decodes a JSON request body into s.
+//
+// The body must be sent as application/json and be no larger than the
+// route's limit. Fields that are not part of s are rejected unless
+// APP_JSON_UNKNOWN_FIELDS is ignore. Errors are returned as response errors
+// naming the offending field where possible.
+func (h *Base) DecodeRequest(r *http.Request, s interface{}) error {
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return resperror.ValidationErr("Request body is required")
+	}
+
+	o := h.Decode
+	if o == nil {
+		o = &defaultDecodeOptions
+	}
+
+	return decode(r, s, o)
+}
+
+func decode(r *http.Request, s interface{}, o *DecodeOptions) error {
+
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mt != "application/json" {
+		return resperror.ErrorUnsupportedMediaType
+	}
+
+	max := o.maxBodySize(r)
+
+	if r.ContentLength > max {
+		return resperror.ErrorRequestTooLarge
+	}
+
+	dec := json.NewDecoder(&limitReader{r: r.Body, n: max})
+	if !o.AllowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	err = dec.Decode(s)
+	if err == nil {
+		// only a single value is accepted
+		var extra json.RawMessage
+		err = dec.Decode(&extra)
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			return resperror.ValidationErr("Request body must contain a single JSON value")
+		}
+	}
+
+	return decodeError(err)
+}
+
+// decodeError converts a decoding error into a response error
+func decodeError(err error) error {
+
+	if err == errBodyTooLarge {
+		return resperror.ErrorRequestTooLarge
+	}
+	if err == io.EOF {
+		return resperror.ValidationErr("Request body is required")
+	}
+	if err == io.ErrUnexpectedEOF {
+		return resperror.ValidationErr("Request body is incomplete JSON")
+	}
+
+	switch et := err.(type) {
+	case *json.UnmarshalTypeError:
+		field := et.Field
+		if field == "" {
+			field = "Request body"
+		}
+		return resperror.ValidationInvalidType(field, jsonType(et.Type))
+	case *json.SyntaxError:
+		return err
+	}
+
+	// encoding/json has no error type for unknown fields
+	const unknownField = "json: unknown field "
+	if msg := err.Error(); strings.HasPrefix(msg, unknownField) {
+		field, uerr := strconv.Unquote(strings.TrimPrefix(msg, unknownField))
+		if uerr != nil {
+			field = strings.TrimPrefix(msg, unknownField)
+		}
+		return resperror.ValidationUnknownField(field)
+	}
+
+	return err
+}
+
+// jsonType describes the JSON value expected for a Go type
+func jsonType(t reflect.Type) string {
+
+	if t == nil {
+		return "a valid value"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	}
+
+	return "a valid value"
+}
+
+// limitReader reads up to n bytes and then fails with errBodyTooLarge,
+// unlike io.LimitReader which ends the body early as if it were complete
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read -
+func (l *limitReader) Read(p []byte) (int, error) {
+
+	if l.n < 0 {
+		return 0, errBodyTooLarge
+	}
+
+	// read one byte past the limit to tell a body of exactly n bytes
+	// from a larger one
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return 0, errBodyTooLarge
+	}
+
+	return n, err
+}
@@ -13,7 +13,11 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/lib/pq"
 	"gopkg.in/olivere/elastic.v6"
+	"github.com/vegh1010/test/pkg/featureflag"
+	"github.com/vegh1010/test/pkg/model"
 	"github.com/vegh1010/test/pkg/modelstore"
+	"github.com/vegh1010/test/pkg/principalcontext"
+	"github.com/vegh1010/test/pkg/requestidcontext"
 	"github.com/vegh1010/test/pkg/resperror"
 	"github.com/vegh1010/test/pkg/txcontext"
 	"github.com/vegh1010/test/pkg/env"
@@ -34,6 +38,7 @@ type Handler interface {
 	GetVersioned() bool
 	GetLogger() zerolog.Logger
 	GetLockResources() map[string]map[string]string
+	SetDecodeOptions(o *DecodeOptions)
 }
 
 // LockResource -
@@ -51,6 +56,9 @@ type Base struct {
 	Env             *env.Env
 	Logger          zerolog.Logger
 	LockResources   map[string]map[string]string
+	// Decode - how request bodies are decoded, set when routes are
+	// registered, the defaults when nil
+	Decode *DecodeOptions
 }
 
 // Params -
@@ -208,14 +216,6 @@ func (h *Base) GetLogger() zerolog.Logger {
 	return h.Logger
 }
 
-// DecodeRequest -
-func (h *Base) DecodeRequest(r *http.Request, s interface{}) error {
-	if r.Body == nil {
-		return nil
-	}
-	return json.NewDecoder(r.Body).Decode(s)
-}
-
 // SendErrorResponse sends an error response to the user.
 //
 // It calls rollback on any db tx available in the request's context
@@ -248,6 +248,21 @@ func (h *Base) SendErrorResponse(w http.ResponseWriter, r *http.Request, e error
 		if et.Code == resperror.ErrCodeNotFound {
 			httpcode = http.StatusNotFound
 		}
+		if et.Code == resperror.ErrCodeUnauthenticated {
+			httpcode = http.StatusUnauthorized
+		}
+		if et.Code == resperror.ErrCodeForbidden {
+			httpcode = http.StatusForbidden
+		}
+		if et.Code == resperror.ErrCodeRateLimited {
+			httpcode = http.StatusTooManyRequests
+		}
+		if et.Code == resperror.ErrCodeUnsupportedMediaType {
+			httpcode = http.StatusUnsupportedMediaType
+		}
+		if et.Code == resperror.ErrCodeRequestTooLarge {
+			httpcode = http.StatusRequestEntityTooLarge
+		}
 		rerr.Error = et
 	case *json.SyntaxError:
 		rerr.Error = resperror.ValidationJSONSyntax(et.Offset)
@@ -421,6 +436,60 @@ func (h *Base) rollbackTx(r *http.Request) error {
 
 }
 
+// AuditContext returns who changes made while handling a request are
+// attributed to
+func (h *Base) AuditContext(r *http.Request) *model.AuditContext {
+
+	ac := &model.AuditContext{
+		ActorID:   model.SystemActor,
+		ActorName: model.SystemActor,
+		RequestID: requestidcontext.GetContext(r),
+	}
+
+	p, err := principalcontext.GetContext(r)
+	if err == nil {
+		ac.ActorID = p.ID
+		ac.ActorName = p.Name
+	}
+
+	return ac
+}
+
+// RequireAdmin returns a forbidden error unless the request was made by
+// an admin principal
+func (h *Base) RequireAdmin(r *http.Request) error {
+
+	p, err := principalcontext.GetContext(r)
+	if err != nil || !p.IsAdmin() {
+		return resperror.ErrorForbidden
+	}
+
+	return nil
+}
+
+// RequireOperator returns a forbidden error unless the request was made
+// by an operator principal. Admins manage their own tenant, operators
+// the service every tenant shares.
+func (h *Base) RequireOperator(r *http.Request) error {
+
+	p, err := principalcontext.GetContext(r)
+	if err != nil || !p.IsOperator() {
+		return resperror.ErrorForbidden
+	}
+
+	return nil
+}
+
+// FeatureEnabled returns whether a feature flag is on for the principal
+// making the request, see package featureflag
+func (h *Base) FeatureEnabled(r *http.Request, name string) bool {
+
+	// unauthenticated requests have no principal, flag defaults apply
+	p, _ := principalcontext.GetContext(r)
+
+	return featureflag.Enabled(name, p)
+}
+
 // PreHandlerChecks -
 func (h *Base) PreHandlerChecks(r *http.Request) (*modelstore.ModelStore, Params, error) {
 
@@ -436,6 +505,9 @@ func (h *Base) PreHandlerChecks(r *http.Request) (*modelstore.ModelStore, Params
 		return nil, nil, err
 	}
 
+	// audit changes to the principal making the request
+	ms.SetAuditContext(h.AuditContext(r))
+
 	// validate params
 	vars := mux.Vars(r)
 	params, errs := h.ValidateParams(vars)
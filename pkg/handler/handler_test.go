@@ -1 +1,122 @@
 package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+type testData struct {
+	Name   string   `json:"name"`
+	Count  int      `json:"count"`
+	Active bool     `json:"active"`
+	Tags   []string `json:"tags"`
+}
+
+type testRequest struct {
+	Data *testData `json:"data"`
+}
+
+func decodeBody(h *Base, contentType, body string) (*testRequest, error) {
+	r := httptest.NewRequest("POST", "/api/test", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	req := testRequest{}
+	err := h.DecodeRequest(r, &req)
+	return &req, err
+}
+
+func TestDecodeOptions(t *testing.T) {
+	o := decodeOptions(config.Requests{})
+	assert.Equal(t, &DecodeOptions{MaxBodySize: DefaultMaxBodySize}, o)
+
+	o = decodeOptions(config.Requests{
+		MaxBodySize:   2048,
+		MaxBodySizes:  []string{"POST /api/merchants=4096", "PUT  /api/merchants/{id}=8192"},
+		UnknownFields: UnknownFieldsIgnore,
+	})
+	assert.Equal(t, &DecodeOptions{
+		MaxBodySize:        2048,
+		AllowUnknownFields: true,
+		Routes: map[string]int64{
+			"POST /api/merchants":     4096,
+			"PUT /api/merchants/{id}": 8192,
+		},
+	}, o)
+}
+
+func TestDecodeRequest(t *testing.T) {
+	h := &Base{}
+
+	req, err := decodeBody(h, "application/json; charset=utf-8", `{"data":{"name":"Shop","count":2,"tags":["a"]}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, &testData{Name: "Shop", Count: 2, Tags: []string{"a"}}, req.Data)
+
+	tests := []struct {
+		contentType string
+		body        string
+		code        int
+		detail      string
+	}{
+		{"", `{}`, resperror.ErrCodeUnsupportedMediaType, ""},
+		{"text/plain", `{}`, resperror.ErrCodeUnsupportedMediaType, ""},
+		{"application/json", ``, resperror.ErrCodeBadFormat, "required"},
+		{"application/json", `{"data":{"name":1}}`, resperror.ErrCodeInvalidType, "name must be a string"},
+		{"application/json", `{"data":{"count":"2"}}`, resperror.ErrCodeInvalidType, "count must be an integer"},
+		{"application/json", `{"data":{"active":"yes"}}`, resperror.ErrCodeInvalidType, "active must be a boolean"},
+		{"application/json", `{"data":{"tags":"a"}}`, resperror.ErrCodeInvalidType, "tags must be an array"},
+		{"application/json", `{"data":{"nmae":"Shop"}}`, resperror.ErrCodeUnknownField, "Unknown field nmae"},
+		{"application/json", `{"data":{}} {"data":{}}`, resperror.ErrCodeBadFormat, "single"},
+	}
+
+	for _, tc := range tests {
+		_, err := decodeBody(h, tc.contentType, tc.body)
+		rerr, ok := err.(*resperror.Data)
+		if assert.True(t, ok, "%s %v", tc.body, err) {
+			assert.Equal(t, tc.code, rerr.Code, tc.body)
+			assert.Contains(t, rerr.Detail, tc.detail, tc.body)
+		}
+	}
+}
+
+func TestDecodeRequestUnknownFieldsIgnored(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/test", strings.NewReader(`{"data":{"nmae":"Shop"}}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	req := testRequest{}
+	err := decode(r, &req, &DecodeOptions{MaxBodySize: DefaultMaxBodySize, AllowUnknownFields: true})
+	assert.NoError(t, err)
+	assert.Equal(t, &testData{}, req.Data)
+}
+
+func TestDecodeRequestMaxBodySize(t *testing.T) {
+	h := &Base{}
+	h.SetDecodeOptions(&DecodeOptions{
+		MaxBodySize: DefaultMaxBodySize,
+		Routes:      map[string]int64{"POST /api/test": 16},
+	})
+
+	_, err := decodeBody(h, "application/json", `{"data":{}}`)
+	assert.NoError(t, err)
+
+	_, err = decodeBody(h, "application/json", `{"data":{"name":"Shop"}}`)
+	assert.Equal(t, resperror.ErrorRequestTooLarge, err)
+
+	// chunked, the size is not known up front
+	r := httptest.NewRequest("POST", "/api/test", strings.NewReader(`{"data":{"name":"Shop"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.ContentLength = -1
+	err = h.DecodeRequest(r, &testRequest{})
+	assert.Equal(t, resperror.ErrorRequestTooLarge, err)
+
+	// other routes use APP_MAX_BODY_SIZE
+	r = httptest.NewRequest("PUT", "/api/test", strings.NewReader(`{"data":{"name":"Shop"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	err = h.DecodeRequest(r, &testRequest{})
+	assert.NoError(t, err)
+}
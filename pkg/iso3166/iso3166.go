@@ -0,0 +1,415 @@
+// Package iso3166 reconciles the country and country_subdivision tables
+// with a data file of ISO 3166-1 countries and ISO 3166-2 subdivisions,
+// database/data/iso3166.json by default.
+//
+// Countries and subdivisions in the file are added or updated and made
+// active, those missing from the file are deactivated rather than deleted
+// as merchants and addresses may refer to them.
+package iso3166
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model/country"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// DefaultFile - the bundled data file, relative to APP_HOME
+const DefaultFile = "database/data/iso3166.json"
+
+// File - countries and subdivisions to import
+type File struct {
+	// Source - where the data came from
+	Source       string         `json:"source"`
+	Countries    []*Country     `json:"countries"`
+	Subdivisions []*Subdivision `json:"subdivisions"`
+}
+
+// Country - an ISO 3166-1 country with its calling code and default
+// currency, either may be empty
+type Country struct {
+	Alpha2      string `json:"alpha2"`
+	Alpha3      string `json:"alpha3"`
+	Numeric     string `json:"numeric"`
+	Name        string `json:"name"`
+	CallingCode string `json:"calling_code"`
+	Currency    string `json:"currency"`
+}
+
+// Subdivision - an ISO 3166-2 subdivision, Parent is the code of the
+// subdivision it sits within
+type Subdivision struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Parent string `json:"parent"`
+}
+
+var (
+	alpha2Re      = regexp.MustCompile(`^[A-Z]{2}$`)
+	alpha3Re      = regexp.MustCompile(`^[A-Z]{3}$`)
+	numericRe     = regexp.MustCompile(`^[0-9]{3}$`)
+	callingCodeRe = regexp.MustCompile(`^[0-9]{1,4}$`)
+	subdivisionRe = regexp.MustCompile(`^[A-Z]{2}-[A-Z0-9]{1,3}$`)
+)
+
+// Load reads and validates a data file
+func Load(path string) (*File, error) {
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := File{}
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid data file %s: %v", path, err)
+	}
+
+	err = f.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid data file %s: %v", path, err)
+	}
+
+	return &f, nil
+}
+
+// Validate checks codes are well formed and unique, and that subdivisions
+// belong to countries and parents in the file
+func (f *File) Validate() error {
+
+	if len(f.Countries) == 0 {
+		// an empty file would deactivate every country
+		return fmt.Errorf("No countries")
+	}
+
+	countries := map[string]bool{}
+	for _, c := range f.Countries {
+		switch {
+		case c == nil:
+			return fmt.Errorf("Empty country")
+		case !alpha2Re.MatchString(c.Alpha2):
+			return fmt.Errorf("Country alpha2 %q must be 2 upper case letters", c.Alpha2)
+		case !alpha3Re.MatchString(c.Alpha3):
+			return fmt.Errorf("Country %s alpha3 %q must be 3 upper case letters", c.Alpha2, c.Alpha3)
+		case !numericRe.MatchString(c.Numeric):
+			return fmt.Errorf("Country %s numeric %q must be 3 digits", c.Alpha2, c.Numeric)
+		case c.CallingCode != "" && !callingCodeRe.MatchString(c.CallingCode):
+			return fmt.Errorf("Country %s calling_code %q must be up to 4 digits", c.Alpha2, c.CallingCode)
+		case c.Name == "":
+			return fmt.Errorf("Country %s has no name", c.Alpha2)
+		case countries[c.Alpha2]:
+			return fmt.Errorf("Country %s is repeated", c.Alpha2)
+		}
+		countries[c.Alpha2] = true
+	}
+
+	subdivisions := map[string]bool{}
+	for _, s := range f.Subdivisions {
+		switch {
+		case s == nil:
+			return fmt.Errorf("Empty subdivision")
+		case !subdivisionRe.MatchString(s.Code):
+			return fmt.Errorf("Subdivision code %q must be a country and up to 3 letters or digits, i.e. AU-NSW", s.Code)
+		case !countries[s.Code[:2]]:
+			return fmt.Errorf("Subdivision %s country is not in the file", s.Code)
+		case s.Name == "" || s.Type == "":
+			return fmt.Errorf("Subdivision %s has no name or type", s.Code)
+		case subdivisions[s.Code]:
+			return fmt.Errorf("Subdivision %s is repeated", s.Code)
+		}
+		subdivisions[s.Code] = true
+	}
+
+	for _, s := range f.Subdivisions {
+		if s.Parent == "" {
+			continue
+		}
+		if !subdivisions[s.Parent] || s.Parent[:2] != s.Code[:2] || s.Parent == s.Code {
+			return fmt.Errorf("Subdivision %s parent %s is not a subdivision of the same country in the file", s.Code, s.Parent)
+		}
+	}
+
+	return nil
+}
+
+// Change kinds
+const (
+	ChangeAdd        = "add"
+	ChangeRename     = "rename"
+	ChangeUpdate     = "update"
+	ChangeReactivate = "reactivate"
+	ChangeDeactivate = "deactivate"
+)
+
+// Entities changed
+const (
+	EntityCountry     = "country"
+	EntitySubdivision = "subdivision"
+)
+
+// Change - a difference between the tables and the file
+type Change struct {
+	Kind   string
+	Entity string
+	ID     string
+	Detail string
+}
+
+// String - i.e. "rename country CZ from Czech Republic to Czechia"
+func (c Change) String() string {
+	s := c.Kind + " " + c.Entity + " " + c.ID
+	if c.Detail != "" {
+		s += " " + c.Detail
+	}
+	return s
+}
+
+// Plan - the changes needed to reconcile the tables with a file
+type Plan struct {
+	Changes []Change
+
+	countries              []*country.Record
+	subdivisions           []*country.SubdivisionRecord
+	deactivateCountries    []string
+	deactivateSubdivisions []string
+}
+
+// Counts returns the number of changes of each kind
+func (p *Plan) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, c := range p.Changes {
+		counts[c.Kind]++
+	}
+	return counts
+}
+
+// Summary - i.e. "3 added, 1 renamed, 0 updated, 0 reactivated, 1 deactivated"
+func (p *Plan) Summary() string {
+	c := p.Counts()
+	return fmt.Sprintf("%d added, %d renamed, %d updated, %d reactivated, %d deactivated",
+		c[ChangeAdd], c[ChangeRename], c[ChangeUpdate], c[ChangeReactivate], c[ChangeDeactivate])
+}
+
+// Diff compares the current countries and subdivisions with a file
+func Diff(f *File, countries []*country.Record, subdivisions []*country.SubdivisionRecord) *Plan {
+
+	p := Plan{}
+
+	cur := map[string]*country.Record{}
+	for _, rec := range countries {
+		cur[rec.ID] = rec
+	}
+
+	inFile := map[string]bool{}
+	for _, c := range f.Countries {
+		inFile[c.Alpha2] = true
+
+		next := &country.Record{
+			ID:          c.Alpha2,
+			Name:        c.Name,
+			Alpha2Code:  c.Alpha2,
+			Alpha3Code:  c.Alpha3,
+			NumericCode: c.Numeric,
+			CallingCode: util.ToNullString(c.CallingCode),
+			CurrencyID:  util.ToNullString(c.Currency),
+			Status:      country.StatusActive,
+		}
+
+		changes := diffCountry(cur[c.Alpha2], next)
+		if len(changes) > 0 {
+			p.Changes = append(p.Changes, changes...)
+			p.countries = append(p.countries, next)
+		}
+	}
+
+	for _, rec := range countries {
+		if !inFile[rec.ID] && rec.Status == country.StatusActive {
+			p.Changes = append(p.Changes, Change{Kind: ChangeDeactivate, Entity: EntityCountry, ID: rec.ID, Detail: rec.Name})
+			p.deactivateCountries = append(p.deactivateCountries, rec.ID)
+		}
+	}
+
+	curSubdivisions := map[string]*country.SubdivisionRecord{}
+	for _, rec := range subdivisions {
+		curSubdivisions[rec.ID] = rec
+	}
+
+	inFile = map[string]bool{}
+	for _, s := range f.Subdivisions {
+		inFile[s.Code] = true
+
+		next := &country.SubdivisionRecord{
+			ID:        s.Code,
+			CountryID: s.Code[:2],
+			Code:      s.Code[3:],
+			Name:      s.Name,
+			Type:      s.Type,
+			ParentID:  util.ToNullString(s.Parent),
+			Status:    country.StatusActive,
+		}
+
+		changes := diffSubdivision(curSubdivisions[s.Code], next)
+		if len(changes) > 0 {
+			p.Changes = append(p.Changes, changes...)
+			p.subdivisions = append(p.subdivisions, next)
+		}
+	}
+
+	for _, rec := range subdivisions {
+		if !inFile[rec.ID] && rec.Status == country.StatusActive {
+			p.Changes = append(p.Changes, Change{Kind: ChangeDeactivate, Entity: EntitySubdivision, ID: rec.ID, Detail: rec.Name})
+			p.deactivateSubdivisions = append(p.deactivateSubdivisions, rec.ID)
+		}
+	}
+
+	return &p
+}
+
+func diffCountry(cur, next *country.Record) []Change {
+
+	if cur == nil {
+		return []Change{{Kind: ChangeAdd, Entity: EntityCountry, ID: next.ID, Detail: next.Name}}
+	}
+
+	var changes []Change
+
+	if cur.Name != next.Name {
+		changes = append(changes, Change{Kind: ChangeRename, Entity: EntityCountry, ID: next.ID, Detail: fmt.Sprintf("from %s to %s", cur.Name, next.Name)})
+	}
+
+	fields := changedFields(map[string][2]string{
+		"alpha3_code":  {cur.Alpha3Code, next.Alpha3Code},
+		"numeric_code": {cur.NumericCode, next.NumericCode},
+		"calling_code": {cur.CallingCode.String, next.CallingCode.String},
+		"currency_id":  {cur.CurrencyID.String, next.CurrencyID.String},
+	})
+	if fields != "" {
+		changes = append(changes, Change{Kind: ChangeUpdate, Entity: EntityCountry, ID: next.ID, Detail: fields})
+	}
+
+	if cur.Status != country.StatusActive {
+		changes = append(changes, Change{Kind: ChangeReactivate, Entity: EntityCountry, ID: next.ID, Detail: next.Name})
+	}
+
+	return changes
+}
+
+func diffSubdivision(cur, next *country.SubdivisionRecord) []Change {
+
+	if cur == nil {
+		return []Change{{Kind: ChangeAdd, Entity: EntitySubdivision, ID: next.ID, Detail: next.Name}}
+	}
+
+	var changes []Change
+
+	if cur.Name != next.Name {
+		changes = append(changes, Change{Kind: ChangeRename, Entity: EntitySubdivision, ID: next.ID, Detail: fmt.Sprintf("from %s to %s", cur.Name, next.Name)})
+	}
+
+	fields := changedFields(map[string][2]string{
+		"type":      {cur.Type, next.Type},
+		"parent_id": {cur.ParentID.String, next.ParentID.String},
+	})
+	if fields != "" {
+		changes = append(changes, Change{Kind: ChangeUpdate, Entity: EntitySubdivision, ID: next.ID, Detail: fields})
+	}
+
+	if cur.Status != country.StatusActive {
+		changes = append(changes, Change{Kind: ChangeReactivate, Entity: EntitySubdivision, ID: next.ID, Detail: next.Name})
+	}
+
+	return changes
+}
+
+// changedFields - i.e. "calling_code from 1 to 1684", fields ordered by name
+func changedFields(fields map[string][2]string) string {
+
+	var names []string
+	for name, v := range fields {
+		if v[0] != v[1] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s from %q to %q", name, fields[name][0], fields[name][1]))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Apply makes the planned changes, countries first so new subdivisions
+// have their country
+func (p *Plan) Apply(m *country.Model) error {
+
+	for _, rec := range p.countries {
+		err := m.Upsert(rec)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rec := range p.subdivisions {
+		err := m.UpsertSubdivision(rec)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range p.deactivateSubdivisions {
+		err := m.DeactivateSubdivision(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range p.deactivateCountries {
+		err := m.Deactivate(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Import reconciles the tables with a file within tx, returning the
+// changes made. Roll back tx for a dry run.
+func Import(e *env.Env, l zerolog.Logger, tx *sqlx.Tx, f *File) (*Plan, error) {
+
+	m, err := country.NewModel(e, l, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	countries, err := m.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	subdivisions, err := m.GetAllSubdivisions()
+	if err != nil {
+		return nil, err
+	}
+
+	p := Diff(f, countries, subdivisions)
+
+	err = p.Apply(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,141 @@
+package iso3166
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/model/country"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+func TestLoadBundled(t *testing.T) {
+
+	f, err := Load(filepath.Join("..", "..", DefaultFile))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	countries := map[string]*Country{}
+	for _, c := range f.Countries {
+		countries[c.Alpha2] = c
+	}
+	assert.Equal(t, "AUD", countries["AU"].Currency)
+	assert.Equal(t, "61", countries["AU"].CallingCode)
+
+	subdivisions := map[string]*Subdivision{}
+	for _, s := range f.Subdivisions {
+		subdivisions[s.Code] = s
+	}
+	assert.Equal(t, "New South Wales", subdivisions["AU-NSW"].Name)
+}
+
+func TestValidate(t *testing.T) {
+
+	valid := func() *File {
+		return &File{
+			Countries: []*Country{
+				{Alpha2: "AU", Alpha3: "AUS", Numeric: "036", Name: "Australia", CallingCode: "61", Currency: "AUD"},
+				{Alpha2: "GB", Alpha3: "GBR", Numeric: "826", Name: "United Kingdom"},
+			},
+			Subdivisions: []*Subdivision{
+				{Code: "AU-NSW", Name: "New South Wales", Type: "State"},
+				{Code: "GB-ENG", Name: "England", Type: "Country"},
+				{Code: "GB-LND", Name: "London, City of", Type: "City corporation", Parent: "GB-ENG"},
+			},
+		}
+	}
+
+	assert.NoError(t, valid().Validate())
+
+	tests := map[string]func(f *File){
+		"no countries":        func(f *File) { f.Countries = nil },
+		"lower case alpha2":   func(f *File) { f.Countries[0].Alpha2 = "au" },
+		"short numeric":       func(f *File) { f.Countries[0].Numeric = "36" },
+		"long calling code":   func(f *File) { f.Countries[0].CallingCode = "61000" },
+		"repeated country":    func(f *File) { f.Countries[1] = f.Countries[0] },
+		"unknown country":     func(f *File) { f.Subdivisions[0].Code = "NZ-AUK" },
+		"invalid subdivision": func(f *File) { f.Subdivisions[0].Code = "AUNSW" },
+		"repeated subdivision": func(f *File) {
+			f.Subdivisions = append(f.Subdivisions, f.Subdivisions[0])
+		},
+		"unknown parent":       func(f *File) { f.Subdivisions[2].Parent = "GB-SCT" },
+		"parent other country": func(f *File) { f.Subdivisions[2].Parent = "AU-NSW" },
+	}
+
+	for name, change := range tests {
+		f := valid()
+		change(f)
+		assert.Error(t, f.Validate(), name)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+
+	_, err := Load(filepath.Join(os.TempDir(), "iso3166-missing.json"))
+	assert.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+
+	f := &File{
+		Countries: []*Country{
+			{Alpha2: "AU", Alpha3: "AUS", Numeric: "036", Name: "Australia", CallingCode: "61", Currency: "AUD"},
+			{Alpha2: "CZ", Alpha3: "CZE", Numeric: "203", Name: "Czechia", CallingCode: "420", Currency: "CZK"},
+			{Alpha2: "SX", Alpha3: "SXM", Numeric: "534", Name: "Sint Maarten (Dutch part)", CallingCode: "1721", Currency: "ANG"},
+			{Alpha2: "NZ", Alpha3: "NZL", Numeric: "554", Name: "New Zealand", CallingCode: "64", Currency: "NZD"},
+		},
+		Subdivisions: []*Subdivision{
+			{Code: "AU-NSW", Name: "New South Wales", Type: "State"},
+			{Code: "AU-VIC", Name: "Victoria", Type: "State"},
+		},
+	}
+
+	countries := []*country.Record{
+		{ID: "AU", Name: "Australia", Alpha2Code: "AU", Alpha3Code: "AUS", NumericCode: "036", CallingCode: util.ToNullString("61"), CurrencyID: util.ToNullString("AUD"), Status: country.StatusActive},
+		{ID: "CZ", Name: "Czech Republic", Alpha2Code: "CZ", Alpha3Code: "CZE", NumericCode: "203", CurrencyID: util.ToNullString("CZK"), Status: country.StatusActive},
+		{ID: "NZ", Name: "New Zealand", Alpha2Code: "NZ", Alpha3Code: "NZL", NumericCode: "554", CallingCode: util.ToNullString("64"), CurrencyID: util.ToNullString("NZD"), Status: country.StatusInactive},
+		{ID: "AN", Name: "Netherlands Antilles", Alpha2Code: "AN", Alpha3Code: "ANT", NumericCode: "530", Status: country.StatusActive},
+	}
+
+	subdivisions := []*country.SubdivisionRecord{
+		{ID: "AU-NSW", CountryID: "AU", Code: "NSW", Name: "New South Wales", Type: "State", Status: country.StatusActive},
+		{ID: "AU-XX", CountryID: "AU", Code: "XX", Name: "Old", Type: "State", Status: country.StatusActive},
+		{ID: "AU-YY", CountryID: "AU", Code: "YY", Name: "Older", Type: "State", Status: country.StatusInactive},
+	}
+
+	p := Diff(f, countries, subdivisions)
+
+	var got []string
+	for _, c := range p.Changes {
+		got = append(got, c.String())
+	}
+
+	assert.Equal(t, []string{
+		"rename country CZ from Czech Republic to Czechia",
+		`update country CZ calling_code from "" to "420"`,
+		"add country SX Sint Maarten (Dutch part)",
+		"reactivate country NZ New Zealand",
+		"deactivate country AN Netherlands Antilles",
+		"add subdivision AU-VIC Victoria",
+		"deactivate subdivision AU-XX Old",
+	}, got)
+
+	assert.Equal(t, "2 added, 1 renamed, 1 updated, 1 reactivated, 2 deactivated", p.Summary())
+
+	// unchanged records are not upserted
+	assert.Len(t, p.countries, 3)
+	assert.Len(t, p.subdivisions, 1)
+	assert.Equal(t, []string{"AN"}, p.deactivateCountries)
+	assert.Equal(t, []string{"AU-XX"}, p.deactivateSubdivisions)
+
+	// a second run has nothing to do
+	var next []*country.Record
+	for _, c := range f.Countries {
+		next = append(next, &country.Record{ID: c.Alpha2, Name: c.Name, Alpha3Code: c.Alpha3, NumericCode: c.Numeric, CallingCode: util.ToNullString(c.CallingCode), CurrencyID: util.ToNullString(c.Currency), Status: country.StatusActive})
+	}
+	next = append(next, &country.Record{ID: "AN", Name: "Netherlands Antilles", Status: country.StatusInactive})
+	p = Diff(f, next, append(subdivisions[:1], p.subdivisions...))
+	assert.Empty(t, p.Changes)
+}
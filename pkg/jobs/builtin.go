@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Built in job types
+const (
+	TypePurgeCompleted = "jobs.purge_completed"
+)
+
+// PurgeCompletedAfter - how long completed jobs are kept
+const PurgeCompletedAfter = 7 * 24 * time.Hour
+
+// PurgeCompletedHandler returns a handler that removes old completed jobs
+func PurgeCompletedHandler(e *env.Env, l zerolog.Logger) HandlerFunc {
+	return func(ctx context.Context, tx *sqlx.Tx, rec *job.Record) error {
+
+		m, err := job.NewModel(e, l, tx)
+		if err != nil {
+			return err
+		}
+
+		_, err = m.PurgeCompleted(util.GetFutureTime(-PurgeCompletedAfter))
+
+		return err
+	}
+}
@@ -0,0 +1,79 @@
+package jobinit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/jobs"
+	"github.com/vegh1010/test/pkg/retention"
+	"github.com/vegh1010/test/pkg/search"
+	"github.com/vegh1010/test/pkg/tzsync"
+	"github.com/vegh1010/test/pkg/webhooks"
+)
+
+// NewWorker returns a worker with all job handlers and schedules registered.
+func NewWorker(e *env.Env, l zerolog.Logger, db *sqlx.DB) (*jobs.Worker, error) {
+
+	reg := jobs.NewRegistry()
+
+	reg.Register(jobs.TypePurgeCompleted, jobs.PurgeCompletedHandler(e, l))
+
+	// webhooks
+	d, err := webhooks.NewDispatcher(e, l, db)
+	if err != nil {
+		return nil, err
+	}
+	reg.Register(webhooks.TypeDispatch, d.Handler())
+
+	// search, an Elasticsearch index is kept in sync from merchant events
+	if search.Backend(e) == search.BackendElasticsearch {
+		s, err := search.NewElasticSearcher(e, l)
+		if err != nil {
+			return nil, err
+		}
+		err = s.EnsureIndex(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		reg.Register(search.TypeIndexMerchant, search.IndexMerchantHandler(e, l, s))
+		d.Subscribe(search.EnqueueIndexMerchant(e, l))
+	}
+
+	dispatchInterval := e.Config.Webhooks.DispatchInterval
+	if dispatchInterval <= 0 {
+		dispatchInterval = 5 * time.Second
+	}
+
+	// retention, purging is disabled unless a retention period is configured
+	merchantRetention := retention.MerchantRetention(e)
+	if merchantRetention > 0 {
+		reg.Register(retention.TypePurgeMerchants, retention.PurgeMerchantsHandler(e, l, merchantRetention))
+	}
+
+	// timezones, synced from the tz database when an interval is configured
+	tzInterval := tzsync.SyncInterval(e)
+	if tzInterval > 0 {
+		reg.Register(tzsync.TypeSync, tzsync.SyncHandler(e, l, tzsync.Source(e)))
+	}
+
+	w, err := jobs.NewWorker(e, l, db, reg)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Every(jobs.TypePurgeCompleted, 24*time.Hour)
+	w.Every(webhooks.TypeDispatch, dispatchInterval)
+
+	if merchantRetention > 0 {
+		w.Every(retention.TypePurgeMerchants, 24*time.Hour)
+	}
+
+	if tzInterval > 0 {
+		w.Every(tzsync.TypeSync, tzInterval)
+	}
+
+	return w, nil
+}
@@ -0,0 +1,105 @@
+// Package jobs provides a Postgres backed background job queue.
+//
+// Jobs are rows in the job table. They are enqueued using a caller's tx, so a
+// job enqueued while handling a request only becomes visible to workers once
+// the request's tx commits. Workers claim jobs with SELECT ... FOR UPDATE
+// SKIP LOCKED and run the registered handler inside the same tx.
+package jobs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/txcontext"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Options - optional settings when enqueueing a job
+type Options struct {
+	// Queue defaults to job.DefaultQueue
+	Queue string
+	// RunAt schedules the job for a specific time
+	RunAt time.Time
+	// Delay schedules the job relative to now, ignored when RunAt is set
+	Delay time.Duration
+	// MaxAttempts defaults to job.DefaultMaxAttempts
+	MaxAttempts int
+	// UniqueKey prevents the same job being enqueued more than once
+	UniqueKey string
+}
+
+// Enqueue adds a job to the queue within the provided tx.
+//
+// Returns the new job ID, or an empty string when a job with the same
+// unique key already exists.
+func Enqueue(e *env.Env, l zerolog.Logger, tx *sqlx.Tx, jobType string, payload interface{}, opts *Options) (string, error) {
+
+	m, err := job.NewModel(e, l, tx)
+	if err != nil {
+		return "", err
+	}
+
+	rec := m.NewRecord()
+	rec.Type = jobType
+
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", err
+		}
+		rec.Payload = types.JSONText(data)
+	}
+
+	if opts != nil {
+		if opts.Queue != "" {
+			rec.Queue = opts.Queue
+		}
+		if opts.MaxAttempts > 0 {
+			rec.MaxAttempts = opts.MaxAttempts
+		}
+		if opts.UniqueKey != "" {
+			rec.UniqueKey = util.ToNullString(opts.UniqueKey)
+		}
+		if !opts.RunAt.IsZero() {
+			rec.RunAt = opts.RunAt.UTC().Format(time.RFC3339)
+		} else if opts.Delay != 0 {
+			rec.RunAt = util.GetFutureTime(opts.Delay)
+		}
+	}
+
+	err = m.Create(&rec)
+	if err == sql.ErrNoRows {
+		// duplicate unique key
+		l.Debug().Msgf("Job %s with unique key %s already enqueued", jobType, rec.UniqueKey.String)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return rec.ID, nil
+}
+
+// EnqueueFromRequest adds a job to the queue within the request's tx so the
+// job commits or rolls back together with the rest of the request.
+func EnqueueFromRequest(r *http.Request, e *env.Env, l zerolog.Logger, jobType string, payload interface{}, opts *Options) (string, error) {
+
+	tx, err := txcontext.GetContext(r)
+	if err != nil {
+		return "", err
+	}
+
+	return Enqueue(e, l, tx, jobType, payload, opts)
+}
+
+// DecodePayload unmarshals a job's payload into v
+func DecodePayload(rec *job.Record, v interface{}) error {
+	return rec.Payload.Unmarshal(v)
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/model/job"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, BackoffBase, Backoff(0))
+	assert.Equal(t, BackoffBase, Backoff(1))
+	assert.Equal(t, 2*BackoffBase, Backoff(2))
+	assert.Equal(t, 8*BackoffBase, Backoff(4))
+	assert.Equal(t, BackoffMax, Backoff(100))
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+
+	assert.Nil(t, reg.Get("test"))
+
+	reg.Register("test", func(ctx context.Context, tx *sqlx.Tx, rec *job.Record) error {
+		return nil
+	})
+
+	assert.NotNil(t, reg.Get("test"))
+	assert.Equal(t, []string{"test"}, reg.Types())
+}
+
+func TestDecodePayload(t *testing.T) {
+	rec := &job.Record{Payload: []byte(`{"id":"abc"}`)}
+
+	var p struct {
+		ID string `json:"id"`
+	}
+	err := DecodePayload(rec, &p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", p.ID)
+}
+
+func TestWorkerEvery(t *testing.T) {
+	w := &Worker{}
+	w.Every("test", time.Hour)
+
+	assert.Len(t, w.schedules, 1)
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vegh1010/test/pkg/model/job"
+)
+
+// HandlerFunc runs a job.
+//
+// The provided tx is the worker's tx holding the job's row lock. Work done
+// with it is committed when the handler succeeds and rolled back when the
+// handler returns an error.
+type HandlerFunc func(ctx context.Context, tx *sqlx.Tx, rec *job.Record) error
+
+// Registry maps job types to handlers
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewRegistry -
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: map[string]HandlerFunc{},
+	}
+}
+
+// Register a handler for a job type, replacing any existing handler
+func (r *Registry) Register(jobType string, h HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[jobType] = h
+}
+
+// Get the handler for a job type, nil when none is registered
+func (r *Registry) Get(jobType string) HandlerFunc {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.handlers[jobType]
+}
+
+// Types returns all registered job types
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var types []string
+	for t := range r.handlers {
+		types = append(types, t)
+	}
+	return types
+}
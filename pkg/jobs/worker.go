@@ -0,0 +1,306 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Backoff settings for failed jobs
+const (
+	BackoffBase = 10 * time.Second
+	BackoffMax  = 6 * time.Hour
+)
+
+// Backoff returns how long to wait before retrying a job that has failed
+// the given number of attempts, doubling each attempt up to BackoffMax.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	d := BackoffBase
+	for i := 1; i < attempts; i++ {
+		d = d * 2
+		if d >= BackoffMax {
+			return BackoffMax
+		}
+	}
+
+	return d
+}
+
+// schedule - a job enqueued on a fixed interval
+type schedule struct {
+	jobType  string
+	interval time.Duration
+}
+
+// Worker - polls a queue and runs jobs with a pool of goroutines
+type Worker struct {
+	Env          *env.Env
+	Logger       zerolog.Logger
+	DB           *sqlx.DB
+	Registry     *Registry
+	Queue        string
+	Concurrency  int
+	PollInterval time.Duration
+	schedules    []schedule
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewWorker -
+func NewWorker(e *env.Env, l zerolog.Logger, db *sqlx.DB, reg *Registry) (*Worker, error) {
+	w := Worker{
+		Env:          e,
+		Logger:       logger.Package(l, "jobs"),
+		DB:           db,
+		Registry:     reg,
+		Queue:        job.DefaultQueue,
+		Concurrency:  1,
+		PollInterval: time.Second,
+	}
+	err := w.init()
+	return &w, err
+}
+
+func (w *Worker) init() error {
+
+	c := w.Env.Config.Jobs
+
+	if c.Workers > 0 {
+		w.Concurrency = c.Workers
+	}
+
+	if c.PollInterval > 0 {
+		w.PollInterval = c.PollInterval
+	}
+
+	return nil
+}
+
+// Every enqueues a job of the given type once per interval. Each interval
+// is enqueued with a unique key so running several workers does not
+// duplicate scheduled jobs. Must be called before Start.
+func (w *Worker) Every(jobType string, interval time.Duration) {
+	w.schedules = append(w.schedules, schedule{jobType: jobType, interval: interval})
+}
+
+// Start the worker goroutines and scheduler
+func (w *Worker) Start() {
+
+	// log
+	log := w.Logger
+
+	log.Info().Msgf("Starting %d job workers on queue %s", w.Concurrency, w.Queue)
+
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < w.Concurrency; i++ {
+		w.wg.Add(1)
+		go w.work()
+	}
+
+	if len(w.schedules) > 0 {
+		w.wg.Add(1)
+		go w.schedule()
+	}
+}
+
+// Stop signals all goroutines to finish their current job and waits for them
+func (w *Worker) Stop() {
+
+	// log
+	log := w.Logger
+
+	log.Info().Msg("Stopping job workers")
+
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+
+	log.Info().Msg("Job workers stopped")
+}
+
+func (w *Worker) work() {
+	defer w.wg.Done()
+
+	// log
+	log := w.Logger
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		default:
+		}
+
+		found, err := w.RunNext()
+		if err != nil {
+			log.Error().Msgf("Error running job %v", err)
+		}
+		if found {
+			continue
+		}
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+func (w *Worker) schedule() {
+	defer w.wg.Done()
+
+	// log
+	log := w.Logger
+
+	for {
+		for _, s := range w.schedules {
+			err := w.enqueueScheduled(s)
+			if err != nil {
+				log.Error().Msgf("Error enqueueing scheduled job %s %v", s.jobType, err)
+			}
+		}
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+func (w *Worker) enqueueScheduled(s schedule) error {
+
+	slot := time.Now().UTC().Truncate(s.interval)
+
+	tx, err := w.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	_, err = Enqueue(w.Env, w.Logger, tx, s.jobType, nil, &Options{
+		Queue:     w.Queue,
+		RunAt:     slot,
+		UniqueKey: fmt.Sprintf("%s@%d", s.jobType, slot.Unix()),
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RunNext claims and runs the next runnable job on the worker's queue.
+// Returns false when there was no job to run.
+func (w *Worker) RunNext() (bool, error) {
+
+	// log
+	log := w.Logger
+
+	tx, err := w.DB.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	// jobs run on behalf of the system across all tenants
+	err = db.SetTenant(tx, db.AllTenants)
+	if err != nil {
+		return false, util.RollbackTxWithError(err, "Error setting tenant", tx)
+	}
+
+	m, err := job.NewModel(w.Env, w.Logger, tx)
+	if err != nil {
+		return false, util.RollbackTxWithError(err, "Error creating job model", tx)
+	}
+
+	rec, err := m.LockNext(w.Queue)
+	if err == sql.ErrNoRows {
+		return false, tx.Rollback()
+	}
+	if err != nil {
+		return false, util.RollbackTxWithError(err, "Error locking next job", tx)
+	}
+
+	log.Debug().Msgf("Running job %s type %s attempt %d", rec.ID, rec.Type, rec.Attempts+1)
+
+	rec.Attempts++
+
+	herr := w.runHandler(tx, rec)
+
+	switch {
+	case herr == nil:
+		rec.Status = job.StatusCompleted
+		rec.CompletedAt = util.ToNullString(util.GetTime())
+		rec.LastError = sql.NullString{}
+	case rec.Attempts >= rec.MaxAttempts:
+		log.Error().Msgf("Job %s type %s failed permanently after %d attempts %v", rec.ID, rec.Type, rec.Attempts, herr)
+		rec.Status = job.StatusDead
+		rec.LastError = util.ToNullString(herr.Error())
+	default:
+		retry := Backoff(rec.Attempts)
+		log.Warn().Msgf("Job %s type %s failed, retrying in %s %v", rec.ID, rec.Type, retry, herr)
+		rec.RunAt = util.GetFutureTime(retry)
+		rec.LastError = util.ToNullString(herr.Error())
+	}
+
+	err = m.Update(rec)
+	if err != nil {
+		return true, util.RollbackTxWithError(err, "Error updating job", tx)
+	}
+
+	return true, tx.Commit()
+}
+
+// runHandler runs the job's handler within a savepoint so a failing handler
+// only discards its own work and the job's retry state can still be saved.
+func (w *Worker) runHandler(tx *sqlx.Tx, rec *job.Record) (err error) {
+
+	h := w.Registry.Get(rec.Type)
+	if h == nil {
+		return fmt.Errorf("No handler registered for job type %s", rec.Type)
+	}
+
+	_, err = tx.Exec("SAVEPOINT job_handler")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("Job handler panic: %v", p)
+		}
+		if err != nil {
+			_, rerr := tx.Exec("ROLLBACK TO SAVEPOINT job_handler")
+			if rerr != nil {
+				w.Logger.Error().Msgf("Error rolling back job savepoint %v", rerr)
+			}
+			return
+		}
+		_, err = tx.Exec("RELEASE SAVEPOINT job_handler")
+	}()
+
+	ctx := w.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return h(ctx, tx, rec)
+}
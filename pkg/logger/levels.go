@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Level names accepted by APP_LOG_LEVEL, APP_LOG_LEVELS and the log levels
+// endpoint
+var levelNames = map[string]zerolog.Level{
+	"debug": zerolog.DebugLevel,
+	"info":  zerolog.InfoLevel,
+	"warn":  zerolog.WarnLevel,
+	"error": zerolog.ErrorLevel,
+}
+
+// Levels - the log level, and the packages logging at a different level
+type Levels struct {
+	Level    string
+	Packages map[string]string
+}
+
+var (
+	levelsMu      sync.RWMutex
+	defaultLevel  = zerolog.ErrorLevel
+	packageLevels = map[string]zerolog.Level{}
+
+	// output - where loggers write, set by NewLogger
+	output io.Writer
+)
+
+// ParseLevel returns the level with a name, debug, info, warn or error
+func ParseLevel(s string) (zerolog.Level, error) {
+	lvl, ok := levelNames[s]
+	if !ok {
+		return zerolog.NoLevel, fmt.Errorf("Invalid log level %q, must be debug, info, warn or error", s)
+	}
+	return lvl, nil
+}
+
+// ParsePackageLevels parses package levels as set by APP_LOG_LEVELS,
+// db=debug
+func ParsePackageLevels(items []string) (map[string]string, error) {
+
+	pkgs := map[string]string{}
+
+	for _, item := range items {
+		i := strings.Index(item, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("Invalid package log level %q, must be package=level", item)
+		}
+		name, lvl := strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		if _, err := ParseLevel(lvl); err != nil {
+			return nil, err
+		}
+		pkgs[name] = lvl
+	}
+
+	return pkgs, nil
+}
+
+// SetLevels replaces the log level and every package level. Nothing is
+// changed when a level is invalid.
+func SetLevels(lv *Levels) error {
+
+	def, err := ParseLevel(lv.Level)
+	if err != nil {
+		return err
+	}
+
+	pkgs := map[string]zerolog.Level{}
+	for name, s := range lv.Packages {
+		if name == "" {
+			return fmt.Errorf("Invalid package log level, package is required")
+		}
+		pkgs[name], err = ParseLevel(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	defaultLevel = def
+	packageLevels = pkgs
+
+	// events below the global level are dropped before they are built,
+	// levelWriter drops the rest for packages logging less
+	min := def
+	for _, lvl := range pkgs {
+		if lvl < min {
+			min = lvl
+		}
+	}
+	zerolog.SetGlobalLevel(min)
+
+	return nil
+}
+
+// GetLevels returns the current log level and package levels
+func GetLevels() *Levels {
+
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+
+	lv := Levels{Level: levelName(defaultLevel), Packages: map[string]string{}}
+	for name, lvl := range packageLevels {
+		lv.Packages[name] = levelName(lvl)
+	}
+
+	return &lv
+}
+
+// String formats levels as they are logged, info db=debug
+func (lv *Levels) String() string {
+	s := []string{lv.Level}
+	var pkgs []string
+	for name, lvl := range lv.Packages {
+		pkgs = append(pkgs, name+"="+lvl)
+	}
+	sort.Strings(pkgs)
+	return strings.Join(append(s, pkgs...), " ")
+}
+
+func levelName(lvl zerolog.Level) string {
+	for name, l := range levelNames {
+		if l == lvl {
+			return name
+		}
+	}
+	return lvl.String()
+}
+
+// levelFor returns the level a package logs at, the default level when
+// it has none of its own
+func levelFor(pkg string) zerolog.Level {
+
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+
+	if lvl, ok := packageLevels[pkg]; ok {
+		return lvl
+	}
+
+	return defaultLevel
+}
+
+// Package returns a logger for a package, db or jobs, whose level may be
+// set apart from the rest with APP_LOG_LEVELS or the log levels endpoint
+func Package(l zerolog.Logger, name string) zerolog.Logger {
+
+	levelsMu.RLock()
+	w := output
+	levelsMu.RUnlock()
+
+	// loggers not made by NewLogger, such as in tests, are left as they are
+	if w != nil {
+		l = l.Output(&levelWriter{out: w, pkg: name})
+	}
+
+	return l.With().Str("package", name).Logger()
+}
+
+// levelWriter drops events below the level of the package that logged
+// them
+type levelWriter struct {
+	out io.Writer
+	pkg string
+}
+
+// Write -
+func (w *levelWriter) Write(p []byte) (int, error) {
+	return w.out.Write(p)
+}
+
+// WriteLevel -
+func (w *levelWriter) WriteLevel(lvl zerolog.Level, p []byte) (int, error) {
+	if lvl < levelFor(w.pkg) {
+		return len(p), nil
+	}
+	return w.out.Write(p)
+}
+
+// setOutput sets where loggers write, wrapped so the default level
+// applies to loggers without a package
+func setOutput(w io.Writer) io.Writer {
+
+	levelsMu.Lock()
+	output = w
+	levelsMu.Unlock()
+
+	return &levelWriter{out: w}
+}
@@ -1,9 +1,13 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/config"
 	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/reload"
 )
 
 // NewLogger returns a logger
@@ -11,31 +15,62 @@ func NewLogger(e *env.Env) zerolog.Logger {
 
 	InitLogger(e)
 
-	l := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	var w io.Writer = os.Stdout
 
-	if e.Get("APP_PRETTY_LOGS") != "" && e.Get("APP_PRETTY_LOGS") != "0" {
-		l = l.Output(zerolog.ConsoleWriter{Out: os.Stdout})
+	if e.Config.PrettyLogs {
+		w = zerolog.ConsoleWriter{Out: os.Stdout}
 	}
 
-	return l
+	return zerolog.New(setOutput(w)).With().Timestamp().Logger()
 }
 
-// InitLogger initializes logger
+// InitLogger initializes logger levels from APP_LOG_LEVEL, error by
+// default, and APP_LOG_LEVELS
 func InitLogger(e *env.Env) {
 
-	// log level
-	logLevel := e.Get("APP_LOG_LEVEL")
+	// levels are validated when the configuration is loaded
+	err := SetLevels(configLevels(e.Config))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
 
-	// Default log level is error
-	zeroLogLevel := zerolog.ErrorLevel
+// configLevels returns the levels configured by c
+func configLevels(c *config.Config) *Levels {
 
-	if logLevel == "debug" {
-		zeroLogLevel = zerolog.DebugLevel
+	lv := Levels{Level: c.LogLevel, Packages: map[string]string{}}
+	if lv.Level == "" {
+		lv.Level = "error"
 	}
 
-	if logLevel == "info" {
-		zeroLogLevel = zerolog.InfoLevel
+	pkgs, err := ParsePackageLevels(c.LogLevels)
+	if err == nil {
+		lv.Packages = pkgs
 	}
 
-	zerolog.SetGlobalLevel(zeroLogLevel)
+	return &lv
+}
+
+// WatchLevels sets the configured levels again on SIGHUP, replacing any
+// set at runtime, until the returned stop function is called
+func WatchLevels(l zerolog.Logger, e *env.Env) (stop func()) {
+
+	return reload.Watch(0, func() {
+
+		c, err := e.Reload()
+		if err != nil {
+			l.Error().Msgf("Log levels not reloaded, invalid configuration: %v", err)
+			return
+		}
+
+		lv := configLevels(c)
+
+		err = SetLevels(lv)
+		if err != nil {
+			l.Error().Msgf("Log levels not reloaded: %v", err)
+			return
+		}
+
+		l.Info().Msgf("Log levels reloaded: %s", lv)
+	})
 }
@@ -1 +1,76 @@
 package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackageLevels(t *testing.T) {
+
+	pkgs, err := ParsePackageLevels([]string{"db=debug", " jobs = info "})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"db": "debug", "jobs": "info"}, pkgs)
+
+	for _, items := range [][]string{{"db"}, {"=debug"}, {"db=loud"}} {
+		_, err := ParsePackageLevels(items)
+		assert.Error(t, err, "%v", items)
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+
+	defer SetLevels(&Levels{Level: "error"})
+
+	err := SetLevels(&Levels{Level: "warn", Packages: map[string]string{"db": "debug"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &Levels{Level: "warn", Packages: map[string]string{"db": "debug"}}, GetLevels())
+	assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+	assert.Equal(t, "warn db=debug", GetLevels().String())
+
+	// invalid levels change nothing
+	assert.Error(t, SetLevels(&Levels{Level: "loud"}))
+	assert.Error(t, SetLevels(&Levels{Level: "info", Packages: map[string]string{"db": "loud"}}))
+	assert.Equal(t, "warn", GetLevels().Level)
+}
+
+func TestPackage(t *testing.T) {
+
+	defer SetLevels(&Levels{Level: "error"})
+	defer setOutput(nil)
+
+	buf := bytes.Buffer{}
+	l := zerolog.New(setOutput(&buf))
+
+	err := SetLevels(&Levels{Level: "warn", Packages: map[string]string{"db": "debug"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dl := Package(l, "db")
+	jl := Package(l, "jobs")
+
+	dl.Debug().Msg("db debug")
+	jl.Debug().Msg("jobs debug")
+	jl.Warn().Msg("jobs warn")
+	l.Info().Msg("default info")
+	l.Error().Msg("default error")
+
+	out := buf.String()
+	assert.Contains(t, out, `"package":"db","message":"db debug"`)
+	assert.NotContains(t, out, "jobs debug")
+	assert.Contains(t, out, `"package":"jobs","message":"jobs warn"`)
+	assert.NotContains(t, out, "default info")
+	assert.Contains(t, out, "default error")
+
+	// levels apply to existing loggers as they change
+	buf.Reset()
+	assert.NoError(t, SetLevels(&Levels{Level: "warn"}))
+	dl.Debug().Msg("db debug")
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/handler"
+	"github.com/vegh1010/test/pkg/model/apiclient"
+	"github.com/vegh1010/test/pkg/principalcontext"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// HeaderAPIKey - alternative to an Authorization bearer token
+const HeaderAPIKey = "X-API-Key"
+
+// cacheTTL - how long an authenticated key is trusted before it is looked
+// up again, bounds how long a deactivated key keeps working
+const cacheTTL = time.Minute
+
+type cacheEntry struct {
+	principal *principalcontext.Principal
+	expires   time.Time
+}
+
+// cache is shared by all routes
+var cache = struct {
+	sync.Mutex
+	entries map[string]cacheEntry
+}{entries: map[string]cacheEntry{}}
+
+// auth -
+type auth struct {
+	Env     *env.Env
+	Logger  zerolog.Logger
+	DB      *sqlx.DB
+	Handler handler.Handler
+}
+
+// NewAuth -
+func NewAuth(e *env.Env, l zerolog.Logger, db *sqlx.DB, hr handler.Handler, h http.Handler) http.Handler {
+
+	a := &auth{
+		Env:     e,
+		Logger:  l,
+		DB:      db,
+		Handler: hr,
+	}
+
+	mw := a.Middleware(h)
+
+	return mw
+}
+
+// Middleware - authenticates the request's API key and sets the principal
+// in the request context. Handlers flagged as unauthenticated are skipped.
+func (t auth) Middleware(h http.Handler) http.Handler {
+
+	log := t.Logger
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if t.Handler.GetUnauthenticated() {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		key := APIKey(r)
+		if key == "" {
+			t.sendError(w, resperror.ErrorUnauthenticated, http.StatusUnauthorized)
+			return
+		}
+
+		p, err := t.authenticate(key)
+		if err == sql.ErrNoRows {
+			log.Warn().Msgf("Invalid API key for path %s", r.RequestURI)
+			t.sendError(w, resperror.ErrorUnauthenticated, http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Error().Msgf("Could not authenticate API key %v", err)
+			t.sendError(w, resperror.SystemErr("Internal application error"), http.StatusInternalServerError)
+			return
+		}
+
+		r = principalcontext.SetContext(r, p)
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// APIKey returns the API key from a request's Authorization bearer token
+// or X-API-Key header.
+func APIKey(r *http.Request) string {
+	if a := r.Header.Get("Authorization"); strings.HasPrefix(a, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(a, "Bearer "))
+	}
+	return strings.TrimSpace(r.Header.Get(HeaderAPIKey))
+}
+
+func (t auth) authenticate(key string) (*principalcontext.Principal, error) {
+
+	hash := apiclient.HashKey(key)
+
+	cache.Lock()
+	ce, ok := cache.entries[hash]
+	cache.Unlock()
+
+	if ok && time.Now().Before(ce.expires) {
+		return ce.principal, nil
+	}
+
+	tx, err := t.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	m, err := apiclient.NewModel(t.Env, t.Logger, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := m.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &principalcontext.Principal{
+		ID:        rec.ID,
+		TenantID:  rec.TenantID,
+		Name:      rec.Name,
+		Role:      rec.Role,
+		RateLimit: rec.RateLimit.String,
+	}
+
+	cache.Lock()
+	cache.entries[hash] = cacheEntry{principal: p, expires: time.Now().Add(cacheTTL)}
+	cache.Unlock()
+
+	return p, nil
+}
+
+func (t auth) sendError(w http.ResponseWriter, rerr *resperror.Data, code int) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(&resperror.Response{Error: rerr})
+}
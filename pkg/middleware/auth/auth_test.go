@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/merchants", nil)
+	assert.Equal(t, "", APIKey(r))
+
+	r.Header.Set(HeaderAPIKey, "abc")
+	assert.Equal(t, "abc", APIKey(r))
+
+	r.Header.Set("Authorization", "Bearer def")
+	assert.Equal(t, "def", APIKey(r))
+
+	r.Header.Set("Authorization", "Basic xyz")
+	assert.Equal(t, "abc", APIKey(r))
+}
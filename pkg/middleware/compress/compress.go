@@ -0,0 +1,278 @@
+// Package compress compresses responses with gzip or deflate when the
+// client accepts it and the response is large enough to benefit.
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+)
+
+// Content encodings
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+)
+
+// DefaultMinSize - responses smaller than this are not compressed, the
+// saving would not cover the overhead
+const DefaultMinSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// Negotiate returns the preferred supported encoding from an
+// Accept-Encoding header, or an empty string when none is acceptable.
+// Gzip is preferred over deflate at equal quality.
+func Negotiate(accept string) string {
+
+	best := ""
+	bestQ := 0.0
+
+	for _, part := range strings.Split(accept, ",") {
+
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				v, err := strconv.ParseFloat(strings.TrimPrefix(f, "q="), 64)
+				if err != nil {
+					v = 0
+				}
+				q = v
+			}
+		}
+
+		if coding == "*" {
+			coding = EncodingGzip
+		}
+		if coding != EncodingGzip && coding != EncodingDeflate {
+			continue
+		}
+
+		if q > bestQ || (q == bestQ && q > 0 && coding == EncodingGzip) {
+			best = coding
+			bestQ = q
+		}
+	}
+
+	return best
+}
+
+// compress -
+type compress struct {
+	Env     *env.Env
+	Logger  zerolog.Logger
+	MinSize int
+}
+
+// NewCompress wraps h to compress responses, configured by
+// APP_COMPRESSION_MIN_SIZE. Set it to 0 to disable compression.
+func NewCompress(e *env.Env, l zerolog.Logger, h http.Handler) (http.Handler, error) {
+
+	n := e.Config.Compression.MinSize
+	if n == 0 {
+		return h, nil
+	}
+
+	a := &compress{
+		Env:     e,
+		Logger:  l,
+		MinSize: n,
+	}
+
+	mw := a.Middleware(h)
+
+	return mw, nil
+}
+
+// Middleware -
+func (t compress) Middleware(h http.Handler) http.Handler {
+
+	log := t.Logger
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := Negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &responseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			minSize:        t.MinSize,
+		}
+		defer func() {
+			err := cw.Close()
+			if err != nil {
+				log.Error().Msgf("Could not complete compressed response %v", err)
+			}
+		}()
+
+		h.ServeHTTP(cw, r)
+	})
+}
+
+// responseWriter buffers the start of a response until it is known to be
+// at least minSize, then compresses the rest. Smaller responses are sent
+// unchanged when the handler completes.
+type responseWriter struct {
+	http.ResponseWriter
+	encoding   string
+	minSize    int
+	status     int
+	buf        bytes.Buffer
+	compressor io.WriteCloser
+	// decided - whether headers have been written, compressing or not
+	decided bool
+}
+
+// WriteHeader is deferred until compression is decided
+func (cw *responseWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+// Write -
+func (cw *responseWriter) Write(p []byte) (int, error) {
+
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	if cw.decided {
+		if cw.compressor != nil {
+			return cw.compressor.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+
+	cw.buf.Write(p)
+
+	if cw.buf.Len() >= cw.minSize {
+		err := cw.decide(true)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush sends buffered data. A response flushed before reaching minSize
+// is streaming and is compressed.
+func (cw *responseWriter) Flush() {
+
+	if !cw.decided {
+		err := cw.decide(cw.buf.Len() > 0)
+		if err != nil {
+			return
+		}
+	}
+
+	if f, ok := cw.compressor.(interface {
+		Flush() error
+	}); ok {
+		f.Flush()
+	}
+
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decide writes headers and any buffered data, compressing when asked and
+// the response has not already been encoded or has no body
+func (cw *responseWriter) decide(compress bool) error {
+
+	cw.decided = true
+
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	hdr := cw.ResponseWriter.Header()
+
+	if hdr.Get("Content-Encoding") != "" || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified {
+		compress = false
+	}
+
+	if compress {
+		hdr.Set("Content-Encoding", cw.encoding)
+		hdr.Del("Content-Length")
+
+		switch cw.encoding {
+		case EncodingGzip:
+			gz := gzipWriters.Get().(*gzip.Writer)
+			gz.Reset(cw.ResponseWriter)
+			cw.compressor = gz
+		case EncodingDeflate:
+			fl, err := flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+			if err != nil {
+				return err
+			}
+			cw.compressor = fl
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if cw.buf.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if cw.compressor != nil {
+		_, err = cw.compressor.Write(cw.buf.Bytes())
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf.Bytes())
+	}
+	cw.buf.Reset()
+
+	return err
+}
+
+// Close sends a response that never reached minSize uncompressed, or
+// completes the compressed response
+func (cw *responseWriter) Close() error {
+
+	if !cw.decided {
+		if cw.status == 0 {
+			// nothing was written
+			return nil
+		}
+		return cw.decide(false)
+	}
+
+	if cw.compressor == nil {
+		return nil
+	}
+
+	err := cw.compressor.Close()
+
+	if gz, ok := cw.compressor.(*gzip.Writer); ok {
+		gzipWriters.Put(gz)
+	}
+	cw.compressor = nil
+
+	return err
+}
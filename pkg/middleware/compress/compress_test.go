@@ -0,0 +1,114 @@
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]string{
+		"":                         "",
+		"identity":                 "",
+		"gzip":                     EncodingGzip,
+		"deflate":                  EncodingDeflate,
+		"deflate, gzip":            EncodingGzip,
+		"gzip;q=0.5, deflate":      EncodingDeflate,
+		"gzip;q=0, deflate;q=0":    "",
+		"br, GZIP;q=0.8":           EncodingGzip,
+		"*":                        EncodingGzip,
+		"deflate;q=0.9, *;q=0.1":   EncodingDeflate,
+		"gzip;q=bad, deflate;q=.2": EncodingDeflate,
+	}
+
+	for accept, expect := range tests {
+		assert.Equal(t, expect, Negotiate(accept), accept)
+	}
+}
+
+func serve(accept string, h http.HandlerFunc) *httptest.ResponseRecorder {
+	c := compress{Logger: zerolog.Nop(), MinSize: 100}
+
+	r := httptest.NewRequest("GET", "/api/merchants", nil)
+	if accept != "" {
+		r.Header.Set("Accept-Encoding", accept)
+	}
+
+	w := httptest.NewRecorder()
+	c.Middleware(h).ServeHTTP(w, r)
+
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"name":"Acme"}`, 20)
+
+	// below the minimum size
+	w := serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("small"))
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, "small", w.Body.String())
+
+	// not accepted
+	w = serve("", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(large))
+	})
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, w.Body.String())
+
+	// gzip
+	w = serve("gzip, deflate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(large[:50]))
+		w.Write([]byte(large[50:]))
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, EncodingGzip, w.Header().Get("Content-Encoding"))
+	gz, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	body, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	// deflate
+	w = serve("deflate", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(large))
+	})
+	assert.Equal(t, EncodingDeflate, w.Header().Get("Content-Encoding"))
+	body, err = ioutil.ReadAll(flate.NewReader(w.Body))
+	assert.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	// already encoded
+	w = serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte(large))
+	})
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, w.Body.String())
+
+	// flushed before reaching the minimum size
+	w = serve("gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("stream"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("ing"))
+	})
+	assert.Equal(t, EncodingGzip, w.Header().Get("Content-Encoding"))
+	assert.True(t, w.Flushed)
+	gz, err = gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	body, err = ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, "streaming", string(body))
+}
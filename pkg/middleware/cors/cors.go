@@ -0,0 +1,93 @@
+// Package cors allows browser applications on other origins to call the
+// API, as configured by APP_CORS_* environment variables.
+package cors
+
+import (
+	"net/http"
+
+	"github.com/rs/cors"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/middleware/ratelimit"
+	"github.com/vegh1010/test/pkg/middleware/requestid"
+)
+
+// Defaults when not configured
+var (
+	DefaultAllowedMethods = []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
+	DefaultAllowedHeaders = []string{
+		"Authorization",
+		"Content-Type",
+		"X-API-Key",
+		requestid.Header,
+	}
+	DefaultExposedHeaders = []string{
+		requestid.Header,
+		ratelimit.HeaderLimit,
+		ratelimit.HeaderRemaining,
+		ratelimit.HeaderReset,
+		ratelimit.HeaderRetryAfter,
+	}
+)
+
+// Options returns the CORS options configured by the environment, nil
+// when APP_CORS_ALLOWED_ORIGINS is not set and CORS is disabled.
+//
+// Origins may contain a single * wildcard, for example
+// https://*.example.com or http://localhost:*.
+func Options(e *env.Env) *cors.Options {
+	return options(e.Config.CORS)
+}
+
+// options builds options from the CORS configuration
+func options(c config.CORS) *cors.Options {
+
+	if len(c.AllowedOrigins) == 0 {
+		return nil
+	}
+
+	o := cors.Options{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   DefaultAllowedMethods,
+		AllowedHeaders:   DefaultAllowedHeaders,
+		ExposedHeaders:   DefaultExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+
+	if len(c.AllowedMethods) > 0 {
+		o.AllowedMethods = c.AllowedMethods
+	}
+
+	if len(c.AllowedHeaders) > 0 {
+		o.AllowedHeaders = c.AllowedHeaders
+	}
+
+	if len(c.ExposedHeaders) > 0 {
+		o.ExposedHeaders = c.ExposedHeaders
+	}
+
+	return &o
+}
+
+// NewCORS wraps the router so preflight requests are answered before any
+// route middleware runs, without authentication or a database tx. Returns
+// h unchanged when CORS is disabled.
+func NewCORS(e *env.Env, l zerolog.Logger, h http.Handler) (http.Handler, error) {
+
+	o := Options(e)
+	if o == nil {
+		return h, nil
+	}
+
+	l.Info().Msgf("CORS allowed origins %v", o.AllowedOrigins)
+
+	return cors.New(*o).Handler(h), nil
+}
@@ -0,0 +1,63 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/cors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/config"
+)
+
+func TestOptions(t *testing.T) {
+	o := options(config.CORS{})
+	assert.Nil(t, o)
+
+	o = options(config.CORS{
+		AllowedOrigins: []string{"https://admin.example.com", "https://*.example.org"},
+	})
+	assert.Equal(t, []string{"https://admin.example.com", "https://*.example.org"}, o.AllowedOrigins)
+	assert.Equal(t, DefaultAllowedMethods, o.AllowedMethods)
+	assert.Equal(t, DefaultAllowedHeaders, o.AllowedHeaders)
+	assert.False(t, o.AllowCredentials)
+	assert.Equal(t, 0, o.MaxAge)
+
+	o = options(config.CORS{
+		AllowedOrigins:   []string{"http://localhost:*"},
+		AllowedMethods:   []string{"GET"},
+		AllowedHeaders:   []string{"Authorization"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	})
+	assert.Equal(t, []string{"GET"}, o.AllowedMethods)
+	assert.Equal(t, []string{"Authorization"}, o.AllowedHeaders)
+	assert.Equal(t, []string{"X-Request-ID"}, o.ExposedHeaders)
+	assert.True(t, o.AllowCredentials)
+	assert.Equal(t, 600, o.MaxAge)
+}
+
+func TestPreflight(t *testing.T) {
+	o := options(config.CORS{AllowedOrigins: []string{"https://*.example.com"}})
+
+	called := false
+	h := cors.New(*o).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	r := httptest.NewRequest(http.MethodOptions, "/api/merchants", nil)
+	r.Header.Set("Origin", "https://admin.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.False(t, called)
+	assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	r = httptest.NewRequest(http.MethodGet, "/api/merchants", nil)
+	r.Header.Set("Origin", "https://example.net")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.True(t, called)
+	assert.Equal(t, "", w.Header().Get("Access-Control-Allow-Origin"))
+}
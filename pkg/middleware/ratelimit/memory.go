@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryEntry - a bucket and when it will be full again
+type memoryEntry struct {
+	bucket *bucket
+	full   time.Time
+}
+
+// MemoryStore holds buckets in process, limits are per instance
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryEntry
+	swept   time.Time
+}
+
+// NewMemoryStore -
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*memoryEntry{},
+	}
+}
+
+// Take -
+func (s *MemoryStore) Take(buckets []Bucket, now time.Time) ([]*Result, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	entries := make([]*memoryEntry, len(buckets))
+	allowed := true
+
+	for i, b := range buckets {
+		e, ok := s.buckets[b.Key]
+		if !ok {
+			e = &memoryEntry{bucket: newBucket(b.Limit, now)}
+			s.buckets[b.Key] = e
+		}
+		e.bucket.refill(b.Limit, now)
+		allowed = allowed && e.bucket.Tokens >= 1
+		entries[i] = e
+	}
+
+	rs := make([]*Result, len(buckets))
+
+	for i, b := range buckets {
+		e := entries[i]
+		if allowed {
+			e.bucket.Tokens--
+		}
+		rs[i] = e.bucket.result(b.Limit, allowed)
+		e.full = now.Add(rs[i].Reset)
+	}
+
+	return rs, nil
+}
+
+// sweep drops buckets that have refilled, they are recreated full
+func (s *MemoryStore) sweep(now time.Time) {
+
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	s.swept = now
+
+	for k, e := range s.buckets {
+		if now.After(e.full) {
+			delete(s.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// takeSQL - rate_limit_take locks the buckets, creating any that do not
+// exist full, and takes a token from each when all have one
+var takeSQL = `
+SELECT bucket_tokens, bucket_allowed
+FROM rate_limit_take($1, $2, $3, $4)
+`
+
+var sweepBucketsSQL = `
+DELETE FROM rate_limit_bucket
+WHERE full_at < $1
+`
+
+// PostgresStore holds buckets in the rate_limit_bucket table so limits
+// are shared by every instance. A request's buckets are taken from in one
+// statement, which locks their rows so concurrent takes are serialised.
+type PostgresStore struct {
+	DB    *sqlx.DB
+	mu    sync.Mutex
+	swept time.Time
+}
+
+// NewPostgresStore -
+func NewPostgresStore(db *sqlx.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+// Take -
+func (s *PostgresStore) Take(buckets []Bucket, now time.Time) ([]*Result, error) {
+
+	err := s.sweep(now)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(buckets))
+	capacities := make([]float64, len(buckets))
+	rates := make([]float64, len(buckets))
+
+	for i, b := range buckets {
+		keys[i] = b.Key
+		capacities[i] = float64(b.Limit.Requests)
+		rates[i] = b.Limit.rate()
+	}
+
+	rows, err := s.DB.Query(takeSQL, pq.Array(keys), pq.Array(capacities), pq.Array(rates), now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rs []*Result
+
+	for i := 0; rows.Next(); i++ {
+		b := bucket{UpdatedAt: now}
+		var allowed bool
+
+		err = rows.Scan(&b.Tokens, &allowed)
+		if err != nil {
+			return nil, err
+		}
+
+		rs = append(rs, b.result(buckets[i].Limit, allowed))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(rs) != len(buckets) {
+		return nil, fmt.Errorf("rate_limit_take returned %d buckets for %d", len(rs), len(buckets))
+	}
+
+	return rs, nil
+}
+
+// sweep deletes buckets that have refilled, at most once per interval for
+// each instance
+func (s *PostgresStore) sweep(now time.Time) error {
+
+	s.mu.Lock()
+	if now.Sub(s.swept) < sweepInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.swept = now
+	s.mu.Unlock()
+
+	_, err := s.DB.Exec(sweepBucketsSQL, now)
+
+	return err
+}
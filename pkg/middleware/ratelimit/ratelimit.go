@@ -0,0 +1,393 @@
+// Package ratelimit limits how often each API client may call the API.
+//
+// Every client has a token bucket across all routes and another for each
+// route. A request takes a token from both, once either is empty requests
+// are rejected with 429 until tokens are refilled, and take from neither.
+// API clients may have a limit of their own and routes may have their own
+// limits. Buckets are held in memory for single instances, or in Postgres
+// so replicas share them.
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/principalcontext"
+	"github.com/vegh1010/test/pkg/resperror"
+)
+
+// Rate limit stores
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+// Rate limit response headers
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// DefaultPeriod - period of a limit given as a plain number of requests
+const DefaultPeriod = time.Minute
+
+// sweepInterval - how often stores drop buckets that have refilled
+const sweepInterval = time.Minute
+
+// Limit - requests allowed per period, the bucket size is the number of
+// requests so a whole period's requests may arrive in a burst
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses a limit of requests per minute, "600", or requests per
+// duration, "10/1s". Empty or zero is no limit and returns nil.
+func ParseLimit(s string) (*Limit, error) {
+
+	if s == "" {
+		return nil, nil
+	}
+
+	l := Limit{Period: DefaultPeriod}
+
+	parts := strings.SplitN(s, "/", 2)
+
+	n, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	l.Requests = int(n)
+
+	if len(parts) == 2 {
+		l.Period, err = time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		if l.Period <= 0 {
+			return nil, fmt.Errorf("period must be positive")
+		}
+	}
+
+	if l.Requests == 0 {
+		return nil, nil
+	}
+
+	return &l, nil
+}
+
+// rate - tokens added per second
+func (l *Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result - the outcome of taking a token from a bucket
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset - until the bucket is full again
+	Reset time.Duration
+	// RetryAfter - until a token is available when not allowed
+	RetryAfter time.Duration
+}
+
+// Bucket - a bucket's key and limit
+type Bucket struct {
+	Key   string
+	Limit *Limit
+}
+
+// Store holds token buckets
+type Store interface {
+	// Take takes a token from every bucket when each has one, and from
+	// none otherwise, returning a result for each bucket
+	Take(buckets []Bucket, now time.Time) ([]*Result, error)
+}
+
+// bucket - tokens remaining as at UpdatedAt
+type bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// newBucket returns a full bucket
+func newBucket(l *Limit, now time.Time) *bucket {
+	return &bucket{Tokens: float64(l.Requests), UpdatedAt: now}
+}
+
+// refill adds the tokens accrued since the bucket was last updated
+func (b *bucket) refill(l *Limit, now time.Time) {
+
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	b.Tokens = math.Min(float64(l.Requests), b.Tokens+elapsed*l.rate())
+	b.UpdatedAt = now
+}
+
+// result returns the result of a take, after any token has been taken
+func (b *bucket) result(l *Limit, allowed bool) *Result {
+
+	res := Result{Allowed: allowed, Limit: l.Requests, Remaining: int(b.Tokens)}
+
+	if !allowed && b.Tokens < 1 {
+		res.RetryAfter = seconds((1 - b.Tokens) / l.rate())
+	}
+
+	res.Reset = seconds((float64(l.Requests) - b.Tokens) / l.rate())
+
+	return &res
+}
+
+// take refills the bucket and takes a token when there is one
+func (b *bucket) take(l *Limit, now time.Time) *Result {
+
+	b.refill(l, now)
+
+	allowed := b.Tokens >= 1
+	if allowed {
+		b.Tokens--
+	}
+
+	return b.result(l, allowed)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Limiter - the configured limits and store, shared by all routes
+type Limiter struct {
+	Store Store
+	// Client - limit for each client across all routes, unless the API
+	// client has a limit of its own
+	Client *Limit
+	// Route - limit for each client on each route
+	Route *Limit
+	// Routes - limits for each client on particular routes, in place of
+	// Route, by method and path template
+	Routes map[string]*Limit
+}
+
+// NewLimiter returns the limiter configured by APP_RATE_LIMIT,
+// APP_RATE_LIMIT_ROUTE, APP_RATE_LIMIT_ROUTES and APP_RATE_LIMIT_STORE
+func NewLimiter(e *env.Env, db *sqlx.DB) (*Limiter, error) {
+
+	var err error
+
+	c := e.Config.RateLimit
+
+	lim := Limiter{Routes: map[string]*Limit{}}
+
+	lim.Client, err = ParseLimit(c.Client)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid APP_RATE_LIMIT %s: %v", c.Client, err)
+	}
+
+	lim.Route, err = ParseLimit(c.Route)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid APP_RATE_LIMIT_ROUTE %s: %v", c.Route, err)
+	}
+
+	// GET /api/merchants/search=60
+	for _, r := range c.Routes {
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 || len(strings.Fields(parts[0])) != 2 {
+			return nil, fmt.Errorf("Invalid APP_RATE_LIMIT_ROUTES %s: must be METHOD /path=limit", r)
+		}
+		route := strings.Join(strings.Fields(parts[0]), " ")
+		lim.Routes[route], err = ParseLimit(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid APP_RATE_LIMIT_ROUTES %s: %v", r, err)
+		}
+	}
+
+	switch c.Store {
+	case "", StoreMemory:
+		lim.Store = NewMemoryStore()
+	case StorePostgres:
+		lim.Store = NewPostgresStore(db)
+	default:
+		return nil, fmt.Errorf("Invalid APP_RATE_LIMIT_STORE %s", c.Store)
+	}
+
+	return &lim, nil
+}
+
+// ClientLimit returns the limit across all routes for a caller, the API
+// client's own limit when it has one. p is nil for unauthenticated routes.
+func (lim *Limiter) ClientLimit(p *principalcontext.Principal) (*Limit, error) {
+	if p == nil || p.RateLimit == "" {
+		return lim.Client, nil
+	}
+	return ParseLimit(p.RateLimit)
+}
+
+// RouteLimit returns the limit for each client on a route
+func (lim *Limiter) RouteLimit(route string) *Limit {
+	if l, ok := lim.Routes[route]; ok {
+		return l
+	}
+	return lim.Route
+}
+
+// Take takes a token from each of the client's buckets for a route when
+// both have one, returning the most restrictive result, or nil when
+// neither is limited. A request rejected by one bucket takes nothing from
+// the other.
+func (lim *Limiter) Take(client, route string, clientLimit *Limit, now time.Time) (*Result, error) {
+
+	var buckets []Bucket
+
+	if l := lim.RouteLimit(route); l != nil {
+		buckets = append(buckets, Bucket{Key: client + " " + route, Limit: l})
+	}
+	if clientLimit != nil {
+		buckets = append(buckets, Bucket{Key: client, Limit: clientLimit})
+	}
+
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	rs, err := lim.Store.Take(buckets, now)
+	if err != nil {
+		return nil, err
+	}
+
+	res := rs[0]
+	for _, r := range rs[1:] {
+		if restricts(r, res) {
+			res = r
+		}
+	}
+
+	return res, nil
+}
+
+// restricts returns whether a is more restrictive than b
+func restricts(a, b *Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+// rateLimit -
+type rateLimit struct {
+	Env     *env.Env
+	Logger  zerolog.Logger
+	Limiter *Limiter
+}
+
+// NewRateLimit -
+func NewRateLimit(e *env.Env, l zerolog.Logger, lim *Limiter, h http.Handler) http.Handler {
+
+	a := &rateLimit{
+		Env:     e,
+		Logger:  l,
+		Limiter: lim,
+	}
+
+	mw := a.Middleware(h)
+
+	return mw
+}
+
+// Middleware - rejects requests from clients that have exceeded their
+// limits. Requests are allowed when the store fails so an unavailable
+// store does not take down the API.
+func (t rateLimit) Middleware(h http.Handler) http.Handler {
+
+	log := t.Logger
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		p, _ := principalcontext.GetContext(r)
+
+		cl, err := t.Limiter.ClientLimit(p)
+		if err != nil {
+			log.Error().Msgf("Invalid rate limit for API client %s, using APP_RATE_LIMIT %v", p.ID, err)
+			cl = t.Limiter.Client
+		}
+
+		res, err := t.Limiter.Take(client(r), route(r), cl, time.Now().UTC())
+		if err != nil {
+			log.Error().Msgf("Could not take rate limit token %v", err)
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if res == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set(HeaderLimit, strconv.Itoa(res.Limit))
+		w.Header().Set(HeaderRemaining, strconv.Itoa(res.Remaining))
+		w.Header().Set(HeaderReset, strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			log.Warn().Msgf("Rate limit exceeded for %s on %s", client(r), route(r))
+			w.Header().Set(HeaderRetryAfter, strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(&resperror.Response{Error: resperror.ErrorRateLimited})
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// client identifies the caller, the authenticated API client or the
+// remote address for unauthenticated routes
+func client(r *http.Request) string {
+
+	if p, err := principalcontext.GetContext(r); err == nil {
+		return "client:" + p.ID
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "addr:" + host
+}
+
+// route identifies the route by method and path template so each
+// merchant's URL shares the route's bucket
+func route(r *http.Request) string {
+
+	path := r.URL.Path
+	if cr := mux.CurrentRoute(r); cr != nil {
+		if t, err := cr.GetPathTemplate(); err == nil {
+			path = t
+		}
+	}
+
+	return r.Method + " " + path
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/principalcontext"
+)
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("")
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	l, err = ParseLimit("0")
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	l, err = ParseLimit("600")
+	assert.NoError(t, err)
+	assert.Equal(t, &Limit{Requests: 600, Period: time.Minute}, l)
+
+	l, err = ParseLimit("10/1s")
+	assert.NoError(t, err)
+	assert.Equal(t, &Limit{Requests: 10, Period: time.Second}, l)
+
+	for _, s := range []string{"-1", "ten", "10/", "10/0s", "10/-1s"} {
+		_, err = ParseLimit(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBucketTake(t *testing.T) {
+	l := &Limit{Requests: 2, Period: 2 * time.Second}
+	now := time.Now()
+
+	b := newBucket(l, now)
+
+	res := b.take(l, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+	assert.Equal(t, time.Second, res.Reset)
+
+	res = b.take(l, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 2*time.Second, res.Reset)
+
+	res = b.take(l, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	// half a token refilled
+	res = b.take(l, now.Add(500*time.Millisecond))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	res = b.take(l, now.Add(time.Second))
+	assert.True(t, res.Allowed)
+
+	// never refills beyond the limit
+	res = b.take(l, now.Add(time.Hour))
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+}
+
+func TestLimiterTake(t *testing.T) {
+	lim := &Limiter{
+		Store:  NewMemoryStore(),
+		Client: &Limit{Requests: 3, Period: time.Minute},
+		Route:  &Limit{Requests: 2, Period: time.Minute},
+	}
+	now := time.Now()
+
+	res, err := lim.Take("a", "GET /api/merchants", lim.Client, now)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+	assert.Equal(t, 2, res.Limit)
+
+	res, _ = lim.Take("a", "GET /api/merchants", lim.Client, now)
+	assert.True(t, res.Allowed)
+
+	// route bucket empty
+	res, _ = lim.Take("a", "GET /api/merchants", lim.Client, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 30*time.Second, res.RetryAfter)
+
+	// client bucket has one token left for other routes
+	res, _ = lim.Take("a", "GET /api/organisations", lim.Client, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 3, res.Limit)
+
+	res, _ = lim.Take("a", "GET /api/locations", lim.Client, now)
+	assert.False(t, res.Allowed)
+
+	// other clients are unaffected
+	res, _ = lim.Take("b", "GET /api/merchants", lim.Client, now)
+	assert.True(t, res.Allowed)
+}
+
+func TestLimiterTakeAll(t *testing.T) {
+	lim := &Limiter{
+		Store:  NewMemoryStore(),
+		Client: &Limit{Requests: 1, Period: time.Minute},
+		Route:  &Limit{Requests: 2, Period: time.Minute},
+		Routes: map[string]*Limit{"GET /api/search": {Requests: 1, Period: time.Minute}},
+	}
+	now := time.Now()
+
+	res, _ := lim.Take("a", "GET /api/merchants", lim.Client, now)
+	assert.True(t, res.Allowed)
+
+	// client bucket empty, the route bucket keeps its token
+	res, _ = lim.Take("a", "GET /api/merchants", lim.Client, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 1, res.Limit)
+
+	// without a client limit the route bucket still has a token
+	res, _ = lim.Take("a", "GET /api/merchants", nil, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 2, res.Limit)
+
+	// routes with a limit of their own
+	res, _ = lim.Take("b", "GET /api/search", nil, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Limit)
+
+	res, _ = lim.Take("b", "GET /api/search", nil, now)
+	assert.False(t, res.Allowed)
+
+	// no limits
+	lim = &Limiter{Store: NewMemoryStore()}
+	res, err := lim.Take("a", "GET /api/merchants", nil, now)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+}
+
+func TestClientLimit(t *testing.T) {
+	lim := &Limiter{Client: &Limit{Requests: 600, Period: time.Minute}}
+
+	l, err := lim.ClientLimit(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, lim.Client, l)
+
+	l, err = lim.ClientLimit(&principalcontext.Principal{ID: "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, lim.Client, l)
+
+	l, err = lim.ClientLimit(&principalcontext.Principal{ID: "a", RateLimit: "6000"})
+	assert.NoError(t, err)
+	assert.Equal(t, &Limit{Requests: 6000, Period: time.Minute}, l)
+
+	_, err = lim.ClientLimit(&principalcontext.Principal{ID: "a", RateLimit: "lots"})
+	assert.Error(t, err)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s := NewMemoryStore()
+	l := &Limit{Requests: 1, Period: time.Second}
+	now := time.Now()
+
+	s.Take([]Bucket{{Key: "a", Limit: l}}, now)
+	assert.Len(t, s.buckets, 1)
+
+	s.Take([]Bucket{{Key: "b", Limit: l}}, now.Add(sweepInterval))
+	assert.Len(t, s.buckets, 1)
+	assert.Contains(t, s.buckets, "b")
+}
+
+func TestMiddleware(t *testing.T) {
+	lim := &Limiter{
+		Store:  NewMemoryStore(),
+		Client: &Limit{Requests: 1, Period: time.Minute},
+	}
+
+	h := NewRateLimit(nil, zerolog.Nop(), lim, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	r := httptest.NewRequest("GET", "/api/merchants", nil)
+	r = principalcontext.SetContext(r, &principalcontext.Principal{ID: "client"})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(HeaderLimit))
+	assert.Equal(t, "0", w.Header().Get(HeaderRemaining))
+	assert.Equal(t, "60", w.Header().Get(HeaderReset))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get(HeaderRetryAfter))
+	assert.Contains(t, w.Body.String(), `"code":5`)
+}
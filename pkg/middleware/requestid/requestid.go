@@ -0,0 +1,50 @@
+package requestid
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/requestidcontext"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Header -
+const Header = "X-Request-ID"
+
+// validID - accept caller provided IDs that are safe to log
+var validID = regexp.MustCompile(`^[A-Za-z0-9\-_.]{1,128}$`)
+
+// requestID -
+type requestID struct {
+	Logger zerolog.Logger
+}
+
+// NewRequestID -
+func NewRequestID(l zerolog.Logger, h http.Handler) http.Handler {
+
+	a := &requestID{
+		Logger: l,
+	}
+
+	mw := a.Middleware(h)
+
+	return mw
+}
+
+// Middleware - uses the caller's request ID when provided, otherwise
+// generates one, and echoes it in the response.
+func (t requestID) Middleware(h http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !validID.MatchString(id) {
+			id = util.GetUUID()
+		}
+
+		w.Header().Set(Header, id)
+		r = requestidcontext.SetContext(r, id)
+
+		h.ServeHTTP(w, r)
+	})
+}
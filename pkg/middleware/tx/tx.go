@@ -1,9 +1,13 @@
 package tx
 
 import (
+	"context"
+	"database/sql"
 	"net/http"
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/principalcontext"
 	"github.com/vegh1010/test/pkg/txcontext"
 	"github.com/vegh1010/test/pkg/env"
 )
@@ -35,7 +39,17 @@ func (t tx) Middleware(h http.Handler) http.Handler {
 	log := t.Logger
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tx, err := t.DB.Beginx()
+		// GET and HEAD requests only read, their tx is read-only and runs
+		// on the read replica when there is one. The replica may be up to
+		// APP_DATABASE_REPLICA_MAX_LAG behind, so a read straight after a
+		// write may not see it.
+		var tx *sqlx.Tx
+		var err error
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			tx, err = t.DB.BeginTxx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		} else {
+			tx, err = t.DB.Beginx()
+		}
 
 		if err != nil {
 			log.Error().Msgf("Could not Beginx in tx for %v", err)
@@ -43,6 +57,20 @@ func (t tx) Middleware(h http.Handler) http.Handler {
 			w.Write([]byte("500 - Internal error"))
 			return
 		}
+
+		// scope the tx to the principal's tenant, without a
+		// principal no tenant rows are visible
+		if p, err := principalcontext.GetContext(r); err == nil && p.TenantID != "" {
+			err = db.SetTenant(tx, p.TenantID)
+			if err != nil {
+				tx.Rollback()
+				log.Error().Msgf("Could not set tenant in tx for %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte("500 - Internal error"))
+				return
+			}
+		}
+
 		r = txcontext.SetContext(r, tx)
 
 		h.ServeHTTP(w, r)
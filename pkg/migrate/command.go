@@ -0,0 +1,246 @@
+package migrate
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+)
+
+// Default paths, relative to APP_HOME
+const (
+	DefaultDir      = "database/migrations"
+	DefaultSnapshot = "database/schema.json"
+)
+
+// Usage - the migrate command's arguments
+const Usage = `usage: migrate [flags] up|down|status|redo|to N|seed|new NAME|snapshot|diff
+
+  up        apply all pending migrations and upsert seed data
+  down      roll back the last applied migration
+  status    list migrations and seeds and whether they are applied
+  redo      roll back and reapply the last applied migration
+  to N      apply or roll back migrations until N is the last applied, 0
+            rolls back every migration
+  seed      upsert seed data, every migration must be applied
+  new NAME  create the next numbered migration in --dir
+  snapshot  write the live schema to the --file snapshot
+  diff      compare the live schema with the --file snapshot
+
+  --dry-run    print the SQL that would run without running it
+  --dir DIR    migrations directory, default ` + DefaultDir + `
+  --file FILE  schema snapshot, default ` + DefaultSnapshot + `
+`
+
+// options - flags, which may appear anywhere in the arguments
+type options struct {
+	dryRun bool
+	dir    string
+	file   string
+}
+
+// parseArgs splits the command from its flags
+func parseArgs(args []string) ([]string, *options, error) {
+
+	var cmd []string
+	o := options{}
+
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") {
+			cmd = append(cmd, a)
+			continue
+		}
+
+		name := strings.TrimLeft(a, "-")
+		value := ""
+		hasValue := false
+		if n := strings.Index(name, "="); n >= 0 {
+			name, value, hasValue = name[:n], name[n+1:], true
+		}
+
+		switch name {
+		case "dry-run":
+			o.dryRun = true
+			continue
+		case "dir", "file":
+		default:
+			return nil, nil, fmt.Errorf("Unknown flag %s\n%s", a, Usage)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("Flag %s needs a value\n%s", a, Usage)
+			}
+			i++
+			value = args[i]
+		}
+
+		if name == "dir" {
+			o.dir = value
+		} else {
+			o.file = value
+		}
+	}
+
+	if len(cmd) == 0 {
+		return nil, nil, fmt.Errorf("%s", Usage)
+	}
+
+	return cmd, &o, nil
+}
+
+// Main runs test-api migrate with the arguments following migrate, such
+// as []string{"to", "12", "--dry-run"}
+func Main(e *env.Env, l zerolog.Logger, args []string) error {
+
+	cmd, o, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if o.dir == "" {
+		o.dir = filepath.Join(e.Get("APP_HOME"), DefaultDir)
+	}
+	if o.file == "" {
+		o.file = filepath.Join(e.Get("APP_HOME"), DefaultSnapshot)
+	}
+
+	// new only writes a file so does not need the database
+	if cmd[0] == "new" {
+		if len(cmd) < 2 {
+			return fmt.Errorf("%s", Usage)
+		}
+		path, err := New(o.dir, strings.Join(cmd[1:], " "))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s\n", path)
+		return nil
+	}
+
+	mdb := db.NewOwnerDB(l, e)
+	defer mdb.Close()
+
+	r := NewRunner(l, mdb)
+	r.DryRun = o.dryRun
+
+	return r.command(cmd, o)
+}
+
+// command runs a command that uses the database
+func (r *Runner) command(cmd []string, o *options) error {
+
+	switch {
+	case cmd[0] == "up" && len(cmd) == 1:
+		return r.Up()
+	case cmd[0] == "down" && len(cmd) == 1:
+		return r.Down()
+	case cmd[0] == "redo" && len(cmd) == 1:
+		return r.Redo()
+	case cmd[0] == "seed" && len(cmd) == 1:
+		return r.Seed()
+	case cmd[0] == "status" && len(cmd) == 1:
+		return r.printStatus()
+	case cmd[0] == "snapshot" && len(cmd) == 1:
+		return r.snapshot(o.file)
+	case cmd[0] == "diff" && len(cmd) == 1:
+		return r.diff(o.file)
+	case cmd[0] == "to" && len(cmd) == 2:
+		v, err := strconv.Atoi(cmd[1])
+		if err != nil {
+			return fmt.Errorf("Invalid migration version %s", cmd[1])
+		}
+		return r.To(v)
+	}
+
+	return fmt.Errorf("%s", Usage)
+}
+
+// printStatus writes the status of each migration to Out
+func (r *Runner) printStatus() error {
+
+	ss, err := r.Status()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(r.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+
+	for _, s := range ss {
+		at := ""
+		if s.AppliedAt != nil {
+			at = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, at)
+	}
+
+	sds, err := r.SeedStatus()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(tw, "\nSEED\t\tSTATE\tSEEDED AT")
+
+	for _, s := range sds {
+		at := ""
+		if s.SeededAt != nil {
+			at = s.SeededAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%s\t\t%s\t%s\n", s.Name, s.State, at)
+	}
+
+	return tw.Flush()
+}
+
+// snapshot writes the live schema to path
+func (r *Runner) snapshot(path string) error {
+
+	s, err := r.LiveSchema()
+	if err != nil {
+		return err
+	}
+
+	err = WriteSchema(path, s)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(r.Out, "Wrote schema at version %d to %s\n", s.Version, path)
+
+	return nil
+}
+
+// diff writes the differences between the snapshot at path and the live
+// schema to Out, returning an error when there are any so drift fails
+// scripts
+func (r *Runner) diff(path string) error {
+
+	expected, err := ReadSchema(path)
+	if err != nil {
+		return err
+	}
+
+	live, err := r.LiveSchema()
+	if err != nil {
+		return err
+	}
+
+	diffs := DiffSchema(expected, live)
+	if len(diffs) == 0 {
+		fmt.Fprintf(r.Out, "Schema matches %s\n", path)
+		return nil
+	}
+
+	for _, d := range diffs {
+		fmt.Fprintln(r.Out, d)
+	}
+
+	return fmt.Errorf("Schema differs from %s in %d places", path, len(diffs))
+}
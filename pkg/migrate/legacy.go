@@ -0,0 +1,181 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Migration tools used before this runner
+const (
+	// LegacyGoPG - go-pg/migrations, versioned like this runner, with the
+	// gopg_migrations table and every object in the schema named by
+	// APP_DATABASE_NAME
+	LegacyGoPG = "go-pg"
+	// LegacyMattes - mattes/migrate, with the schema_migrations table and
+	// timestamp versions
+	LegacyMattes = "mattes"
+)
+
+// mattesVersions - the migration each mattes/migrate version left the
+// schema at, its migrations were split up when ported to go-pg
+var mattesVersions = map[int64]int{
+	1512091947: 3, // create_country_table
+	1512091949: 6, // create_timezone_table
+	1512091979: 8, // create_merchant_table
+}
+
+var legacyTableSQL = `SELECT to_regclass($1) IS NOT NULL`
+
+// legacySchemaSQL - schemas holding a legacy table that are not searched
+var legacySchemaSQL = `
+SELECT n.nspname
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relname = $1
+AND n.nspname <> ALL (current_schemas(false))
+ORDER BY n.nspname
+`
+
+var goPGVersionSQL = `SELECT COALESCE(max(version), 0) FROM gopg_migrations`
+
+var mattesVersionSQL = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+// legacyVersion returns the migration a database migrated by go-pg or
+// mattes was left at and which of them migrated it, 0 when neither did
+func legacyVersion(ctx context.Context, q queryer) (int, string, error) {
+
+	found, err := legacyTable(ctx, q, "gopg_migrations")
+	if err != nil {
+		return 0, "", err
+	}
+
+	if found {
+		var version int
+		err = q.QueryRowContext(ctx, goPGVersionSQL).Scan(&version)
+		if err != nil {
+			return 0, "", err
+		}
+
+		return version, LegacyGoPG, nil
+	}
+
+	found, err = legacyTable(ctx, q, "schema_migrations")
+	if err != nil || !found {
+		return 0, "", err
+	}
+
+	var version int64
+	var dirty bool
+	err = q.QueryRowContext(ctx, mattesVersionSQL).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, LegacyMattes, nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	if dirty {
+		return 0, "", fmt.Errorf("mattes/migrate version %d is dirty, repair the schema and clear schema_migrations.dirty before migrating", version)
+	}
+
+	v, ok := mattesVersions[version]
+	if !ok {
+		return 0, "", fmt.Errorf("mattes/migrate version %d is not known", version)
+	}
+
+	return v, LegacyMattes, nil
+}
+
+// legacyTable returns whether a legacy history table is on the search
+// path. go-pg created its table and every object in a schema named after
+// the database, which has to be searched for this runner to see them.
+func legacyTable(ctx context.Context, q queryer, name string) (bool, error) {
+
+	found := false
+	err := q.QueryRowContext(ctx, legacyTableSQL, name).Scan(&found)
+	if err != nil || found {
+		return found, err
+	}
+
+	rows, err := q.QueryContext(ctx, legacySchemaSQL, name)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var s string
+		err = rows.Scan(&s)
+		if err != nil {
+			return false, err
+		}
+		schemas = append(schemas, s)
+	}
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	if len(schemas) > 0 {
+		return false, fmt.Errorf("Found %s in schema %s, set APP_DATABASE_SCHEMA to the schema the database was migrated in", name, strings.Join(schemas, ", "))
+	}
+
+	return false, nil
+}
+
+// baseline records the migrations a go-pg or mattes database has already
+// run as applied, without running them, when nothing is recorded yet
+func (r *Runner) baseline(ctx context.Context, conn *sql.Conn, applied map[int]*Applied) (map[int]*Applied, error) {
+
+	log := r.Logger
+
+	if len(applied) > 0 {
+		return applied, nil
+	}
+
+	version, tool, err := legacyVersion(ctx, conn)
+	if err != nil || version == 0 {
+		return applied, err
+	}
+
+	if version > r.Latest() {
+		return nil, fmt.Errorf("%s migrated the database to version %d, newer than this build's %d", tool, version, r.Latest())
+	}
+
+	if r.DryRun {
+		fmt.Fprintf(r.Out, "-- baseline %s migrations 1 to %d\n\n", tool, version)
+		for _, m := range r.Migrations {
+			if m.Version <= version {
+				applied[m.Version] = &Applied{Version: m.Version, Name: m.Name, Checksum: m.Checksum()}
+			}
+		}
+		return applied, nil
+	}
+
+	log.Info().Msgf("Recording migrations 1 to %d run by %s as applied", version, tool)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range r.Migrations {
+		if m.Version > version {
+			break
+		}
+		_, err = tx.ExecContext(ctx, insertHistorySQL, m.Version, m.Name, m.Checksum())
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.history(conn)
+}
@@ -0,0 +1,112 @@
+// Package migrate runs the versioned schema migrations registered by
+// database/migrations.
+//
+// Applied migrations are recorded in the schema_migration table with a
+// checksum of their SQL, so a migration edited after it was applied is
+// reported rather than silently skipped. Runs hold a Postgres advisory lock
+// so replicas starting together do not race, and each migration is applied
+// in its own tx.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Migration - a versioned schema change and how to reverse it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Amended - checksums of earlier SQL for the migration, fixed after it
+	// was released without changing the schema it leaves
+	Amended []string
+}
+
+// Checksum identifies the migration's SQL, it changes when either
+// direction is edited
+func (m *Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up + "\x00" + m.Down))
+	return hex.EncodeToString(sum[:])
+}
+
+// applied returns whether a recorded checksum is of this migration's SQL
+// or of SQL it was amended from
+func (m *Migration) applied(checksum string) bool {
+	if checksum == m.Checksum() {
+		return true
+	}
+	for _, a := range m.Amended {
+		if checksum == a {
+			return true
+		}
+	}
+	return false
+}
+
+// String -
+func (m *Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+var (
+	mu         sync.Mutex
+	migrations = map[int]*Migration{}
+)
+
+// Register a migration, called from the init of each migration in
+// database/migrations. Versions must be unique.
+func Register(version int, name, up, down string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if version <= 0 {
+		panic(fmt.Sprintf("Migration %s has invalid version %d", name, version))
+	}
+	if m, ok := migrations[version]; ok {
+		panic(fmt.Sprintf("Migration %s has the same version as %s", name, m))
+	}
+
+	migrations[version] = &Migration{
+		Version: version,
+		Name:    name,
+		Up:      up,
+		Down:    down,
+	}
+}
+
+// Migrations returns all registered migrations by version
+func Migrations() []*Migration {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var ms []*Migration
+	for _, m := range migrations {
+		ms = append(ms, m)
+	}
+
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].Version < ms[j].Version
+	})
+
+	return ms
+}
+
+// Amend accepts a registered migration's earlier checksum, for migrations
+// fixed after they were released. Databases that applied the earlier SQL
+// are not reported as modified, so the fix must leave the same schema.
+func Amend(version int, checksum string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	m, ok := migrations[version]
+	if !ok {
+		panic(fmt.Sprintf("Migration version %d amended before it is registered", version))
+	}
+
+	m.Amended = append(m.Amended, checksum)
+}
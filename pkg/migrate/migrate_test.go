@@ -0,0 +1,510 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMigrations() []*Migration {
+	return []*Migration{
+		{Version: 1, Name: "Create_A", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"},
+		{Version: 2, Name: "Create_B", Up: "CREATE TABLE b ();", Down: "DROP TABLE b;"},
+		{Version: 3, Name: "Create_C", Up: "CREATE TABLE c ();", Down: "DROP TABLE c;"},
+	}
+}
+
+func appliedFor(ms ...*Migration) map[int]*Applied {
+	applied := map[int]*Applied{}
+	for _, m := range ms {
+		applied[m.Version] = &Applied{Version: m.Version, Name: m.Name, Checksum: m.Checksum(), AppliedAt: time.Now()}
+	}
+	return applied
+}
+
+func versions(steps []step) []int {
+	var vs []int
+	for _, s := range steps {
+		v := s.migration.Version
+		if s.down {
+			v = -v
+		}
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+func TestChecksum(t *testing.T) {
+	m := &Migration{Version: 1, Name: "Create_A", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"}
+	sum := m.Checksum()
+	assert.Len(t, sum, 64)
+
+	m.Down = "DROP TABLE IF EXISTS a;"
+	assert.NotEqual(t, sum, m.Checksum())
+}
+
+func TestRegister(t *testing.T) {
+	Register(100001, "Test_Register", "SELECT 1;", "SELECT 1;")
+	defer delete(migrations, 100001)
+
+	assert.Panics(t, func() {
+		Register(100001, "Test_Register_Again", "SELECT 1;", "SELECT 1;")
+	})
+	assert.Panics(t, func() {
+		Register(0, "Test_Register_Zero", "SELECT 1;", "SELECT 1;")
+	})
+
+	ms := Migrations()
+	for i := 1; i < len(ms); i++ {
+		assert.True(t, ms[i-1].Version < ms[i].Version)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	ms := testMigrations()
+
+	steps, err := plan(ms, appliedFor(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, versions(steps))
+
+	steps, err = plan(ms, appliedFor(ms[0]), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, versions(steps))
+
+	steps, err = plan(ms, appliedFor(ms...), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-3, -2}, versions(steps))
+
+	steps, err = plan(ms, appliedFor(ms...), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-3, -2, -1}, versions(steps))
+
+	steps, err = plan(ms, appliedFor(ms...), 3)
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
+
+	_, err = plan(ms, appliedFor(), 4)
+	assert.Error(t, err)
+}
+
+func TestStatus(t *testing.T) {
+	ms := testMigrations()
+
+	applied := appliedFor(ms[0], ms[1])
+	applied[1].Checksum = "edited"
+	applied[9] = &Applied{Version: 9, Name: "Removed", Checksum: "x"}
+
+	ss := status(ms, applied)
+	var states []string
+	for _, s := range ss {
+		states = append(states, s.State)
+	}
+	assert.Equal(t, []string{StateModified, StateApplied, StatePending, StateMissing}, states)
+	assert.Nil(t, ss[2].AppliedAt)
+
+	assert.NoError(t, verify(ms, appliedFor(ms[0])))
+	assert.Error(t, verify(ms, map[int]*Applied{1: applied[1]}))
+	assert.Error(t, verify(ms, map[int]*Applied{9: applied[9]}))
+
+	// a fix accepts the checksum it was amended from
+	ms[0].Amended = []string{"edited"}
+	assert.Equal(t, StateApplied, status(ms, applied)[0].State)
+	assert.NoError(t, verify(ms, map[int]*Applied{1: applied[1]}))
+}
+
+func TestAmend(t *testing.T) {
+	Register(100002, "Test_Amend", "SELECT 1;", "SELECT 1;")
+	defer delete(migrations, 100002)
+
+	Amend(100002, "earlier")
+	assert.Equal(t, []string{"earlier"}, migrations[100002].Amended)
+
+	assert.Panics(t, func() {
+		Amend(100003, "earlier")
+	})
+}
+
+func TestParseArgs(t *testing.T) {
+	cmd, o, err := parseArgs([]string{"to", "12", "--dry-run"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"to", "12"}, cmd)
+	assert.Equal(t, &options{dryRun: true}, o)
+
+	cmd, o, err = parseArgs([]string{"--file", "schema.json", "diff", "-dir=migrations"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"diff"}, cmd)
+	assert.Equal(t, &options{file: "schema.json", dir: "migrations"}, o)
+
+	for _, args := range [][]string{
+		{},
+		{"--dry-run"},
+		{"up", "--force"},
+		{"diff", "--file"},
+	} {
+		_, _, err = parseArgs(args)
+		assert.Error(t, err, "%v", args)
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	r := &Runner{Migrations: testMigrations()}
+
+	for _, cmd := range [][]string{
+		{"sideways"},
+		{"up", "2"},
+		{"to"},
+		{"to", "two"},
+	} {
+		assert.Error(t, r.command(cmd, &options{}), "%v", cmd)
+	}
+}
+
+func TestNew(t *testing.T) {
+	name, err := MigrationName("create widget")
+	assert.NoError(t, err)
+	assert.Equal(t, "Create_Widget", name)
+
+	name, err = MigrationName("Alter_Merchant-add notes")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alter_Merchant_Add_Notes", name)
+
+	_, err = MigrationName("drop; table")
+	assert.Error(t, err)
+
+	dir, err := ioutil.TempDir("", "migrations")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "9_Create_Job.go"), nil, 0644)
+	ioutil.WriteFile(filepath.Join(dir, "10_Create_Outbox.go"), nil, 0644)
+	ioutil.WriteFile(filepath.Join(dir, "migrations.go"), nil, 0644)
+
+	path, err := New(dir, "create widget")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "11_Create_Widget.go"), path)
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Contains(t, string(b), `migrate.Register(11, "Create_Widget", upQuery, downQuery)`)
+
+	path, err = New(dir, "create gadget")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "12_Create_Gadget.go"), path)
+}
+
+func TestDiffSchema(t *testing.T) {
+	def := "now()"
+	expected := &Schema{
+		Version: 42,
+		Tables: []*Table{
+			{
+				Name: "merchant",
+				Columns: []*Column{
+					{Name: "id", Type: "uuid"},
+					{Name: "name", Type: "text"},
+					{Name: "created_at", Type: "timestamp without time zone", Default: &def},
+				},
+				Constraints: []*Constraint{
+					{Name: "merchant_pk", Type: "PRIMARY KEY"},
+					{Name: "merchant_country_fk", Type: "FOREIGN KEY"},
+				},
+				Indexes: []*Index{
+					{Name: "merchant_country_idx", Definition: "CREATE INDEX merchant_country_idx ON merchant USING btree (country_id)"},
+					{Name: "merchant_name_idx", Definition: "CREATE INDEX merchant_name_idx ON merchant USING btree (name)"},
+				},
+			},
+			{Name: "job"},
+		},
+	}
+
+	assert.Empty(t, DiffSchema(expected, expected))
+
+	live := &Schema{
+		Version: 41,
+		Tables: []*Table{
+			{
+				Name: "merchant",
+				Columns: []*Column{
+					{Name: "id", Type: "uuid"},
+					{Name: "name", Type: "character varying(50)", Nullable: true},
+					{Name: "created_at", Type: "timestamp without time zone"},
+					{Name: "notes", Type: "text", Nullable: true},
+				},
+				Constraints: []*Constraint{
+					{Name: "merchant_pk", Type: "PRIMARY KEY"},
+				},
+				Indexes: []*Index{
+					{Name: "merchant_name_idx", Definition: "CREATE UNIQUE INDEX merchant_name_idx ON merchant USING btree (name)"},
+					{Name: "merchant_notes_idx", Definition: "CREATE INDEX merchant_notes_idx ON merchant USING btree (notes)"},
+				},
+			},
+			{Name: "widget"},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"~ version 41, expected 42",
+		"- table job",
+		"~ column merchant.created_at default NULL, expected now()",
+		"~ column merchant.name type character varying(50), expected text",
+		"~ column merchant.name nullable true, expected false",
+		"+ column merchant.notes text",
+		"- constraint merchant.merchant_country_fk FOREIGN KEY",
+		"- index merchant.merchant_country_idx",
+		"~ index merchant.merchant_name_idx CREATE UNIQUE INDEX merchant_name_idx ON merchant USING btree (name), expected CREATE INDEX merchant_name_idx ON merchant USING btree (name)",
+		"+ index merchant.merchant_notes_idx CREATE INDEX merchant_notes_idx ON merchant USING btree (notes)",
+		"+ table widget",
+	}, DiffSchema(expected, live))
+}
+
+func TestColumnType(t *testing.T) {
+	n := 3
+	assert.Equal(t, "character varying(3)", columnType("character varying", "varchar", &n))
+	assert.Equal(t, "e_merchant_status", columnType("USER-DEFINED", "e_merchant_status", nil))
+	assert.Equal(t, "text[]", columnType("ARRAY", "_text", nil))
+	assert.Equal(t, "uuid", columnType("uuid", "uuid", nil))
+}
+
+func TestSeeds(t *testing.T) {
+	RegisterSeed(100002, "test_b", "SELECT 1;")
+	RegisterSeed(100001, "test_a", "SELECT 1;")
+	defer delete(seeds, "test_a")
+	defer delete(seeds, "test_b")
+
+	assert.Panics(t, func() {
+		RegisterSeed(100003, "test_a", "SELECT 1;")
+	})
+
+	ss := Seeds()
+	assert.Equal(t, "test_a", ss[len(ss)-2].Name)
+	assert.Equal(t, "test_b", ss[len(ss)-1].Name)
+}
+
+func TestCheck(t *testing.T) {
+	ms := testMigrations()
+
+	ss := []*Seed{{Order: 1, Name: "a", SQL: "SELECT 1;"}, {Order: 2, Name: "b", SQL: "SELECT 2;"}}
+	seeded := map[string]*Seeded{
+		"a": {Name: "a", Checksum: ss[0].Checksum(), SeededAt: time.Now()},
+		"b": {Name: "b", Checksum: ss[1].Checksum(), SeededAt: time.Now()},
+	}
+
+	assert.NoError(t, check(status(ms, appliedFor(ms...)), seedStatus(ss, seeded)))
+
+	applied := appliedFor(ms[0], ms[1])
+	applied[2].Checksum = "edited"
+
+	err := check(status(ms, applied), seedStatus(ss, seeded))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "modified: 2_Create_B")
+		assert.Contains(t, err.Error(), "pending: 3_Create_C")
+		assert.Contains(t, err.Error(), "APP_MIGRATE_ON_START=apply")
+	}
+
+	// seeds not seeded or edited since
+	seeded["b"].Checksum = "edited"
+	delete(seeded, "a")
+
+	err = check(status(ms, appliedFor(ms...)), seedStatus(ss, seeded))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "seeds pending: a")
+		assert.Contains(t, err.Error(), "seeds stale: b")
+	}
+}
+
+func TestUpLegacy(t *testing.T) {
+	ms := testMigrations()
+
+	// migrated to 2 by go-pg
+	fdb := &fakeDB{tables: map[string]bool{"gopg_migrations": true}, goPGVersion: 2}
+	r := fakeRunner(fdb, ms)
+
+	assert.NoError(t, r.Up())
+	assert.Equal(t, []string{"CREATE TABLE c ();"}, fdb.execs)
+	assert.Equal(t, ms[1].Checksum(), fdb.history[1].Checksum)
+	assert.Len(t, fdb.history, 3)
+
+	// adopted once
+	assert.NoError(t, r.Up())
+	assert.Len(t, fdb.execs, 1)
+	assert.Len(t, fdb.history, 3)
+
+	// migrated by mattes
+	fdb = &fakeDB{tables: map[string]bool{"schema_migrations": true}, mattesVersion: 1512091947}
+	r = fakeRunner(fdb, ms)
+	assert.NoError(t, r.Up())
+	assert.Empty(t, fdb.execs)
+	assert.Len(t, fdb.history, 3)
+
+	fdb = &fakeDB{tables: map[string]bool{"schema_migrations": true}, mattesVersion: 1512091947, mattesDirty: true}
+	assert.Error(t, fakeRunner(fdb, ms).Up())
+	assert.Empty(t, fdb.history)
+
+	// go-pg objects are in a schema named after the database
+	fdb = &fakeDB{tables: map[string]bool{}, goPGSchema: "test"}
+	err := fakeRunner(fdb, ms).Up()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "APP_DATABASE_SCHEMA")
+	}
+	assert.Empty(t, fdb.execs)
+
+	// a new database
+	fdb = &fakeDB{tables: map[string]bool{}}
+	assert.NoError(t, fakeRunner(fdb, ms).Up())
+	assert.Len(t, fdb.execs, 3)
+}
+
+// fakeDB - a database as left by the legacy migration tools, answering
+// the runner's queries
+type fakeDB struct {
+	mu            sync.Mutex
+	tables        map[string]bool
+	goPGVersion   int
+	goPGSchema    string
+	mattesVersion int64
+	mattesDirty   bool
+	history       []Applied
+	execs         []string
+}
+
+var fakeDBs = struct {
+	sync.Mutex
+	dbs map[string]*fakeDB
+}{dbs: map[string]*fakeDB{}}
+
+func init() {
+	sql.Register("migrate-fake", fakeDriver{})
+}
+
+func fakeRunner(fdb *fakeDB, ms []*Migration) *Runner {
+	fakeDBs.Lock()
+	name := fmt.Sprintf("fake-%d", len(fakeDBs.dbs))
+	fakeDBs.dbs[name] = fdb
+	fakeDBs.Unlock()
+
+	d := sqlx.MustOpen("migrate-fake", name)
+	return &Runner{DB: d, Logger: zerolog.Nop(), Migrations: ms, Out: ioutil.Discard}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBs.Lock()
+	defer fakeDBs.Unlock()
+	return &fakeConn{db: fakeDBs.dbs[name]}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	return nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	d := c.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT pg_advisory"), query == createSeedHistorySQL:
+	case query == createHistorySQL:
+		d.tables["schema_migration"] = true
+	case query == insertHistorySQL:
+		d.history = append(d.history, Applied{
+			Version:  int(args[0].Value.(int64)),
+			Name:     args[1].Value.(string),
+			Checksum: args[2].Value.(string),
+		})
+	default:
+		d.execs = append(d.execs, query)
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	d := c.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch query {
+	case legacyTableSQL:
+		return &fakeRows{cols: []string{"exists"}, rows: [][]driver.Value{{d.tables[args[0].Value.(string)]}}}, nil
+	case legacySchemaSQL:
+		rows := &fakeRows{cols: []string{"nspname"}}
+		if args[0].Value.(string) == "gopg_migrations" && d.goPGSchema != "" {
+			rows.rows = [][]driver.Value{{d.goPGSchema}}
+		}
+		return rows, nil
+	case goPGVersionSQL:
+		return &fakeRows{cols: []string{"version"}, rows: [][]driver.Value{{int64(d.goPGVersion)}}}, nil
+	case mattesVersionSQL:
+		return &fakeRows{cols: []string{"version", "dirty"}, rows: [][]driver.Value{{d.mattesVersion, d.mattesDirty}}}, nil
+	case seedHistoryExistsSQL:
+		return &fakeRows{cols: []string{"exists"}, rows: [][]driver.Value{{false}}}, nil
+	case historyExistsSQL:
+		return &fakeRows{cols: []string{"exists"}, rows: [][]driver.Value{{d.tables["schema_migration"]}}}, nil
+	case historySQL:
+		rows := &fakeRows{cols: []string{"version", "name", "checksum", "applied_at"}}
+		for _, a := range d.history {
+			rows.rows = append(rows.rows, []driver.Value{int64(a.Version), a.Name, a.Checksum, time.Now()})
+		}
+		return rows, nil
+	}
+
+	return nil, fmt.Errorf("unexpected query %s", query)
+}
+
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.cols
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,108 @@
+package migrate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_.+\.go$`)
+
+var migrationNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+var migrationTemplate = template.Must(template.New("migration").Parse(`package migrations
+
+import (
+	"github.com/vegh1010/test/pkg/migrate"
+)
+
+func init() {
+	upQuery := ` + "``" + `
+
+	downQuery := ` + "``" + `
+
+	migrate.Register({{.Version}}, "{{.Name}}", upQuery, downQuery)
+}
+`))
+
+// MigrationName converts a description such as "create widget" or
+// "alter-merchant add notes" to a migration name, Create_Widget
+func MigrationName(s string) (string, error) {
+
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	})
+
+	for i, p := range parts {
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+
+	name := strings.Join(parts, "_")
+	if !migrationNameRe.MatchString(name) {
+		return "", fmt.Errorf("Invalid migration name %q, use letters, digits and underscores", s)
+	}
+
+	return name, nil
+}
+
+// NextVersion returns the version after the last migration file in dir
+func NextVersion(dir string) (int, error) {
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	last := 0
+	for _, f := range files {
+		m := migrationFileRe.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+		v, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, err
+		}
+		if v > last {
+			last = v
+		}
+	}
+
+	return last + 1, nil
+}
+
+// New writes an empty migration numbered after the last one in dir,
+// returning its path
+func New(dir, description string) (string, error) {
+
+	name, err := MigrationName(description)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := NextVersion(dir)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s.go", version, name))
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	err = migrationTemplate.Execute(f, &Migration{Version: version, Name: name})
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+
+	return path, f.Close()
+}
@@ -0,0 +1,432 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+)
+
+// Migration states reported by Status
+const (
+	StateApplied = "applied"
+	StatePending = "pending"
+	// StateModified - applied, but the migration has been edited since
+	StateModified = "modified"
+	// StateMissing - applied, but the migration is no longer registered
+	StateMissing = "missing"
+)
+
+// lockKey - advisory lock held while migrating, any constant shared by
+// all instances will do
+const lockKey int64 = 4157102391
+
+var createHistorySQL = `
+CREATE TABLE IF NOT EXISTS schema_migration (
+	version     INTEGER    NOT NULL,
+	name        TEXT       NOT NULL,
+	checksum    TEXT       NOT NULL,
+	applied_at  TIMESTAMP  NOT NULL DEFAULT (now() at time zone 'utc'),
+	CONSTRAINT  schema_migration_pk PRIMARY KEY (version)
+)
+`
+
+var historyExistsSQL = `SELECT to_regclass('schema_migration') IS NOT NULL`
+
+var historySQL = `SELECT version, name, checksum, applied_at FROM schema_migration ORDER BY version`
+
+var insertHistorySQL = `INSERT INTO schema_migration (version, name, checksum) VALUES ($1, $2, $3)`
+
+var deleteHistorySQL = `DELETE FROM schema_migration WHERE version = $1`
+
+// Applied - a schema_migration row
+type Applied struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// Status - the state of a migration
+type Status struct {
+	Version   int
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+// step - a migration to apply or roll back
+type step struct {
+	migration *Migration
+	down      bool
+}
+
+// Runner applies and rolls back migrations
+type Runner struct {
+	DB         *sqlx.DB
+	Logger     zerolog.Logger
+	Migrations []*Migration
+	Seeds      []*Seed
+	// DryRun - write the SQL that would run to Out instead of running it
+	DryRun bool
+	Out    io.Writer
+}
+
+// NewRunner returns a runner for all registered migrations
+func NewRunner(l zerolog.Logger, db *sqlx.DB) *Runner {
+	return &Runner{
+		DB:         db,
+		Logger:     l,
+		Migrations: Migrations(),
+		Seeds:      Seeds(),
+		Out:        os.Stdout,
+	}
+}
+
+// Latest returns the version of the last migration, 0 when there are none
+func (r *Runner) Latest() int {
+	if len(r.Migrations) == 0 {
+		return 0
+	}
+	return r.Migrations[len(r.Migrations)-1].Version
+}
+
+// Status returns the state of every registered or applied migration
+func (r *Runner) Status() ([]*Status, error) {
+
+	applied, err := r.history(r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return status(r.Migrations, applied), nil
+}
+
+// Up applies all pending migrations then upserts seed data
+func (r *Runner) Up() error {
+
+	err := r.To(r.Latest())
+	if err != nil {
+		return err
+	}
+
+	return r.Seed()
+}
+
+// Down rolls back the last applied migration
+func (r *Runner) Down() error {
+	return r.run(func(applied map[int]*Applied) ([]step, error) {
+		m := r.last(applied)
+		if m == nil {
+			return nil, nil
+		}
+		return []step{{migration: m, down: true}}, nil
+	})
+}
+
+// Redo rolls back and reapplies the last applied migration
+func (r *Runner) Redo() error {
+	return r.run(func(applied map[int]*Applied) ([]step, error) {
+		m := r.last(applied)
+		if m == nil {
+			return nil, nil
+		}
+		return []step{{migration: m, down: true}, {migration: m}}, nil
+	})
+}
+
+// To applies or rolls back migrations until version is the last applied
+func (r *Runner) To(version int) error {
+	return r.run(func(applied map[int]*Applied) ([]step, error) {
+		return plan(r.Migrations, applied, version)
+	})
+}
+
+// last returns the last applied migration
+func (r *Runner) last(applied map[int]*Applied) *Migration {
+	for i := len(r.Migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[r.Migrations[i].Version]; ok {
+			return r.Migrations[i]
+		}
+	}
+	return nil
+}
+
+// run holds the migration lock while the steps returned by planFn are
+// applied, each in its own tx
+func (r *Runner) run(planFn func(map[int]*Applied) ([]step, error)) error {
+
+	log := r.Logger
+	ctx := context.Background()
+
+	conn, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.unlock(ctx, conn)
+
+	applied, err := r.history(conn)
+	if err != nil {
+		return err
+	}
+
+	// databases migrated by go-pg or mattes are adopted at the version
+	// they were left at
+	applied, err = r.baseline(ctx, conn, applied)
+	if err != nil {
+		return err
+	}
+
+	err = verify(r.Migrations, applied)
+	if err != nil {
+		return err
+	}
+
+	steps, err := planFn(applied)
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		log.Info().Msg("No migrations to run")
+		return nil
+	}
+
+	for _, s := range steps {
+		err = r.apply(ctx, conn, s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lock returns a connection holding the migration lock, with the history
+// tables created. The advisory lock belongs to a session so everything
+// runs on the one connection. Dry runs change nothing and take no lock.
+func (r *Runner) lock(ctx context.Context) (*sql.Conn, error) {
+
+	log := r.Logger
+
+	conn, err := r.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.DryRun {
+		return conn, nil
+	}
+
+	log.Info().Msg("Acquiring migration lock")
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	for _, query := range []string{createHistorySQL, createSeedHistorySQL} {
+		_, err = conn.ExecContext(ctx, query)
+		if err != nil {
+			r.unlock(ctx, conn)
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// unlock releases the migration lock and the connection
+func (r *Runner) unlock(ctx context.Context, conn *sql.Conn) {
+	if !r.DryRun {
+		_, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockKey)
+		if err != nil {
+			r.Logger.Warn().Msgf("Could not release migration lock %v", err)
+		}
+	}
+	conn.Close()
+}
+
+// apply runs a step and records it in schema_migration in one tx
+func (r *Runner) apply(ctx context.Context, conn *sql.Conn, s step) error {
+
+	log := r.Logger
+	m := s.migration
+
+	direction, query := "up", m.Up
+	if s.down {
+		direction, query = "down", m.Down
+	}
+
+	if r.DryRun {
+		fmt.Fprintf(r.Out, "-- %s %s\n%s\n\n", m, direction, query)
+		return nil
+	}
+
+	log.Info().Msgf("Migrating %s %s", m, direction)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// migrations with nothing to undo have no down SQL
+	if strings.TrimSpace(query) != "" {
+		_, err = tx.ExecContext(ctx, query)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Migration %s %s failed: %v", m, direction, err)
+		}
+	}
+
+	if s.down {
+		_, err = tx.ExecContext(ctx, deleteHistorySQL, m.Version)
+	} else {
+		_, err = tx.ExecContext(ctx, insertHistorySQL, m.Version, m.Name, m.Checksum())
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// queryer - the db or the locked connection
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// history returns applied migrations by version, none when the history
+// table has not been created yet
+func (r *Runner) history(q queryer) (map[int]*Applied, error) {
+
+	ctx := context.Background()
+	applied := map[int]*Applied{}
+
+	exists := false
+	err := q.QueryRowContext(ctx, historyExistsSQL).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := q.QueryContext(ctx, historySQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := Applied{}
+		err = rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied[a.Version] = &a
+	}
+
+	return applied, rows.Err()
+}
+
+// verify checks that applied migrations have not been edited or removed,
+// migrating from a schema that no longer matches its migrations would
+// leave it in an unknown state
+func verify(ms []*Migration, applied map[int]*Applied) error {
+
+	for _, s := range status(ms, applied) {
+		switch s.State {
+		case StateModified:
+			return fmt.Errorf("Migration %d_%s has been edited since it was applied", s.Version, s.Name)
+		case StateMissing:
+			return fmt.Errorf("Migration %d_%s was applied but is not registered", s.Version, s.Name)
+		}
+	}
+
+	return nil
+}
+
+// status compares registered migrations with applied ones
+func status(ms []*Migration, applied map[int]*Applied) []*Status {
+
+	var ss []*Status
+	registered := map[int]bool{}
+
+	for _, m := range ms {
+		registered[m.Version] = true
+
+		s := &Status{Version: m.Version, Name: m.Name, State: StatePending}
+		if a, ok := applied[m.Version]; ok {
+			s.State = StateApplied
+			if !m.applied(a.Checksum) {
+				s.State = StateModified
+			}
+			at := a.AppliedAt
+			s.AppliedAt = &at
+		}
+		ss = append(ss, s)
+	}
+
+	var missing []*Status
+	for v, a := range applied {
+		if registered[v] {
+			continue
+		}
+		at := a.AppliedAt
+		missing = append(missing, &Status{Version: v, Name: a.Name, State: StateMissing, AppliedAt: &at})
+	}
+
+	ss = append(ss, missing...)
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Version < ss[j].Version
+	})
+
+	return ss
+}
+
+// plan returns the steps that leave version as the last applied
+// migration, rolling back newer migrations newest first and applying
+// pending migrations oldest first
+func plan(ms []*Migration, applied map[int]*Applied, version int) ([]step, error) {
+
+	if version < 0 {
+		return nil, fmt.Errorf("Invalid migration version %d", version)
+	}
+
+	known := version == 0
+	for _, m := range ms {
+		if m.Version == version {
+			known = true
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("Migration version %d is not registered", version)
+	}
+
+	var steps []step
+
+	for i := len(ms) - 1; i >= 0; i-- {
+		m := ms[i]
+		if _, ok := applied[m.Version]; ok && m.Version > version {
+			steps = append(steps, step{migration: m, down: true})
+		}
+	}
+
+	for _, m := range ms {
+		if _, ok := applied[m.Version]; !ok && m.Version <= version {
+			steps = append(steps, step{migration: m})
+		}
+	}
+
+	return steps, nil
+}
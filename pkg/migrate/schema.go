@@ -0,0 +1,403 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Schema - tables, columns and constraints read from information_schema
+// and indexes read from pg_indexes, written as a snapshot so drift
+// between environments can be found with migrate diff
+type Schema struct {
+	// Version - last applied migration when the schema was read
+	Version int      `json:"version"`
+	Tables  []*Table `json:"tables"`
+}
+
+// Table -
+type Table struct {
+	Name        string        `json:"name"`
+	Columns     []*Column     `json:"columns"`
+	Constraints []*Constraint `json:"constraints"`
+	Indexes     []*Index      `json:"indexes"`
+}
+
+// Column -
+type Column struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Nullable bool    `json:"nullable"`
+	Default  *string `json:"default"`
+}
+
+// Constraint -
+type Constraint struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Index - an index not created by a primary key or unique constraint,
+// those are compared as constraints
+type Index struct {
+	Name string `json:"name"`
+	// Definition - the CREATE INDEX statement, with the table unqualified
+	// so snapshots compare across schemas
+	Definition string `json:"definition"`
+}
+
+var schemaTablesSQL = `
+SELECT table_name
+FROM information_schema.tables
+WHERE table_schema = current_schema()
+AND table_type = 'BASE TABLE'
+ORDER BY table_name
+`
+
+var schemaColumnsSQL = `
+SELECT table_name, column_name, data_type, udt_name, character_maximum_length, is_nullable = 'YES', column_default
+FROM information_schema.columns
+WHERE table_schema = current_schema()
+ORDER BY table_name, column_name
+`
+
+// schemaConstraintsSQL - NOT NULL is reported as a column's nullability,
+// not by the CHECK constraints Postgres creates for it
+var schemaConstraintsSQL = `
+SELECT table_name, constraint_name, constraint_type
+FROM information_schema.table_constraints
+WHERE table_schema = current_schema()
+AND NOT (constraint_type = 'CHECK' AND constraint_name LIKE '%_not_null')
+ORDER BY table_name, constraint_name
+`
+
+var schemaIndexesSQL = `
+SELECT i.tablename, i.indexname, replace(i.indexdef, ' ON ' || quote_ident(i.schemaname) || '.', ' ON ')
+FROM pg_indexes i
+WHERE i.schemaname = current_schema()
+AND NOT EXISTS (
+	SELECT 1
+	FROM pg_constraint c
+	JOIN pg_namespace n ON n.oid = c.connamespace
+	WHERE n.nspname = i.schemaname
+	AND c.conname = i.indexname
+	AND c.contype IN ('p', 'u', 'x')
+)
+ORDER BY i.tablename, i.indexname
+`
+
+// LiveSchema reads the schema of the database
+func (r *Runner) LiveSchema() (*Schema, error) {
+
+	ctx := context.Background()
+	s := Schema{}
+	tables := map[string]*Table{}
+
+	applied, err := r.history(r.DB)
+	if err != nil {
+		return nil, err
+	}
+	for v := range applied {
+		if v > s.Version {
+			s.Version = v
+		}
+	}
+
+	rows, err := r.DB.QueryContext(ctx, schemaTablesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := Table{}
+		err = rows.Scan(&t.Name)
+		if err != nil {
+			return nil, err
+		}
+		tables[t.Name] = &t
+		s.Tables = append(s.Tables, &t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	crows, err := r.DB.QueryContext(ctx, schemaColumnsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer crows.Close()
+
+	for crows.Next() {
+		var table, dataType, udtName string
+		var length *int
+		c := Column{}
+		err = crows.Scan(&table, &c.Name, &dataType, &udtName, &length, &c.Nullable, &c.Default)
+		if err != nil {
+			return nil, err
+		}
+		c.Type = columnType(dataType, udtName, length)
+
+		// views have columns but are not tables
+		if t, ok := tables[table]; ok {
+			t.Columns = append(t.Columns, &c)
+		}
+	}
+	if err = crows.Err(); err != nil {
+		return nil, err
+	}
+
+	krows, err := r.DB.QueryContext(ctx, schemaConstraintsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer krows.Close()
+
+	for krows.Next() {
+		var table string
+		c := Constraint{}
+		err = krows.Scan(&table, &c.Name, &c.Type)
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := tables[table]; ok {
+			t.Constraints = append(t.Constraints, &c)
+		}
+	}
+	if err = krows.Err(); err != nil {
+		return nil, err
+	}
+
+	irows, err := r.DB.QueryContext(ctx, schemaIndexesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer irows.Close()
+
+	for irows.Next() {
+		var table string
+		i := Index{}
+		err = irows.Scan(&table, &i.Name, &i.Definition)
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := tables[table]; ok {
+			t.Indexes = append(t.Indexes, &i)
+		}
+	}
+
+	return &s, irows.Err()
+}
+
+// columnType - enums and arrays are reported by information_schema as
+// USER-DEFINED and ARRAY, their type is the udt name
+func columnType(dataType, udtName string, length *int) string {
+
+	switch dataType {
+	case "USER-DEFINED":
+		return udtName
+	case "ARRAY":
+		return strings.TrimPrefix(udtName, "_") + "[]"
+	}
+
+	if length != nil {
+		return fmt.Sprintf("%s(%d)", dataType, *length)
+	}
+
+	return dataType
+}
+
+// ReadSchema reads a schema snapshot
+func ReadSchema(path string) (*Schema, error) {
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := Schema{}
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid schema snapshot %s: %v", path, err)
+	}
+
+	return &s, nil
+}
+
+// WriteSchema writes a schema snapshot
+func WriteSchema(path string, s *Schema) error {
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// DiffSchema returns the differences found in live compared with
+// expected, one per line, none when they match. Lines start with - for
+// something missing from live, + for something only in live and ~ for
+// something that differs.
+func DiffSchema(expected, live *Schema) []string {
+
+	var diffs []string
+
+	if expected.Version != live.Version {
+		diffs = append(diffs, fmt.Sprintf("~ version %d, expected %d", live.Version, expected.Version))
+	}
+
+	et := map[string]*Table{}
+	for _, t := range expected.Tables {
+		et[t.Name] = t
+	}
+	lt := map[string]*Table{}
+	for _, t := range live.Tables {
+		lt[t.Name] = t
+	}
+
+	for _, name := range tableNames(et, lt) {
+		e, l := et[name], lt[name]
+		switch {
+		case l == nil:
+			diffs = append(diffs, "- table "+name)
+		case e == nil:
+			diffs = append(diffs, "+ table "+name)
+		default:
+			diffs = append(diffs, diffColumns(e, l)...)
+			diffs = append(diffs, diffConstraints(e, l)...)
+			diffs = append(diffs, diffIndexes(e, l)...)
+		}
+	}
+
+	return diffs
+}
+
+func tableNames(a, b map[string]*Table) []string {
+
+	var names []string
+	for n := range a {
+		names = append(names, n)
+	}
+	for n := range b {
+		if _, ok := a[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func diffColumns(expected, live *Table) []string {
+
+	var diffs []string
+
+	ec := map[string]*Column{}
+	var names []string
+	for _, c := range expected.Columns {
+		ec[c.Name] = c
+		names = append(names, c.Name)
+	}
+	lc := map[string]*Column{}
+	for _, c := range live.Columns {
+		lc[c.Name] = c
+		if _, ok := ec[c.Name]; !ok {
+			names = append(names, c.Name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		e, l := ec[name], lc[name]
+		col := expected.Name + "." + name
+		switch {
+		case l == nil:
+			diffs = append(diffs, "- column "+col)
+		case e == nil:
+			diffs = append(diffs, "+ column "+col+" "+l.Type)
+		default:
+			if e.Type != l.Type {
+				diffs = append(diffs, fmt.Sprintf("~ column %s type %s, expected %s", col, l.Type, e.Type))
+			}
+			if e.Nullable != l.Nullable {
+				diffs = append(diffs, fmt.Sprintf("~ column %s nullable %t, expected %t", col, l.Nullable, e.Nullable))
+			}
+			if str(e.Default) != str(l.Default) {
+				diffs = append(diffs, fmt.Sprintf("~ column %s default %s, expected %s", col, str(l.Default), str(e.Default)))
+			}
+		}
+	}
+
+	return diffs
+}
+
+func diffConstraints(expected, live *Table) []string {
+
+	var diffs []string
+
+	ec := map[string]string{}
+	for _, c := range expected.Constraints {
+		ec[c.Name] = c.Type
+	}
+	lc := map[string]string{}
+	for _, c := range live.Constraints {
+		lc[c.Name] = c.Type
+	}
+
+	for _, c := range expected.Constraints {
+		if _, ok := lc[c.Name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("- constraint %s.%s %s", expected.Name, c.Name, c.Type))
+		}
+	}
+	for _, c := range live.Constraints {
+		t, ok := ec[c.Name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("+ constraint %s.%s %s", expected.Name, c.Name, c.Type))
+		} else if t != c.Type {
+			diffs = append(diffs, fmt.Sprintf("~ constraint %s.%s %s, expected %s", expected.Name, c.Name, c.Type, t))
+		}
+	}
+
+	return diffs
+}
+
+func diffIndexes(expected, live *Table) []string {
+
+	var diffs []string
+
+	ei := map[string]string{}
+	for _, i := range expected.Indexes {
+		ei[i.Name] = i.Definition
+	}
+	li := map[string]string{}
+	for _, i := range live.Indexes {
+		li[i.Name] = i.Definition
+	}
+
+	for _, i := range expected.Indexes {
+		if _, ok := li[i.Name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("- index %s.%s", expected.Name, i.Name))
+		}
+	}
+	for _, i := range live.Indexes {
+		d, ok := ei[i.Name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("+ index %s.%s %s", expected.Name, i.Name, i.Definition))
+		} else if d != i.Definition {
+			diffs = append(diffs, fmt.Sprintf("~ index %s.%s %s, expected %s", expected.Name, i.Name, i.Definition, d))
+		}
+	}
+
+	return diffs
+}
+
+func str(s *string) string {
+	if s == nil {
+		return "NULL"
+	}
+	return *s
+}
@@ -0,0 +1,227 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Seed states reported by SeedStatus, besides StateApplied and
+// StatePending
+const (
+	// StateStale - seeded, but the seed has been edited since
+	StateStale = "stale"
+)
+
+var createSeedHistorySQL = `
+CREATE TABLE IF NOT EXISTS schema_seed (
+	name       TEXT       NOT NULL,
+	checksum   TEXT       NOT NULL,
+	seeded_at  TIMESTAMP  NOT NULL DEFAULT (now() at time zone 'utc'),
+	CONSTRAINT schema_seed_pk PRIMARY KEY (name)
+)
+`
+
+var seedHistoryExistsSQL = `SELECT to_regclass('schema_seed') IS NOT NULL`
+
+var seedHistorySQL = `SELECT name, checksum, seeded_at FROM schema_seed`
+
+var upsertSeedHistorySQL = `
+INSERT INTO schema_seed (name, checksum) VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET
+	checksum = EXCLUDED.checksum,
+	seeded_at = EXCLUDED.seeded_at
+`
+
+// Seed - reference data upserted once every migration is applied. Seeds
+// run on every migrate up and must be safe to repeat.
+type Seed struct {
+	Order int
+	Name  string
+	SQL   string
+}
+
+// Checksum identifies the seed's SQL
+func (s *Seed) Checksum() string {
+	sum := sha256.Sum256([]byte(s.SQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// Seeded - a schema_seed row
+type Seeded struct {
+	Name     string
+	Checksum string
+	SeededAt time.Time
+}
+
+// SeedStatus - the state of a seed
+type SeedStatus struct {
+	Name     string
+	State    string
+	SeededAt *time.Time
+}
+
+var seeds = map[string]*Seed{}
+
+// RegisterSeed registers a seed, called from the init of each seed in
+// database/seeds. Seeds run by order, so data referenced by other seeds
+// should come first.
+func RegisterSeed(order int, name, query string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := seeds[name]; ok {
+		panic(fmt.Sprintf("Seed %s is already registered", name))
+	}
+
+	seeds[name] = &Seed{
+		Order: order,
+		Name:  name,
+		SQL:   query,
+	}
+}
+
+// Seeds returns all registered seeds by order
+func Seeds() []*Seed {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var ss []*Seed
+	for _, s := range seeds {
+		ss = append(ss, s)
+	}
+
+	sort.Slice(ss, func(i, j int) bool {
+		if ss[i].Order != ss[j].Order {
+			return ss[i].Order < ss[j].Order
+		}
+		return ss[i].Name < ss[j].Name
+	})
+
+	return ss
+}
+
+// Seed upserts all seed data in one tx. Seeds are written for the latest
+// schema so every migration must be applied first.
+func (r *Runner) Seed() error {
+
+	log := r.Logger
+	ctx := context.Background()
+
+	conn, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.unlock(ctx, conn)
+
+	if r.DryRun {
+		for _, s := range r.Seeds {
+			fmt.Fprintf(r.Out, "-- seed %s\n%s\n\n", s.Name, s.SQL)
+		}
+		return nil
+	}
+
+	applied, err := r.history(conn)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range status(r.Migrations, applied) {
+		if s.State != StateApplied {
+			return fmt.Errorf("Seeds need every migration applied, %d_%s is %s", s.Version, s.Name, s.State)
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range r.Seeds {
+		log.Info().Msgf("Seeding %s", s.Name)
+
+		_, err = tx.ExecContext(ctx, s.SQL)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Seed %s failed: %v", s.Name, err)
+		}
+
+		_, err = tx.ExecContext(ctx, upsertSeedHistorySQL, s.Name, s.Checksum())
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SeedStatus returns the state of every registered seed
+func (r *Runner) SeedStatus() ([]*SeedStatus, error) {
+
+	seeded, err := r.seedHistory(r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return seedStatus(r.Seeds, seeded), nil
+}
+
+// seedHistory returns seeds by name, none when the history table has not
+// been created yet
+func (r *Runner) seedHistory(q queryer) (map[string]*Seeded, error) {
+
+	ctx := context.Background()
+	seeded := map[string]*Seeded{}
+
+	exists := false
+	err := q.QueryRowContext(ctx, seedHistoryExistsSQL).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return seeded, nil
+	}
+
+	rows, err := q.QueryContext(ctx, seedHistorySQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := Seeded{}
+		err = rows.Scan(&s.Name, &s.Checksum, &s.SeededAt)
+		if err != nil {
+			return nil, err
+		}
+		seeded[s.Name] = &s
+	}
+
+	return seeded, rows.Err()
+}
+
+// seedStatus compares registered seeds with those seeded. Seeds that are
+// no longer registered left their data behind and are not reported.
+func seedStatus(ss []*Seed, seeded map[string]*Seeded) []*SeedStatus {
+
+	var sts []*SeedStatus
+
+	for _, s := range ss {
+		st := &SeedStatus{Name: s.Name, State: StatePending}
+		if sd, ok := seeded[s.Name]; ok {
+			st.State = StateApplied
+			if sd.Checksum != s.Checksum() {
+				st.State = StateStale
+			}
+			at := sd.SeededAt
+			st.SeededAt = &at
+		}
+		sts = append(sts, st)
+	}
+
+	return sts
+}
@@ -0,0 +1,103 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+)
+
+// Startup modes for APP_MIGRATE_ON_START
+const (
+	// StartupOff - start without looking at the schema
+	StartupOff = "off"
+	// StartupCheck - refuse to start unless every migration is applied
+	StartupCheck = "check"
+	// StartupApply - apply pending migrations and seeds, then start
+	StartupApply = "apply"
+)
+
+// Startup checks or migrates the schema before the application prepares
+// statements against it, as set by APP_MIGRATE_ON_START. Replicas starting
+// together in apply mode take turns holding the migration lock.
+func Startup(e *env.Env, l zerolog.Logger) error {
+
+	mode := e.Config.Database.MigrateOnStart
+	if mode != StartupCheck && mode != StartupApply {
+		return nil
+	}
+
+	mdb := db.NewOwnerDB(l, e)
+	defer mdb.Close()
+
+	r := NewRunner(l, mdb)
+
+	if mode == StartupApply {
+		l.Info().Msg("Applying migrations")
+		err := r.Up()
+		if err != nil {
+			return err
+		}
+	}
+
+	l.Info().Msg("Checking schema version")
+
+	return r.Check()
+}
+
+// Check returns an error listing every migration that is not applied, or
+// was applied but has since been edited or removed, and every seed that
+// has not been seeded or has been edited since
+func (r *Runner) Check() error {
+
+	ss, err := r.Status()
+	if err != nil {
+		return err
+	}
+
+	sds, err := r.SeedStatus()
+	if err != nil {
+		return err
+	}
+
+	return check(ss, sds)
+}
+
+func check(ss []*Status, sds []*SeedStatus) error {
+
+	problems := map[string][]string{}
+	var states []string
+
+	add := func(state, name string) {
+		if _, ok := problems[state]; !ok {
+			states = append(states, state)
+		}
+		problems[state] = append(problems[state], name)
+	}
+
+	for _, s := range ss {
+		if s.State != StateApplied {
+			add(s.State, fmt.Sprintf("%d_%s", s.Version, s.Name))
+		}
+	}
+
+	for _, s := range sds {
+		if s.State != StateApplied {
+			add("seeds "+s.State, s.Name)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	msg := "Database schema does not match this build"
+	for _, state := range states {
+		msg += fmt.Sprintf(", %s: %s", state, strings.Join(problems[state], " "))
+	}
+	msg += ". Run test-api migrate up or set APP_MIGRATE_ON_START=apply"
+
+	return fmt.Errorf("%s", msg)
+}
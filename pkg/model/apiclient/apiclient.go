@@ -0,0 +1,125 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID        string         `db:"id"`
+	TenantID  string         `db:"tenant_id"`
+	Name      string         `db:"name"`
+	KeyHash   string         `db:"key_hash"`
+	Role      string         `db:"role"`
+	Status    string         `db:"status"`
+	RateLimit sql.NullString `db:"rate_limit"`
+	CreatedAt string         `db:"created_at"`
+	UpdatedAt sql.NullString `db:"updated_at"`
+	DeletedAt sql.NullString `db:"deleted_at"`
+}
+
+// API client role values
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+	// RoleOperator - runs the service, such as changing log levels,
+	// rather than managing a tenant's data
+	RoleOperator = "operator"
+)
+
+// API client status values
+const (
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+// HashKey returns the stored representation of an API key. Keys are
+// random tokens so an unsalted hash is sufficient.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// GetByKey returns the active client for an API key
+func (m *Model) GetByKey(key string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByKeyHashStmt)
+
+	err := stmt.QueryRowx(HashKey(key)).StructScan(&rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// Create creates a client with a new API key. The key is returned once
+// and only its hash is stored.
+func (m *Model) Create(rec *Record) (string, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// key
+	key := util.GenerateToken()
+	rec.KeyHash = HashKey(key)
+
+	// status - initially is always active
+	rec.Status = StatusActive
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return "", err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,65 @@
+package apiclient
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByKeyHashStmt *sqlx.Stmt
+var getByKeyHashSQL = `
+SELECT *
+FROM api_client
+WHERE key_hash = $1
+AND status = 'active'
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO api_client (
+	id,
+	tenant_id,
+	name,
+	key_hash,
+	role,
+	status,
+	rate_limit,
+	created_at
+) VALUES (
+	:id,
+	:tenant_id,
+	:name,
+	:key_hash,
+	:role,
+	:status,
+	:rate_limit,
+	:created_at
+)
+RETURNING
+	id,
+	tenant_id,
+	name,
+	key_hash,
+	role,
+	status,
+	rate_limit,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByKeyHashStmt, err = db.Preparex(getByKeyHashSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByKeyHashSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Audit operations
+const (
+	AuditOperationCreate  = "create"
+	AuditOperationUpdate  = "update"
+	AuditOperationDelete  = "delete"
+	AuditOperationRemove  = "remove"
+	AuditOperationRestore = "restore"
+)
+
+// SystemActor is recorded when changes are made outside of a request
+const SystemActor = "system"
+
+// AuditContext - who is making changes through a model
+type AuditContext struct {
+	ActorID   string
+	ActorName string
+	RequestID string
+}
+
+// SetAuditContext sets who changes made through the model are attributed to
+func (m *Base) SetAuditContext(a *AuditContext) {
+	m.AuditContext = a
+}
+
+// Audit appends a record of a change to the audit log within the model's tx.
+//
+// Before and after are any JSON serialisable representation of the entity,
+// either may be nil for creates and removes.
+func (m *Base) Audit(entityType, entityID, operation string, before, after interface{}) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	ac := m.AuditContext
+	if ac == nil {
+		ac = &AuditContext{ActorID: SystemActor, ActorName: SystemActor}
+	}
+
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return err
+	}
+
+	diff, err := AuditDiff(beforeJSON, afterJSON)
+	if err != nil {
+		return err
+	}
+
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"id":          util.GetUUID(),
+		"entity_type": entityType,
+		"entity_id":   entityID,
+		"operation":   operation,
+		"actor_id":    ac.ActorID,
+		"actor_name":  ac.ActorName,
+		"request_id":  ac.RequestID,
+		"before":      nullJSON(beforeJSON),
+		"after":       nullJSON(afterJSON),
+		"diff":        string(diffJSON),
+		"created_at":  util.GetTime(),
+	}
+
+	_, err = db.NamedExec(`
+INSERT INTO audit_log (
+	id,
+	entity_type,
+	entity_id,
+	operation,
+	actor_id,
+	actor_name,
+	request_id,
+	before,
+	after,
+	diff,
+	created_at
+) VALUES (
+	:id,
+	:entity_type,
+	:entity_id,
+	:operation,
+	:actor_id,
+	:actor_name,
+	:request_id,
+	:before,
+	:after,
+	:diff,
+	:created_at
+)
+`, params)
+	if err != nil {
+		log.Error().Msgf("Error executing audit insert %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// AuditChange - a changed field in an audit diff
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditDiff returns the top level fields that differ between two JSON
+// objects. Either may be nil.
+func AuditDiff(before, after []byte) (map[string]*AuditChange, error) {
+
+	b := map[string]interface{}{}
+	a := map[string]interface{}{}
+
+	if before != nil {
+		err := json.Unmarshal(before, &b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if after != nil {
+		err := json.Unmarshal(after, &a)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	diff := map[string]*AuditChange{}
+
+	for k, bv := range b {
+		av, ok := a[k]
+		if !ok || !reflect.DeepEqual(bv, av) {
+			diff[k] = &AuditChange{Old: bv, New: av}
+		}
+	}
+
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			diff[k] = &AuditChange{Old: nil, New: av}
+		}
+	}
+
+	return diff, nil
+}
+
+func auditJSON(v interface{}) ([]byte, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func nullJSON(b []byte) interface{} {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
@@ -0,0 +1,94 @@
+package auditlog
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	utilmodel "github.com/vegh1010/test/pkg/util/model"
+)
+
+// Record -
+//
+// Audit log records are append only, they are written by model.Base.Audit
+// and can never be updated or deleted.
+type Record struct {
+	ID         string             `db:"id"`
+	TenantID   string             `db:"tenant_id"`
+	EntityType string             `db:"entity_type"`
+	EntityID   string             `db:"entity_id"`
+	Operation  string             `db:"operation"`
+	ActorID    string             `db:"actor_id"`
+	ActorName  string             `db:"actor_name"`
+	RequestID  string             `db:"request_id"`
+	Before     types.NullJSONText `db:"before"`
+	After      types.NullJSONText `db:"after"`
+	Diff       types.JSONText     `db:"diff"`
+	CreatedAt  string             `db:"created_at"`
+}
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// GetByParam - supports the operators from util/model
+// SQLFromParamsAndOperator, for example "created_at": "between".
+func (m *Model) GetByParam(params map[string]interface{}, paramOperators map[string]string) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt, err := utilmodel.SQLFromParamsAndOperator(`
+SELECT *
+FROM audit_log
+WHERE 1 = 1
+`, params, paramOperators, "")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	return recs, rows.Err()
+}
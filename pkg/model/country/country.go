@@ -0,0 +1,199 @@
+package country
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record - an ISO 3166-1 country, ID is the alpha-2 code and CurrencyID
+// the country's default currency
+type Record struct {
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	Alpha2Code  string         `db:"alpha2_code"`
+	Alpha3Code  string         `db:"alpha3_code"`
+	NumericCode string         `db:"numeric_code"`
+	CallingCode sql.NullString `db:"calling_code"`
+	CurrencyID  sql.NullString `db:"currency_id"`
+	Status      string         `db:"status"`
+	CreatedAt   string         `db:"created_at"`
+	UpdatedAt   sql.NullString `db:"updated_at"`
+	DeletedAt   sql.NullString `db:"deleted_at"`
+}
+
+// SubdivisionRecord - an ISO 3166-2 subdivision, ID is the full code,
+// AU-NSW, and Code the part after the country, NSW
+type SubdivisionRecord struct {
+	ID        string         `db:"id"`
+	CountryID string         `db:"country_id"`
+	Code      string         `db:"code"`
+	Name      string         `db:"name"`
+	Type      string         `db:"type"`
+	ParentID  sql.NullString `db:"parent_id"`
+	Status    string         `db:"status"`
+	CreatedAt string         `db:"created_at"`
+	UpdatedAt sql.NullString `db:"updated_at"`
+}
+
+// Status values
+const (
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// NewSubdivisionRecord -
+func (m *Model) NewSubdivisionRecord() SubdivisionRecord {
+	return SubdivisionRecord{}
+}
+
+// GetAll - every country, including inactive countries, ordered by ID
+func (m *Model) GetAll() ([]*Record, error) {
+
+	// records
+	recs := []*Record{}
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	err := db.Stmtx(getAllStmt).Select(&recs)
+	if err != nil {
+		log.Error().Msgf("Error querying countries %v", err)
+		return nil, err
+	}
+
+	return recs, nil
+}
+
+// GetAllSubdivisions - every subdivision, including inactive
+// subdivisions, ordered by ID
+func (m *Model) GetAllSubdivisions() ([]*SubdivisionRecord, error) {
+
+	// records
+	recs := []*SubdivisionRecord{}
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	err := db.Stmtx(getAllSubdivisionsStmt).Select(&recs)
+	if err != nil {
+		log.Error().Msgf("Error querying subdivisions %v", err)
+		return nil, err
+	}
+
+	return recs, nil
+}
+
+// Upsert - creates or updates a country
+func (m *Model) Upsert(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(upsertStmt)
+
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing upsert %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpsertSubdivision - creates or updates a subdivision
+func (m *Model) UpsertSubdivision(rec *SubdivisionRecord) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(upsertSubdivisionStmt)
+
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing subdivision upsert %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Deactivate - marks a country inactive so it can no longer be used
+func (m *Model) Deactivate(id string) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	_, err := db.Stmtx(deactivateStmt).Exec(id, util.GetTime())
+	if err != nil {
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeactivateSubdivision - marks a subdivision inactive so it can no
+// longer be used
+func (m *Model) DeactivateSubdivision(id string) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	_, err := db.Stmtx(deactivateSubdivisionStmt).Exec(id, util.GetTime())
+	if err != nil {
+		log.Error().Msgf("Error executing subdivision update %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package country
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getAllStmt *sqlx.Stmt
+var getAllSQL = `
+SELECT *
+FROM country
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+var getAllSubdivisionsStmt *sqlx.Stmt
+var getAllSubdivisionsSQL = `
+SELECT *
+FROM country_subdivision
+ORDER BY id
+`
+
+var upsertStmt *sqlx.NamedStmt
+var upsertSQL = `
+INSERT INTO country (
+	id,
+	name,
+	alpha2_code,
+	alpha3_code,
+	numeric_code,
+	calling_code,
+	currency_id,
+	status,
+	created_at
+) VALUES (
+	:id,
+	:name,
+	:alpha2_code,
+	:alpha3_code,
+	:numeric_code,
+	:calling_code,
+	:currency_id,
+	:status,
+	:created_at
+)
+ON CONFLICT (id) DO UPDATE SET
+	name         = EXCLUDED.name,
+	alpha2_code  = EXCLUDED.alpha2_code,
+	alpha3_code  = EXCLUDED.alpha3_code,
+	numeric_code = EXCLUDED.numeric_code,
+	calling_code = EXCLUDED.calling_code,
+	currency_id  = EXCLUDED.currency_id,
+	status       = EXCLUDED.status,
+	updated_at   = EXCLUDED.created_at
+RETURNING *
+`
+
+var upsertSubdivisionStmt *sqlx.NamedStmt
+var upsertSubdivisionSQL = `
+INSERT INTO country_subdivision (
+	id,
+	country_id,
+	code,
+	name,
+	type,
+	parent_id,
+	status,
+	created_at
+) VALUES (
+	:id,
+	:country_id,
+	:code,
+	:name,
+	:type,
+	:parent_id,
+	:status,
+	:created_at
+)
+ON CONFLICT (id) DO UPDATE SET
+	name       = EXCLUDED.name,
+	type       = EXCLUDED.type,
+	parent_id  = EXCLUDED.parent_id,
+	status     = EXCLUDED.status,
+	updated_at = EXCLUDED.created_at
+RETURNING *
+`
+
+var deactivateStmt *sqlx.Stmt
+var deactivateSQL = `
+UPDATE country SET
+	status     = 'inactive',
+	updated_at = $2
+WHERE id = $1
+`
+
+var deactivateSubdivisionStmt *sqlx.Stmt
+var deactivateSubdivisionSQL = `
+UPDATE country_subdivision SET
+	status     = 'inactive',
+	updated_at = $2
+WHERE id = $1
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getAllStmt, err = db.Preparex(getAllSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getAllSQL %v", err)
+	}
+
+	getAllSubdivisionsStmt, err = db.Preparex(getAllSubdivisionsSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getAllSubdivisionsSQL %v", err)
+	}
+
+	upsertStmt, err = db.PrepareNamed(upsertSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare upsertSQL %v", err)
+	}
+
+	upsertSubdivisionStmt, err = db.PrepareNamed(upsertSubdivisionSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare upsertSubdivisionSQL %v", err)
+	}
+
+	deactivateStmt, err = db.Preparex(deactivateSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deactivateSQL %v", err)
+	}
+
+	deactivateSubdivisionStmt, err = db.Preparex(deactivateSubdivisionSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deactivateSubdivisionSQL %v", err)
+	}
+
+}
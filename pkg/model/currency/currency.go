@@ -0,0 +1,143 @@
+package currency
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+)
+
+// Record - an ISO 4217 currency
+type Record struct {
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	NumericCode string         `db:"numeric_code"`
+	MinorUnits  int32          `db:"minor_units"`
+	Status      string         `db:"status"`
+	CreatedAt   string         `db:"created_at"`
+	UpdatedAt   sql.NullString `db:"updated_at"`
+	DeletedAt   sql.NullString `db:"deleted_at"`
+}
+
+// Status values
+const (
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching currency record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// GetByParam -
+func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt := `
+SELECT *
+FROM currency
+WHERE deleted_at IS NULL
+`
+
+	// params
+	for k := range params {
+		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
+	}
+
+	sqlStmt = sqlStmt + "ORDER BY id\n"
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	return recs, rows.Err()
+}
+
+// GetExponents returns the minor units of every currency keyed by code.
+// It runs outside of a request tx so it can be loaded at startup, after
+// PrepareStatements.
+func GetExponents() (map[string]int32, error) {
+
+	var recs []*Record
+
+	err := getAllStmt.Select(&recs)
+	if err != nil {
+		return nil, err
+	}
+
+	exps := map[string]int32{}
+	for _, rec := range recs {
+		exps[rec.ID] = rec.MinorUnits
+	}
+
+	return exps, nil
+}
@@ -0,0 +1,38 @@
+package currency
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM currency
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var getAllStmt *sqlx.Stmt
+var getAllSQL = `
+SELECT *
+FROM currency
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	getAllStmt, err = db.Preparex(getAllSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getAllSQL %v", err)
+	}
+
+}
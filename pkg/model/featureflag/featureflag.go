@@ -0,0 +1,200 @@
+package featureflag
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record - a feature flag, Enabled applies unless a rule says otherwise
+type Record struct {
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Enabled     bool           `db:"enabled"`
+	CreatedAt   string         `db:"created_at"`
+	UpdatedAt   sql.NullString `db:"updated_at"`
+}
+
+// RuleRecord - turns a flag on or off for one tenant or one API client
+type RuleRecord struct {
+	ID            string         `db:"id"`
+	FeatureFlagID string         `db:"feature_flag_id"`
+	TenantID      sql.NullString `db:"tenant_id"`
+	APIClientID   sql.NullString `db:"api_client_id"`
+	Enabled       bool           `db:"enabled"`
+	CreatedAt     string         `db:"created_at"`
+}
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// NewRuleRecord -
+func (m *Model) NewRuleRecord() RuleRecord {
+	return RuleRecord{}
+}
+
+// GetAll - every flag ordered by name
+func (m *Model) GetAll() ([]*Record, error) {
+
+	// records
+	recs := []*Record{}
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	err := db.Stmtx(getAllStmt).Select(&recs)
+	if err != nil {
+		log.Error().Msgf("Error querying feature flags %v", err)
+		return nil, err
+	}
+
+	return recs, nil
+}
+
+// GetByName -
+func (m *Model) GetByName(name string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching feature flag record by name %s", name)
+
+	// db
+	db := m.DB
+
+	err := db.Stmtx(getByNameStmt).QueryRowx(name).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// GetAllRules - every rule of every flag
+func (m *Model) GetAllRules() ([]*RuleRecord, error) {
+
+	// records
+	recs := []*RuleRecord{}
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	err := db.Stmtx(getAllRulesStmt).Select(&recs)
+	if err != nil {
+		log.Error().Msgf("Error querying feature flag rules %v", err)
+		return nil, err
+	}
+
+	return recs, nil
+}
+
+// Upsert - creates a flag or updates the flag with the same name
+func (m *Model) Upsert(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(upsertStmt)
+
+	rec.CreatedAt = util.GetTime()
+
+	m.DebugStruct("Upsert ", rec)
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing upsert %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetRule - replaces the rule for the rule's tenant or API client
+func (m *Model) SetRule(rec *RuleRecord) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	err := m.DeleteRule(rec)
+	if err != nil {
+		return err
+	}
+
+	stmt := db.NamedStmt(createRuleStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	m.DebugStruct("SetRule ", rec)
+
+	err = stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteRule - removes the rule for the rule's tenant or API client, so
+// the flag's own setting applies to them again
+func (m *Model) DeleteRule(rec *RuleRecord) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	_, err := db.Stmtx(deleteRuleStmt).Exec(rec.FeatureFlagID, rec.TenantID, rec.APIClientID)
+	if err != nil {
+		log.Error().Msgf("Error executing delete %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package featureflag
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getAllStmt *sqlx.Stmt
+var getAllSQL = `
+SELECT *
+FROM feature_flag
+ORDER BY name
+`
+
+var getByNameStmt *sqlx.Stmt
+var getByNameSQL = `
+SELECT *
+FROM feature_flag
+WHERE name = $1
+`
+
+var getAllRulesStmt *sqlx.Stmt
+var getAllRulesSQL = `
+SELECT *
+FROM feature_flag_rule
+ORDER BY feature_flag_id, created_at
+`
+
+var upsertStmt *sqlx.NamedStmt
+var upsertSQL = `
+INSERT INTO feature_flag (
+	name,
+	description,
+	enabled,
+	created_at
+) VALUES (
+	:name,
+	:description,
+	:enabled,
+	:created_at
+)
+ON CONFLICT (name) DO UPDATE SET
+	description = COALESCE(EXCLUDED.description, feature_flag.description),
+	enabled     = EXCLUDED.enabled,
+	updated_at  = EXCLUDED.created_at
+RETURNING *
+`
+
+var createRuleStmt *sqlx.NamedStmt
+var createRuleSQL = `
+INSERT INTO feature_flag_rule (
+	id,
+	feature_flag_id,
+	tenant_id,
+	api_client_id,
+	enabled,
+	created_at
+) VALUES (
+	:id,
+	:feature_flag_id,
+	:tenant_id,
+	:api_client_id,
+	:enabled,
+	:created_at
+)
+RETURNING *
+`
+
+var deleteRuleStmt *sqlx.Stmt
+var deleteRuleSQL = `
+DELETE FROM feature_flag_rule
+WHERE feature_flag_id = $1
+AND   tenant_id IS NOT DISTINCT FROM $2::uuid
+AND   api_client_id IS NOT DISTINCT FROM $3::uuid
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getAllStmt, err = db.Preparex(getAllSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getAllSQL %v", err)
+	}
+
+	getByNameStmt, err = db.Preparex(getByNameSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByNameSQL %v", err)
+	}
+
+	getAllRulesStmt, err = db.Preparex(getAllRulesSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getAllRulesSQL %v", err)
+	}
+
+	upsertStmt, err = db.PrepareNamed(upsertSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare upsertSQL %v", err)
+	}
+
+	createRuleStmt, err = db.PrepareNamed(createRuleSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRuleSQL %v", err)
+	}
+
+	deleteRuleStmt, err = db.Preparex(deleteRuleSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRuleSQL %v", err)
+	}
+
+}
@@ -0,0 +1,215 @@
+package job
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID          string         `db:"id"`
+	Queue       string         `db:"queue"`
+	Type        string         `db:"type"`
+	Payload     types.JSONText `db:"payload"`
+	UniqueKey   sql.NullString `db:"unique_key"`
+	Status      string         `db:"status"`
+	Attempts    int            `db:"attempts"`
+	MaxAttempts int            `db:"max_attempts"`
+	RunAt       string         `db:"run_at"`
+	LastError   sql.NullString `db:"last_error"`
+	CompletedAt sql.NullString `db:"completed_at"`
+	CreatedAt   string         `db:"created_at"`
+	UpdatedAt   sql.NullString `db:"updated_at"`
+	DeletedAt   sql.NullString `db:"deleted_at"`
+}
+
+// Job status values
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusDead      = "dead"
+)
+
+// DefaultQueue -
+const DefaultQueue = "default"
+
+// DefaultMaxAttempts -
+const DefaultMaxAttempts = 10
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{
+		Queue:       DefaultQueue,
+		Payload:     types.JSONText("{}"),
+		MaxAttempts: DefaultMaxAttempts,
+	}
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching job record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// LockNext fetches the next runnable job on a queue and locks it for the
+// remainder of the tx. Jobs locked by other workers are skipped so that any
+// number of workers can poll the same queue without blocking each other.
+//
+// Returns sql.ErrNoRows when there is nothing to run.
+func (m *Model) LockNext(queue string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(lockNextStmt)
+
+	err := stmt.QueryRowx(queue, util.GetTime()).StructScan(&rec)
+	if err != nil {
+		return nil, err
+	}
+
+	m.DebugStruct("Locked", rec)
+
+	return &rec, nil
+}
+
+// Create -
+//
+// When the record has a unique key and a job with the same key already
+// exists the insert is skipped and sql.ErrNoRows is returned.
+func (m *Model) Create(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// status - initially is always pending
+	rec.Status = StatusPending
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	// run at - immediately unless delayed
+	if rec.RunAt == "" {
+		rec.RunAt = rec.CreatedAt
+	}
+
+	m.DebugStruct("Create ", rec)
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error().Msgf("Error executing insert %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Update -
+func (m *Model) Update(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(updateRecordStmt)
+
+	oldUpdatedAt := rec.UpdatedAt
+
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		rec.UpdatedAt = oldUpdatedAt
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// PurgeCompleted removes completed jobs that finished before the provided time
+func (m *Model) PurgeCompleted(before string) (int64, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(purgeCompletedStmt)
+
+	res, err := stmt.Exec(before)
+	if err != nil {
+		log.Error().Msgf("Error executing delete %v", err)
+		return 0, err
+	}
+
+	raf, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	log.Debug().Msgf("Purged %d completed jobs", raf)
+
+	return raf, nil
+}
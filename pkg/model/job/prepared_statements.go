@@ -0,0 +1,136 @@
+package job
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM job
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var lockNextStmt *sqlx.Stmt
+var lockNextSQL = `
+SELECT *
+FROM job
+WHERE queue = $1
+AND status = 'pending'
+AND run_at <= $2
+AND deleted_at IS NULL
+ORDER BY run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO job (
+	id,
+	queue,
+	type,
+	payload,
+	unique_key,
+	status,
+	attempts,
+	max_attempts,
+	run_at,
+	created_at
+) VALUES (
+	:id,
+	:queue,
+	:type,
+	:payload,
+	:unique_key,
+	:status,
+	:attempts,
+	:max_attempts,
+	:run_at,
+	:created_at
+)
+ON CONFLICT (unique_key) WHERE unique_key IS NOT NULL DO NOTHING
+RETURNING
+	id,
+	queue,
+	type,
+	payload,
+	unique_key,
+	status,
+	attempts,
+	max_attempts,
+	run_at,
+	last_error,
+	completed_at,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE job SET
+	status         = :status,
+	attempts       = :attempts,
+	run_at         = :run_at,
+	last_error     = :last_error,
+	completed_at   = :completed_at,
+	updated_at     = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING
+	id,
+	queue,
+	type,
+	payload,
+	unique_key,
+	status,
+	attempts,
+	max_attempts,
+	run_at,
+	last_error,
+	completed_at,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+var purgeCompletedStmt *sqlx.Stmt
+var purgeCompletedSQL = `
+DELETE FROM job
+WHERE status = 'completed'
+AND completed_at < $1
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	lockNextStmt, err = db.Preparex(lockNextSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare lockNextSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	purgeCompletedStmt, err = db.Preparex(purgeCompletedSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare purgeCompletedSQL %v", err)
+	}
+
+}
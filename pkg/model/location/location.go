@@ -0,0 +1,288 @@
+package location
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID         string         `db:"id"`
+	TenantID   string         `db:"tenant_id"`
+	MerchantID string         `db:"merchant_id"`
+	Name       string         `db:"name"`
+	TimezoneID string         `db:"timezone_id"`
+	Status     string         `db:"status"`
+	CreatedAt  string         `db:"created_at"`
+	UpdatedAt  sql.NullString `db:"updated_at"`
+	DeletedAt  sql.NullString `db:"deleted_at"`
+}
+
+// Location status values
+const (
+	StatusActive     = "active"
+	StatusInactive   = "inactive"
+	StatusTerminated = "terminated"
+)
+
+// EntityType - audit log entity type
+const EntityType = "location"
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching location record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// GetByParam -
+func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt := `
+SELECT *
+FROM location
+WHERE deleted_at IS NULL
+`
+
+	// params
+	for k := range params {
+		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
+	}
+
+	sqlStmt = sqlStmt + "ORDER BY created_at\n"
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	m.DebugStruct("Fetched", recs)
+
+	return recs, rows.Err()
+}
+
+// Create -
+func (m *Model) Create(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// status - initially is always active
+	rec.Status = StatusActive
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return err
+	}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationCreate, nil, auditData(rec))
+}
+
+// Update -
+func (m *Model) Update(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// current record for audit
+	cur, err := m.GetByID(rec.ID)
+	if err != nil {
+		return err
+	}
+
+	stmt := db.NamedStmt(updateRecordStmt)
+
+	oldUpdatedAt := rec.UpdatedAt
+
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err = stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		rec.UpdatedAt = oldUpdatedAt
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationUpdate, auditData(cur), auditData(rec))
+}
+
+// Delete -
+func (m *Model) Delete(id string) error {
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Delete ID %s", id)
+
+	// db
+	db := m.DB
+
+	rec := m.NewRecord()
+	rec.ID = id
+
+	stmt := db.NamedStmt(deleteRecordStmt)
+
+	// deleted at
+	rec.DeletedAt.String = util.GetTime()
+	rec.DeletedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing delete %s", err)
+		return err
+	}
+
+	before := rec
+	before.DeletedAt = sql.NullString{}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationDelete, auditData(&before), auditData(&rec))
+}
+
+// TerminateByMerchantID terminates all of a merchant's locations that
+// have not already been terminated
+func (m *Model) TerminateByMerchantID(merchantID string) error {
+
+	recs, err := m.GetByParam(map[string]interface{}{"merchant_id": merchantID})
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range recs {
+		if rec.Status == StatusTerminated {
+			continue
+		}
+		rec.Status = StatusTerminated
+		err = m.Update(rec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// auditData - location representation recorded in the audit log
+func auditData(rec *Record) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          rec.ID,
+		"merchant_id": rec.MerchantID,
+		"name":        rec.Name,
+		"timezone_id": rec.TimezoneID,
+		"status":      rec.Status,
+		"deleted_at":  rec.DeletedAt.String,
+	}
+}
+
+// ValidateResult is used for validating against location config
+type ValidateResult struct {
+	TimezoneID sql.NullBool `db:"timezone_id"`
+}
+
+// ValidateRecord - validates properties of a record are valid for creating or updating
+func (m *Model) ValidateRecord(rec *Record) (*ValidateResult, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	log.Debug().Msgf("Validating location record %v", rec)
+
+	stmt := db.NamedStmt(validateRecordStmt)
+
+	vrec := ValidateResult{}
+
+	err := stmt.QueryRowx(rec).StructScan(&vrec)
+	if err != nil {
+		log.Error().Msgf("Error executing validation query %v", err)
+		return nil, err
+	}
+
+	return &vrec, nil
+}
@@ -0,0 +1,98 @@
+package location
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM location
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO location (
+	id,
+	merchant_id,
+	name,
+	timezone_id,
+	status,
+	created_at
+) VALUES (
+	:id,
+	:merchant_id,
+	:name,
+	:timezone_id,
+	:status,
+	:created_at
+)
+RETURNING *
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE location SET
+	name        = :name,
+	timezone_id = :timezone_id,
+	status      = :status,
+	updated_at  = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var deleteRecordStmt *sqlx.NamedStmt
+var deleteRecordSQL = `
+UPDATE location SET
+	deleted_at = :deleted_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var validateRecordStmt *sqlx.NamedStmt
+var validateRecordSQL = `
+SELECT
+(
+	SELECT 1
+	FROM   timezone
+	WHERE  id = :timezone_id
+	AND    status = 'active'
+	AND    deleted_at IS NULL
+) timezone_id
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	deleteRecordStmt, err = db.PrepareNamed(deleteRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
+	}
+
+	validateRecordStmt, err = db.PrepareNamed(validateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare validateRecordSQL %v", err)
+	}
+
+}
@@ -4,25 +4,31 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/model/location"
+	"github.com/vegh1010/test/pkg/model/outboxevent"
 	"github.com/vegh1010/test/pkg/env"
 	"github.com/vegh1010/test/pkg/util"
 )
 
 // Record -
 type Record struct {
-	ID         string         `db:"id"`
-	Name       string         `db:"name"`
-	ShortName  string         `db:"short_name"`
-	DBAName    string         `db:"dba_name"`
-	CountryID  string         `db:"country_id"`
-	TimezoneID string         `db:"timezone_id"`
-	Status     string         `db:"status"`
-	CreatedAt  string         `db:"created_at"`
-	UpdatedAt  sql.NullString `db:"updated_at"`
-	DeletedAt  sql.NullString `db:"deleted_at"`
+	ID             string         `db:"id"`
+	TenantID       string         `db:"tenant_id"`
+	OrganisationID sql.NullString `db:"organisation_id"`
+	Name           string         `db:"name"`
+	ShortName      string         `db:"short_name"`
+	DBAName        string         `db:"dba_name"`
+	CountryID      string         `db:"country_id"`
+	TimezoneID     string         `db:"timezone_id"`
+	Currencies     pq.StringArray `db:"currencies"`
+	Status         string         `db:"status"`
+	CreatedAt      string         `db:"created_at"`
+	UpdatedAt      sql.NullString `db:"updated_at"`
+	DeletedAt      sql.NullString `db:"deleted_at"`
 }
 
 // StatusCommentRecord -
@@ -37,6 +43,16 @@ type StatusCommentRecord struct {
 	DeletedAt  sql.NullString `db:"deleted_at"`
 }
 
+// SearchRecord - a merchant matching a search with its relevance score
+// and the matching fields highlighted
+type SearchRecord struct {
+	ID                 string  `db:"id"`
+	Score              float64 `db:"score"`
+	NameHighlight      string  `db:"name_highlight"`
+	ShortNameHighlight string  `db:"short_name_highlight"`
+	DBANameHighlight   string  `db:"dba_name_highlight"`
+}
+
 // External ID status values
 const (
 	StatusActive     = "active"
@@ -44,6 +60,44 @@ const (
 	StatusTerminated = "terminated"
 )
 
+// Outbox event types
+const (
+	AggregateType          = "merchant"
+	EventTypeCreated       = "merchant.created"
+	EventTypeUpdated       = "merchant.updated"
+	EventTypeStatusChanged = "merchant.status_changed"
+	EventTypeDeleted       = "merchant.deleted"
+	EventTypeRestored      = "merchant.restored"
+	EventTypeRemoved       = "merchant.removed"
+)
+
+// EventTypes - all merchant event types
+var EventTypes = []string{
+	EventTypeCreated,
+	EventTypeUpdated,
+	EventTypeStatusChanged,
+	EventTypeDeleted,
+	EventTypeRestored,
+	EventTypeRemoved,
+}
+
+// EventData - merchant representation published in outbox events
+type EventData struct {
+	ID             string   `json:"id"`
+	OrganisationID string   `json:"organisation_id,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	ShortName      string   `json:"short_name,omitempty"`
+	DBAName        string   `json:"dba_name,omitempty"`
+	CountryID      string   `json:"country_id,omitempty"`
+	TimezoneID     string   `json:"timezone_id,omitempty"`
+	Currencies     []string `json:"currencies,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	PreviousStatus string   `json:"previous_status,omitempty"`
+	CreatedAt      string   `json:"created_at,omitempty"`
+	UpdatedAt      string   `json:"updated_at,omitempty"`
+	DeletedAt      string   `json:"deleted_at,omitempty"`
+}
+
 // Model -
 type Model struct {
 	model.Base
@@ -91,7 +145,7 @@ func (m *Model) GetByID(id string) (*Record, error) {
 
 	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
 	if err != nil {
-		log.Error().Msgf("Error executing update ", err)
+		log.Error().Msgf("Error executing select %v", err)
 		return nil, err
 	}
 
@@ -100,10 +154,47 @@ func (m *Model) GetByID(id string) (*Record, error) {
 
 // GetByParam -
 func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+	return m.getByParam(params, false)
+}
+
+// GetByParamIncludingDeleted - as GetByParam but also returns soft deleted records
+func (m *Model) GetByParamIncludingDeleted(params map[string]interface{}) ([]*Record, error) {
+	return m.getByParam(params, true)
+}
+
+// getByParam -
+func (m *Model) getByParam(params map[string]interface{}, includeDeleted bool) ([]*Record, error) {
 
 	// records
 	var recs []*Record
 
+	err := m.eachByParam(params, includeDeleted, func(rec *Record) error {
+		recs = append(recs, rec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.DebugStruct("Fetched", recs)
+
+	return recs, nil
+}
+
+// EachByParam - as GetByParam but calls fn with each record as it is
+// scanned rather than returning them all, returning the first error
+func (m *Model) EachByParam(params map[string]interface{}, fn func(*Record) error) error {
+	return m.eachByParam(params, false, fn)
+}
+
+// EachByParamIncludingDeleted - as EachByParam but also returns soft deleted records
+func (m *Model) EachByParamIncludingDeleted(params map[string]interface{}, fn func(*Record) error) error {
+	return m.eachByParam(params, true, fn)
+}
+
+// eachByParam -
+func (m *Model) eachByParam(params map[string]interface{}, includeDeleted bool, fn func(*Record) error) error {
+
 	// log
 	log := m.Logger
 
@@ -114,9 +205,13 @@ func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
 	sqlStmt := `
 SELECT *
 FROM merchant
-WHERE deleted_at IS NULL
+WHERE 1 = 1
 `
 
+	if !includeDeleted {
+		sqlStmt = sqlStmt + "AND deleted_at IS NULL\n"
+	}
+
 	// params
 	for k := range params {
 		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
@@ -125,7 +220,7 @@ WHERE deleted_at IS NULL
 	rows, err := db.NamedQuery(sqlStmt, params)
 	if err != nil {
 		log.Error().Msgf("Error querying row %s", err)
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
@@ -133,14 +228,66 @@ WHERE deleted_at IS NULL
 		var e Record
 		err = rows.StructScan(&e)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		err = fn(&e)
+		if err != nil {
+			return err
 		}
-		recs = append(recs, &e)
 	}
 
-	m.DebugStruct("Fetched", recs)
+	return rows.Err()
+}
+
+// GetByIDs - records for the given IDs, in no particular order
+func (m *Model) GetByIDs(ids []string) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDsStmt)
+
+	err := stmt.Select(&recs, pq.Array(ids))
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return recs, nil
+}
+
+// Search - full text and fuzzy search over merchant names.
+//
+// The tsquery matches whole words and prefixes, text is matched against
+// names by trigram word similarity so misspelt names are still found.
+func (m *Model) Search(tsquery, text string, limit int) ([]*SearchRecord, error) {
+
+	// records
+	var recs []*SearchRecord
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Searching merchants tsquery %s text %s", tsquery, text)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(searchStmt)
 
-	return recs, rows.Err()
+	err := stmt.Select(&recs, tsquery, text, limit)
+	if err != nil {
+		log.Error().Msgf("Error executing search %v", err)
+		return nil, err
+	}
+
+	return recs, nil
 }
 
 // GetOneByParam -
@@ -191,7 +338,14 @@ func (m *Model) Create(rec *Record) error {
 		return err
 	}
 
-	return nil
+	data := m.eventData(rec)
+
+	err = m.writeEvent(EventTypeCreated, rec.ID, data)
+	if err != nil {
+		return err
+	}
+
+	return m.Audit(AggregateType, rec.ID, model.AuditOperationCreate, nil, data)
 }
 
 // Update -
@@ -203,6 +357,12 @@ func (m *Model) Update(rec *Record) error {
 	// db
 	db := m.DB
 
+	// current record for status change events and audit
+	cur, err := m.GetByID(rec.ID)
+	if err != nil {
+		return err
+	}
+
 	stmt := db.NamedStmt(updateRecordStmt)
 
 	oldUpdatedAt := rec.UpdatedAt
@@ -210,16 +370,76 @@ func (m *Model) Update(rec *Record) error {
 	rec.UpdatedAt.String = util.GetTime()
 	rec.UpdatedAt.Valid = true
 
-	err := stmt.QueryRowx(rec).StructScan(rec)
+	err = stmt.QueryRowx(rec).StructScan(rec)
 	if err != nil {
 		rec.UpdatedAt = oldUpdatedAt
 		log.Error().Msgf("Error executing update %v", err)
 		return err
 	}
 
+	data := m.eventData(rec)
+
+	err = m.writeEvent(EventTypeUpdated, rec.ID, data)
+	if err != nil {
+		return err
+	}
+
+	err = m.Audit(AggregateType, rec.ID, model.AuditOperationUpdate, m.eventData(cur), data)
+	if err != nil {
+		return err
+	}
+
+	if cur.Status != rec.Status {
+		data.PreviousStatus = cur.Status
+		err = m.writeEvent(EventTypeStatusChanged, rec.ID, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	// terminating a merchant terminates its locations
+	if rec.Status == StatusTerminated && cur.Status != StatusTerminated {
+		return m.terminateLocations(rec.ID)
+	}
+
+	return nil
+}
+
+// TerminateByOrganisationID terminates all of an organisation's merchants
+// that have not already been terminated, along with their locations
+func (m *Model) TerminateByOrganisationID(organisationID string) error {
+
+	recs, err := m.GetByParam(map[string]interface{}{"organisation_id": organisationID})
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range recs {
+		if rec.Status == StatusTerminated {
+			continue
+		}
+		rec.Status = StatusTerminated
+		err = m.Update(rec)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// terminateLocations -
+func (m *Model) terminateLocations(id string) error {
+
+	lm, err := location.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+	lm.SetAuditContext(m.AuditContext)
+
+	return lm.TerminateByMerchantID(id)
+}
+
 // Delete -
 func (m *Model) Delete(id string) error {
 
@@ -231,6 +451,15 @@ func (m *Model) Delete(id string) error {
 	// db
 	db := m.DB
 
+	// current record for audit
+	cur, err := m.getByParam(map[string]interface{}{"id": id}, false)
+	if err != nil {
+		return err
+	}
+	if len(cur) != 1 {
+		return sql.ErrNoRows
+	}
+
 	rec := m.NewRecord()
 	rec.ID = id
 
@@ -240,13 +469,87 @@ func (m *Model) Delete(id string) error {
 	rec.DeletedAt.String = util.GetTime()
 	rec.DeletedAt.Valid = true
 
-	err := stmt.QueryRowx(rec).StructScan(&rec)
+	err = stmt.QueryRowx(rec).StructScan(&rec)
 	if err != nil {
 		log.Error().Msgf("Error executing delete %s", err)
 		return err
 	}
 
-	return nil
+	data := m.eventData(&rec)
+
+	err = m.writeEvent(EventTypeDeleted, rec.ID, data)
+	if err != nil {
+		return err
+	}
+
+	return m.Audit(AggregateType, rec.ID, model.AuditOperationDelete, m.eventData(cur[0]), data)
+}
+
+// Restore - reverses a soft delete
+func (m *Model) Restore(id string) (*Record, error) {
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Restore ID %s", id)
+
+	// db
+	db := m.DB
+
+	// current record for audit
+	cur, err := m.getByParam(map[string]interface{}{"id": id}, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(cur) != 1 {
+		return nil, sql.ErrNoRows
+	}
+
+	rec := m.NewRecord()
+	rec.ID = id
+
+	stmt := db.NamedStmt(restoreRecordStmt)
+
+	// updated at
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err = stmt.QueryRowx(rec).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing restore %s", err)
+		return nil, err
+	}
+
+	data := m.eventData(&rec)
+
+	err = m.writeEvent(EventTypeRestored, rec.ID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, m.Audit(AggregateType, rec.ID, model.AuditOperationRestore, m.eventData(cur[0]), data)
+}
+
+// GetDeletedBefore - IDs of records soft deleted before the given time
+func (m *Model) GetDeletedBefore(before string) ([]string, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	var ids []string
+
+	stmt := db.Stmtx(getDeletedBeforeStmt)
+
+	err := stmt.Select(&ids, before)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return ids, nil
 }
 
 // Remove -
@@ -260,33 +563,93 @@ func (m *Model) Remove(id string) error {
 	// db
 	db := m.DB
 
+	// current record for audit
+	cur, err := m.getByParam(map[string]interface{}{"id": id}, true)
+	if err != nil {
+		return err
+	}
+	if len(cur) != 1 {
+		return sql.ErrNoRows
+	}
+
 	rec := m.NewRecord()
 	rec.ID = id
 
 	// remove merchant record
 	stmt := db.Stmtx(removeRecordStmt)
 
-	res, err := stmt.Exec(rec.ID)
+	err = stmt.QueryRowx(rec.ID).StructScan(&rec)
 	if err != nil {
 		log.Error().Msgf("Error executing delete %s", err)
 		return err
 	}
 
-	// rows affected
-	raf, err := res.RowsAffected()
-	if raf != 1 {
-		return fmt.Errorf("Expecting to remove exactly one row removed, got %d  ", raf)
+	err = m.writeEvent(EventTypeRemoved, rec.ID, &EventData{ID: rec.ID})
+	if err != nil {
+		return err
 	}
 
-	log.Debug().Msgf("Result RowsAffected %d", raf)
+	return m.Audit(AggregateType, rec.ID, model.AuditOperationRemove, m.eventData(cur[0]), nil)
+}
+
+// eventData -
+func (m *Model) eventData(rec *Record) *EventData {
+	return &EventData{
+		ID:             rec.ID,
+		OrganisationID: rec.OrganisationID.String,
+		Name:           rec.Name,
+		ShortName:      rec.ShortName,
+		DBAName:        rec.DBAName,
+		CountryID:      rec.CountryID,
+		TimezoneID:     rec.TimezoneID,
+		Currencies:     rec.Currencies,
+		Status:         rec.Status,
+		CreatedAt:      rec.CreatedAt,
+		UpdatedAt:      rec.UpdatedAt.String,
+		DeletedAt:      rec.DeletedAt.String,
+	}
+}
+
+// writeEvent writes a merchant event to the outbox within the model's tx
+func (m *Model) writeEvent(eventType, id string, data *EventData) error {
+
+	om, err := outboxevent.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	_, err = om.Write(AggregateType, id, eventType, data)
 
 	return err
 }
 
+// GetCountryCurrency returns the currency of a country, or an empty
+// string when the country has no currency
+func (m *Model) GetCountryCurrency(countryID string) (string, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	var currency string
+
+	err := db.Stmtx(getCountryCurrencyStmt).QueryRowx(countryID).Scan(&currency)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return "", err
+	}
+
+	return currency, nil
+}
+
 // ValidateResult is used for validating against merchant config and existing transactions
 type ValidateResult struct {
-	CountryID  sql.NullBool `db:"country_id"`
-	TimezoneID sql.NullBool `db:"timezone_id"`
+	CountryID      sql.NullBool `db:"country_id"`
+	TimezoneID     sql.NullBool `db:"timezone_id"`
+	OrganisationID sql.NullBool `db:"organisation_id"`
+	Currencies     sql.NullBool `db:"currencies"`
 }
 
 // ValidateRecord - validates properties of a record are valid for creating or updating
@@ -13,34 +13,76 @@ WHERE id = $1
 AND deleted_at IS NULL
 `
 
+var getByIDsStmt *sqlx.Stmt
+var getByIDsSQL = `
+SELECT *
+FROM merchant
+WHERE id = ANY($1)
+AND deleted_at IS NULL
+`
+
+var searchStmt *sqlx.Stmt
+var searchSQL = `
+SELECT
+	m.id,
+	ts_rank(to_tsvector('simple', m.name || ' ' || m.short_name || ' ' || m.dba_name), q.query)
+	+ GREATEST(
+		word_similarity(q.text, m.name),
+		word_similarity(q.text, m.short_name),
+		word_similarity(q.text, m.dba_name)
+	) AS score,
+	ts_headline('simple', m.name, q.query, 'StartSel=<em>, StopSel=</em>, HighlightAll=true') AS name_highlight,
+	ts_headline('simple', m.short_name, q.query, 'StartSel=<em>, StopSel=</em>, HighlightAll=true') AS short_name_highlight,
+	ts_headline('simple', m.dba_name, q.query, 'StartSel=<em>, StopSel=</em>, HighlightAll=true') AS dba_name_highlight
+FROM merchant m, (
+	SELECT to_tsquery('simple', $1) AS query, $2::TEXT AS text
+) q
+WHERE m.deleted_at IS NULL
+AND (
+	to_tsvector('simple', m.name || ' ' || m.short_name || ' ' || m.dba_name) @@ q.query
+	OR q.text <% m.name
+	OR q.text <% m.short_name
+	OR q.text <% m.dba_name
+)
+ORDER BY score DESC, m.name
+LIMIT $3
+`
+
 var createRecordStmt *sqlx.NamedStmt
 var createRecordSQL = `
 INSERT INTO merchant (
 	id,
+	organisation_id,
 	name,
 	short_name,
 	dba_name,
 	country_id,
 	timezone_id,
+	currencies,
 	status,
 	created_at
 ) VALUES (
 	:id,
+	:organisation_id,
 	:name,
 	:short_name,
 	:dba_name,
 	:country_id,
 	:timezone_id,
+	:currencies,
 	:status,
 	:created_at
 )
 RETURNING
 	id,
+	tenant_id,
+	organisation_id,
 	name,
 	short_name,
 	dba_name,
 	country_id,
 	timezone_id,
+	currencies,
 	status,
 	created_at,
 	updated_at,
@@ -50,22 +92,27 @@ RETURNING
 var updateRecordStmt *sqlx.NamedStmt
 var updateRecordSQL = `
 UPDATE merchant SET
-	name 	       = :name,
-	short_name     = :short_name,
-	dba_name       = :dba_name,
-	country_id     = :country_id,
-	timezone_id    = :timezone_id,
-	status         = :status,
-	updated_at     = :updated_at
+	organisation_id = :organisation_id,
+	name            = :name,
+	short_name      = :short_name,
+	dba_name        = :dba_name,
+	country_id      = :country_id,
+	timezone_id     = :timezone_id,
+	currencies      = :currencies,
+	status          = :status,
+	updated_at      = :updated_at
 WHERE id = :id
 AND deleted_at IS NULL
 RETURNING
 	id,
+	tenant_id,
+	organisation_id,
 	name,
 	short_name,
 	dba_name,
 	country_id,
 	timezone_id,
+	currencies,
 	status,
 	created_at,
 	updated_at,
@@ -80,21 +127,65 @@ WHERE id = :id
 AND deleted_at IS NULL
 RETURNING
 	id,
+	tenant_id,
+	organisation_id,
+	name,
+	short_name,
+	dba_name,
+	country_id,
+	timezone_id,
+	currencies,
+	status,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+var restoreRecordStmt *sqlx.NamedStmt
+var restoreRecordSQL = `
+UPDATE merchant SET
+	deleted_at = NULL,
+	updated_at = :updated_at
+WHERE id = :id
+AND deleted_at IS NOT NULL
+RETURNING
+	id,
+	tenant_id,
+	organisation_id,
 	name,
 	short_name,
 	dba_name,
 	country_id,
 	timezone_id,
+	currencies,
 	status,
 	created_at,
 	updated_at,
 	deleted_at
 `
 
+var getDeletedBeforeStmt *sqlx.Stmt
+var getDeletedBeforeSQL = `
+SELECT id
+FROM merchant
+WHERE deleted_at IS NOT NULL
+AND deleted_at < $1
+ORDER BY deleted_at
+`
+
 var removeRecordStmt *sqlx.Stmt
 var removeRecordSQL = `
 DELETE FROM merchant
 WHERE id = $1
+RETURNING *
+`
+
+var getCountryCurrencyStmt *sqlx.Stmt
+var getCountryCurrencySQL = `
+SELECT COALESCE(currency_id, '')
+FROM country
+WHERE id = $1
+AND deleted_at IS NULL
 `
 
 var validateRecordWithIDStmt *sqlx.NamedStmt
@@ -112,7 +203,26 @@ SELECT
 	WHERE  id = :timezone_id
 	AND    status = 'active'
 	AND    deleted_at IS NULL
-) timezone_id
+) timezone_id, (
+	SELECT CAST(:organisation_id AS UUID) IS NULL OR EXISTS (
+		SELECT 1
+		FROM   organisation
+		WHERE  id = CAST(:organisation_id AS UUID)
+		AND    status <> 'terminated'
+		AND    deleted_at IS NULL
+	)
+) organisation_id, (
+	SELECT NOT EXISTS (
+		SELECT 1
+		FROM   unnest(CAST(:currencies AS TEXT[])) AS c (id)
+		WHERE  c.id NOT IN (
+			SELECT id
+			FROM   currency
+			WHERE  status = 'active'
+			AND    deleted_at IS NULL
+		)
+	)
+) currencies
 `
 
 var validateRecordWithoutIDStmt *sqlx.NamedStmt
@@ -130,7 +240,26 @@ SELECT
 	WHERE  id = :timezone_id
 	AND    status = 'active'
 	AND    deleted_at IS NULL
-) timezone_id
+) timezone_id, (
+	SELECT CAST(:organisation_id AS UUID) IS NULL OR EXISTS (
+		SELECT 1
+		FROM   organisation
+		WHERE  id = CAST(:organisation_id AS UUID)
+		AND    status <> 'terminated'
+		AND    deleted_at IS NULL
+	)
+) organisation_id, (
+	SELECT NOT EXISTS (
+		SELECT 1
+		FROM   unnest(CAST(:currencies AS TEXT[])) AS c (id)
+		WHERE  c.id NOT IN (
+			SELECT id
+			FROM   currency
+			WHERE  status = 'active'
+			AND    deleted_at IS NULL
+		)
+	)
+) currencies
 `
 
 // PrepareStatements prepares sql statements
@@ -142,6 +271,16 @@ func PrepareStatements(db *sqlx.DB) {
 		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
 	}
 
+	getByIDsStmt, err = db.Preparex(getByIDsSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDsSQL %v", err)
+	}
+
+	searchStmt, err = db.Preparex(searchSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare searchSQL %v", err)
+	}
+
 	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
 	if err != nil {
 		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
@@ -157,11 +296,26 @@ func PrepareStatements(db *sqlx.DB) {
 		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
 	}
 
+	restoreRecordStmt, err = db.PrepareNamed(restoreRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare restoreRecordSQL %v", err)
+	}
+
+	getDeletedBeforeStmt, err = db.Preparex(getDeletedBeforeSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getDeletedBeforeSQL %v", err)
+	}
+
 	removeRecordStmt, err = db.Preparex(removeRecordSQL)
 	if err != nil {
 		log.Fatal().Msgf("Failed to prepare removeRecordSQL %v", err)
 	}
 
+	getCountryCurrencyStmt, err = db.Preparex(getCountryCurrencySQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getCountryCurrencySQL %v", err)
+	}
+
 	validateRecordWithIDStmt, err = db.PrepareNamed(validateRecordWithIDSQL)
 	if err != nil {
 		log.Fatal().Msgf("Failed to prepare validateRecordWithIDSQL %v", err)
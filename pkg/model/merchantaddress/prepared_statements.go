@@ -0,0 +1,129 @@
+package merchantaddress
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM merchant_address
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO merchant_address (
+	id,
+	merchant_id,
+	type,
+	line1,
+	line2,
+	city,
+	region,
+	postal_code,
+	country_id,
+	created_at
+) VALUES (
+	:id,
+	:merchant_id,
+	:type,
+	:line1,
+	:line2,
+	:city,
+	:region,
+	:postal_code,
+	:country_id,
+	:created_at
+)
+RETURNING *
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE merchant_address SET
+	type        = :type,
+	line1       = :line1,
+	line2       = :line2,
+	city        = :city,
+	region      = :region,
+	postal_code = :postal_code,
+	country_id  = :country_id,
+	updated_at  = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var deleteRecordStmt *sqlx.NamedStmt
+var deleteRecordSQL = `
+UPDATE merchant_address SET
+	deleted_at = :deleted_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var validateRecordStmt *sqlx.NamedStmt
+var validateRecordSQL = `
+SELECT
+(
+	SELECT 1
+	FROM   country
+	WHERE  id = :country_id
+	AND    status = 'active'
+	AND    deleted_at IS NULL
+) country_id,
+(
+	SELECT code
+	FROM   country_subdivision
+	WHERE  country_id = :country_id
+	AND    status = 'active'
+	AND    (
+		upper(id) = upper(:region) OR
+		upper(code) = upper(:region) OR
+		lower(name) = lower(:region)
+	)
+	ORDER BY id
+	LIMIT 1
+) region_code,
+EXISTS (
+	SELECT 1
+	FROM   country_subdivision
+	WHERE  country_id = :country_id
+	AND    status = 'active'
+) has_subdivisions
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	deleteRecordStmt, err = db.PrepareNamed(deleteRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
+	}
+
+	validateRecordStmt, err = db.PrepareNamed(validateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare validateRecordSQL %v", err)
+	}
+
+}
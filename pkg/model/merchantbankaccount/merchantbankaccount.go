@@ -0,0 +1,334 @@
+package merchantbankaccount
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/encryption"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID                     string         `db:"id"`
+	TenantID               string         `db:"tenant_id"`
+	MerchantID             string         `db:"merchant_id"`
+	AccountName            string         `db:"account_name"`
+	CountryID              string         `db:"country_id"`
+	Currency               string         `db:"currency"`
+	BankCode               string         `db:"bank_code"`
+	AccountNumberEncrypted string         `db:"account_number_encrypted"`
+	IsDefault              bool           `db:"is_default"`
+	CreatedAt              string         `db:"created_at"`
+	UpdatedAt              sql.NullString `db:"updated_at"`
+	DeletedAt              sql.NullString `db:"deleted_at"`
+}
+
+// EntityType - audit log entity type
+const EntityType = "merchant_bank_account"
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// SetAccountNumber encrypts an account number onto a record
+func (m *Model) SetAccountNumber(rec *Record, accountNumber string) error {
+
+	key, err := encryption.Key("APP_ENCRYPTION_KEY", m.Env.Config.Encryption.Key)
+	if err != nil {
+		return err
+	}
+
+	ct, err := encryption.Encrypt(key, accountNumber)
+	if err != nil {
+		return err
+	}
+
+	rec.AccountNumberEncrypted = ct
+
+	return nil
+}
+
+// AccountNumber decrypts the account number of a record
+func (m *Model) AccountNumber(rec *Record) (string, error) {
+
+	key, err := encryption.Key("APP_ENCRYPTION_KEY", m.Env.Config.Encryption.Key)
+	if err != nil {
+		return "", err
+	}
+
+	return encryption.Decrypt(key, rec.AccountNumberEncrypted)
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching merchant bank account record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// GetByParam -
+func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt := `
+SELECT *
+FROM merchant_bank_account
+WHERE deleted_at IS NULL
+`
+
+	// params
+	for k := range params {
+		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
+	}
+
+	sqlStmt = sqlStmt + "ORDER BY created_at\n"
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	m.DebugStruct("Fetched", recs)
+
+	return recs, rows.Err()
+}
+
+// Create - when the record is the default settlement account any other
+// default account for the merchant and currency stops being the default.
+func (m *Model) Create(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	if rec.IsDefault {
+		err := m.clearDefault(rec.MerchantID, rec.Currency, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return err
+	}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationCreate, nil, auditData(rec))
+}
+
+// Update - when the record is the default settlement account any other
+// default account for the merchant and currency stops being the default.
+func (m *Model) Update(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// current record for audit
+	cur, err := m.GetByID(rec.ID)
+	if err != nil {
+		return err
+	}
+
+	if rec.IsDefault {
+		err = m.clearDefault(rec.MerchantID, rec.Currency, rec.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt := db.NamedStmt(updateRecordStmt)
+
+	oldUpdatedAt := rec.UpdatedAt
+
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err = stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		rec.UpdatedAt = oldUpdatedAt
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationUpdate, auditData(cur), auditData(rec))
+}
+
+// clearDefault - unsets the current default account for a merchant and
+// currency, excluding the account being saved
+func (m *Model) clearDefault(merchantID, currency, excludeID string) error {
+
+	recs, err := m.GetByParam(map[string]interface{}{
+		"merchant_id": merchantID,
+		"currency":    currency,
+		"is_default":  true,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range recs {
+		if rec.ID == excludeID {
+			continue
+		}
+		rec.IsDefault = false
+		err = m.Update(rec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete -
+func (m *Model) Delete(id string) error {
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Delete ID %s", id)
+
+	// db
+	db := m.DB
+
+	rec := m.NewRecord()
+	rec.ID = id
+
+	stmt := db.NamedStmt(deleteRecordStmt)
+
+	// deleted at
+	rec.DeletedAt.String = util.GetTime()
+	rec.DeletedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing delete %s", err)
+		return err
+	}
+
+	before := rec
+	before.DeletedAt = sql.NullString{}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationDelete, auditData(&before), auditData(&rec))
+}
+
+// auditData - bank account representation recorded in the audit log, the
+// account number is never recorded
+func auditData(rec *Record) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           rec.ID,
+		"merchant_id":  rec.MerchantID,
+		"account_name": rec.AccountName,
+		"country_id":   rec.CountryID,
+		"currency":     rec.Currency,
+		"bank_code":    rec.BankCode,
+		"is_default":   rec.IsDefault,
+		"deleted_at":   rec.DeletedAt.String,
+	}
+}
+
+// ValidateResult is used for validating against bank account config
+type ValidateResult struct {
+	CountryID sql.NullBool `db:"country_id"`
+}
+
+// ValidateRecord - validates properties of a record are valid for creating or updating
+func (m *Model) ValidateRecord(rec *Record) (*ValidateResult, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	log.Debug().Msgf("Validating merchant bank account record %s", rec.ID)
+
+	stmt := db.NamedStmt(validateRecordStmt)
+
+	vrec := ValidateResult{}
+
+	err := stmt.QueryRowx(rec).StructScan(&vrec)
+	if err != nil {
+		log.Error().Msgf("Error executing validation query %v", err)
+		return nil, err
+	}
+
+	return &vrec, nil
+}
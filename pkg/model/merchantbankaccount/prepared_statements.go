@@ -0,0 +1,108 @@
+package merchantbankaccount
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM merchant_bank_account
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO merchant_bank_account (
+	id,
+	merchant_id,
+	account_name,
+	country_id,
+	currency,
+	bank_code,
+	account_number_encrypted,
+	is_default,
+	created_at
+) VALUES (
+	:id,
+	:merchant_id,
+	:account_name,
+	:country_id,
+	:currency,
+	:bank_code,
+	:account_number_encrypted,
+	:is_default,
+	:created_at
+)
+RETURNING *
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE merchant_bank_account SET
+	account_name             = :account_name,
+	country_id               = :country_id,
+	currency                 = :currency,
+	bank_code                = :bank_code,
+	account_number_encrypted = :account_number_encrypted,
+	is_default               = :is_default,
+	updated_at               = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var deleteRecordStmt *sqlx.NamedStmt
+var deleteRecordSQL = `
+UPDATE merchant_bank_account SET
+	is_default = FALSE,
+	deleted_at = :deleted_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var validateRecordStmt *sqlx.NamedStmt
+var validateRecordSQL = `
+SELECT
+(
+	SELECT 1
+	FROM   country
+	WHERE  id = :country_id
+	AND    status = 'active'
+	AND    deleted_at IS NULL
+) country_id
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	deleteRecordStmt, err = db.PrepareNamed(deleteRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
+	}
+
+	validateRecordStmt, err = db.PrepareNamed(validateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare validateRecordSQL %v", err)
+	}
+
+}
@@ -0,0 +1,249 @@
+package merchantbusinesshours
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/businesshours"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record - weekly hours, opens and closes are minutes since midnight in
+// the merchant's timezone
+type Record struct {
+	ID         string `db:"id"`
+	TenantID   string `db:"tenant_id"`
+	MerchantID string `db:"merchant_id"`
+	DayOfWeek  int    `db:"day_of_week"`
+	Opens      int    `db:"opens"`
+	Closes     int    `db:"closes"`
+	CreatedAt  string `db:"created_at"`
+}
+
+// HolidayRecord - hours replacing the weekly hours on a date, closed all
+// day when Opens and Closes are null
+type HolidayRecord struct {
+	ID         string         `db:"id"`
+	TenantID   string         `db:"tenant_id"`
+	MerchantID string         `db:"merchant_id"`
+	Date       time.Time      `db:"date"`
+	Name       sql.NullString `db:"name"`
+	Opens      sql.NullInt64  `db:"opens"`
+	Closes     sql.NullInt64  `db:"closes"`
+	CreatedAt  string         `db:"created_at"`
+}
+
+// EntityType - audit log entity type, business hours are audited against
+// the merchant they belong to
+const EntityType = "merchant_business_hours"
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// NewHolidayRecord -
+func (m *Model) NewHolidayRecord() HolidayRecord {
+	return HolidayRecord{}
+}
+
+// Schedule converts records for working out opening times
+func Schedule(recs []*Record, holidays []*HolidayRecord) *businesshours.Schedule {
+	s := businesshours.Schedule{}
+	for _, rec := range recs {
+		s.Weekly = append(s.Weekly, businesshours.Weekly{
+			Day: time.Weekday(rec.DayOfWeek),
+			Hours: businesshours.Hours{
+				Opens:  businesshours.Clock(rec.Opens),
+				Closes: businesshours.Clock(rec.Closes),
+			},
+		})
+	}
+	for _, rec := range holidays {
+		s.Holidays = append(s.Holidays, businesshours.Holiday{
+			Date:   rec.Date.Format(businesshours.DateFormat),
+			Closed: !rec.Opens.Valid,
+			Hours: businesshours.Hours{
+				Opens:  businesshours.Clock(rec.Opens.Int64),
+				Closes: businesshours.Clock(rec.Closes.Int64),
+			},
+		})
+	}
+	return &s
+}
+
+// GetByMerchantID - weekly hours of a merchant ordered by day and opening
+func (m *Model) GetByMerchantID(merchantID string) ([]*Record, error) {
+
+	// records
+	recs := []*Record{}
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByMerchantIDStmt)
+
+	rows, err := stmt.Queryx(merchantID)
+	if err != nil {
+		log.Error().Msgf("Error querying business hours %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	return recs, rows.Err()
+}
+
+// GetHolidays - holidays of a merchant ordered by date and opening
+func (m *Model) GetHolidays(merchantID string) ([]*HolidayRecord, error) {
+
+	// records
+	recs := []*HolidayRecord{}
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getHolidaysStmt)
+
+	rows, err := stmt.Queryx(merchantID)
+	if err != nil {
+		log.Error().Msgf("Error querying holidays %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e HolidayRecord
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	return recs, rows.Err()
+}
+
+// Replace - replaces a merchant's weekly hours and holidays
+func (m *Model) Replace(merchantID string, recs []*Record, holidays []*HolidayRecord) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// current records for audit
+	cur, err := m.GetByMerchantID(merchantID)
+	if err != nil {
+		return err
+	}
+
+	curHolidays, err := m.GetHolidays(merchantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Stmtx(deleteByMerchantIDStmt).Exec(merchantID)
+	if err != nil {
+		log.Error().Msgf("Error deleting business hours %v", err)
+		return err
+	}
+
+	_, err = db.Stmtx(deleteHolidaysStmt).Exec(merchantID)
+	if err != nil {
+		log.Error().Msgf("Error deleting holidays %v", err)
+		return err
+	}
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	for _, rec := range recs {
+		rec.ID = util.GetUUID()
+		rec.MerchantID = merchantID
+		rec.CreatedAt = util.GetTime()
+
+		err = stmt.QueryRowx(rec).StructScan(rec)
+		if err != nil {
+			log.Error().Msgf("Error executing insert %v", err)
+			return err
+		}
+	}
+
+	hstmt := db.NamedStmt(createHolidayStmt)
+
+	for _, rec := range holidays {
+		rec.ID = util.GetUUID()
+		rec.MerchantID = merchantID
+		rec.CreatedAt = util.GetTime()
+
+		err = hstmt.QueryRowx(rec).StructScan(rec)
+		if err != nil {
+			log.Error().Msgf("Error executing holiday insert %v", err)
+			return err
+		}
+	}
+
+	return m.Audit(EntityType, merchantID, model.AuditOperationUpdate, auditData(cur, curHolidays), auditData(recs, holidays))
+}
+
+// auditData - business hours representation recorded in the audit log
+func auditData(recs []*Record, holidays []*HolidayRecord) map[string]interface{} {
+	hd := []map[string]interface{}{}
+	for _, rec := range recs {
+		hd = append(hd, map[string]interface{}{
+			"day_of_week": rec.DayOfWeek,
+			"opens":       rec.Opens,
+			"closes":      rec.Closes,
+		})
+	}
+	od := []map[string]interface{}{}
+	for _, rec := range holidays {
+		od = append(od, map[string]interface{}{
+			"date":   rec.Date.Format(businesshours.DateFormat),
+			"name":   rec.Name.String,
+			"opens":  rec.Opens,
+			"closes": rec.Closes,
+		})
+	}
+	return map[string]interface{}{
+		"hours":    hd,
+		"holidays": od,
+	}
+}
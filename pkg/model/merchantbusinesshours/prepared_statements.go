@@ -0,0 +1,112 @@
+package merchantbusinesshours
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByMerchantIDStmt *sqlx.Stmt
+var getByMerchantIDSQL = `
+SELECT *
+FROM merchant_business_hours
+WHERE merchant_id = $1
+ORDER BY day_of_week, opens
+`
+
+var getHolidaysStmt *sqlx.Stmt
+var getHolidaysSQL = `
+SELECT *
+FROM merchant_holiday
+WHERE merchant_id = $1
+ORDER BY date, opens NULLS FIRST
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO merchant_business_hours (
+	id,
+	merchant_id,
+	day_of_week,
+	opens,
+	closes,
+	created_at
+) VALUES (
+	:id,
+	:merchant_id,
+	:day_of_week,
+	:opens,
+	:closes,
+	:created_at
+)
+RETURNING *
+`
+
+var createHolidayStmt *sqlx.NamedStmt
+var createHolidaySQL = `
+INSERT INTO merchant_holiday (
+	id,
+	merchant_id,
+	date,
+	name,
+	opens,
+	closes,
+	created_at
+) VALUES (
+	:id,
+	:merchant_id,
+	:date,
+	:name,
+	:opens,
+	:closes,
+	:created_at
+)
+RETURNING *
+`
+
+var deleteByMerchantIDStmt *sqlx.Stmt
+var deleteByMerchantIDSQL = `
+DELETE FROM merchant_business_hours
+WHERE merchant_id = $1
+`
+
+var deleteHolidaysStmt *sqlx.Stmt
+var deleteHolidaysSQL = `
+DELETE FROM merchant_holiday
+WHERE merchant_id = $1
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByMerchantIDStmt, err = db.Preparex(getByMerchantIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByMerchantIDSQL %v", err)
+	}
+
+	getHolidaysStmt, err = db.Preparex(getHolidaysSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getHolidaysSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	createHolidayStmt, err = db.PrepareNamed(createHolidaySQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createHolidaySQL %v", err)
+	}
+
+	deleteByMerchantIDStmt, err = db.Preparex(deleteByMerchantIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteByMerchantIDSQL %v", err)
+	}
+
+	deleteHolidaysStmt, err = db.Preparex(deleteHolidaysSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteHolidaysSQL %v", err)
+	}
+
+}
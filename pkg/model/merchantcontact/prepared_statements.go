@@ -0,0 +1,84 @@
+package merchantcontact
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM merchant_contact
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO merchant_contact (
+	id,
+	merchant_id,
+	role,
+	name,
+	email,
+	phone,
+	created_at
+) VALUES (
+	:id,
+	:merchant_id,
+	:role,
+	:name,
+	:email,
+	:phone,
+	:created_at
+)
+RETURNING *
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE merchant_contact SET
+	role       = :role,
+	name       = :name,
+	email      = :email,
+	phone      = :phone,
+	updated_at = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var deleteRecordStmt *sqlx.NamedStmt
+var deleteRecordSQL = `
+UPDATE merchant_contact SET
+	deleted_at = :deleted_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	deleteRecordStmt, err = db.PrepareNamed(deleteRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
+	}
+
+}
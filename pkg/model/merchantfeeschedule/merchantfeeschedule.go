@@ -0,0 +1,373 @@
+package merchantfeeschedule
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/fees"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID         string         `db:"id"`
+	TenantID   string         `db:"tenant_id"`
+	MerchantID string         `db:"merchant_id"`
+	Currency   string         `db:"currency"`
+	CreatedAt  string         `db:"created_at"`
+	UpdatedAt  sql.NullString `db:"updated_at"`
+	DeletedAt  sql.NullString `db:"deleted_at"`
+}
+
+// TierRecord - amounts are in minor units of the schedule currency
+type TierRecord struct {
+	ID            string          `db:"id"`
+	TenantID      string          `db:"tenant_id"`
+	FeeScheduleID string          `db:"fee_schedule_id"`
+	MinAmount     int64           `db:"min_amount"`
+	Percentage    decimal.Decimal `db:"percentage"`
+	FixedFee      int64           `db:"fixed_fee"`
+	CreatedAt     string          `db:"created_at"`
+}
+
+// EntityType - audit log entity type
+const EntityType = "merchant_fee_schedule"
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// NewTierRecord -
+func (m *Model) NewTierRecord() TierRecord {
+	return TierRecord{}
+}
+
+// Tiers converts tier records for fee calculation
+func Tiers(recs []*TierRecord) []fees.Tier {
+	tiers := []fees.Tier{}
+	for _, rec := range recs {
+		tiers = append(tiers, fees.Tier{
+			MinAmount:  rec.MinAmount,
+			Percentage: rec.Percentage,
+			FixedFee:   rec.FixedFee,
+		})
+	}
+	return tiers
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching merchant fee schedule record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// GetByParam -
+func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt := `
+SELECT *
+FROM merchant_fee_schedule
+WHERE deleted_at IS NULL
+`
+
+	// params
+	for k := range params {
+		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
+	}
+
+	sqlStmt = sqlStmt + "ORDER BY created_at\n"
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	m.DebugStruct("Fetched", recs)
+
+	return recs, rows.Err()
+}
+
+// GetTiers - tiers of a fee schedule ordered by minimum amount
+func (m *Model) GetTiers(feeScheduleID string) ([]*TierRecord, error) {
+
+	// records
+	recs := []*TierRecord{}
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getTiersStmt)
+
+	rows, err := stmt.Queryx(feeScheduleID)
+	if err != nil {
+		log.Error().Msgf("Error querying tiers %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e TierRecord
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	return recs, rows.Err()
+}
+
+// Create - creates a fee schedule with its tiers
+func (m *Model) Create(rec *Record, tiers []*TierRecord) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return err
+	}
+
+	err = m.createTiers(rec.ID, tiers)
+	if err != nil {
+		return err
+	}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationCreate, nil, auditData(rec, tiers))
+}
+
+// Update - updates a fee schedule replacing its tiers
+func (m *Model) Update(rec *Record, tiers []*TierRecord) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// current record for audit
+	cur, err := m.GetByID(rec.ID)
+	if err != nil {
+		return err
+	}
+
+	curTiers, err := m.GetTiers(rec.ID)
+	if err != nil {
+		return err
+	}
+
+	stmt := db.NamedStmt(updateRecordStmt)
+
+	oldUpdatedAt := rec.UpdatedAt
+
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err = stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		rec.UpdatedAt = oldUpdatedAt
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	_, err = db.Stmtx(deleteTiersStmt).Exec(rec.ID)
+	if err != nil {
+		log.Error().Msgf("Error deleting tiers %v", err)
+		return err
+	}
+
+	err = m.createTiers(rec.ID, tiers)
+	if err != nil {
+		return err
+	}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationUpdate, auditData(cur, curTiers), auditData(rec, tiers))
+}
+
+func (m *Model) createTiers(feeScheduleID string, tiers []*TierRecord) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createTierStmt)
+
+	for _, tier := range tiers {
+		tier.ID = util.GetUUID()
+		tier.FeeScheduleID = feeScheduleID
+		tier.CreatedAt = util.GetTime()
+
+		err := stmt.QueryRowx(tier).StructScan(tier)
+		if err != nil {
+			log.Error().Msgf("Error executing tier insert %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete -
+func (m *Model) Delete(id string) error {
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Delete ID %s", id)
+
+	// db
+	db := m.DB
+
+	rec := m.NewRecord()
+	rec.ID = id
+
+	stmt := db.NamedStmt(deleteRecordStmt)
+
+	// deleted at
+	rec.DeletedAt.String = util.GetTime()
+	rec.DeletedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing delete %s", err)
+		return err
+	}
+
+	before := rec
+	before.DeletedAt = sql.NullString{}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationDelete, auditData(&before, nil), auditData(&rec, nil))
+}
+
+// auditData - fee schedule representation recorded in the audit log
+func auditData(rec *Record, tiers []*TierRecord) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":          rec.ID,
+		"merchant_id": rec.MerchantID,
+		"currency":    rec.Currency,
+		"deleted_at":  rec.DeletedAt.String,
+	}
+	if tiers != nil {
+		td := []map[string]interface{}{}
+		for _, tier := range tiers {
+			td = append(td, map[string]interface{}{
+				"min_amount": tier.MinAmount,
+				"percentage": tier.Percentage.String(),
+				"fixed_fee":  tier.FixedFee,
+			})
+		}
+		data["tiers"] = td
+	}
+	return data
+}
+
+// ValidateResult is used for validating against fee schedule config
+type ValidateResult struct {
+	DuplicateCurrency sql.NullBool `db:"duplicate_currency"`
+}
+
+// ValidateRecord - validates properties of a record are valid for creating or updating
+func (m *Model) ValidateRecord(rec *Record) (*ValidateResult, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	log.Debug().Msgf("Validating merchant fee schedule record %v", rec)
+
+	stmt := db.NamedStmt(validateRecordStmt)
+
+	vrec := ValidateResult{}
+
+	err := stmt.QueryRowx(rec).StructScan(&vrec)
+	if err != nil {
+		log.Error().Msgf("Error executing validation query %v", err)
+		return nil, err
+	}
+
+	return &vrec, nil
+}
@@ -0,0 +1,142 @@
+package merchantfeeschedule
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM merchant_fee_schedule
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO merchant_fee_schedule (
+	id,
+	merchant_id,
+	currency,
+	created_at
+) VALUES (
+	:id,
+	:merchant_id,
+	:currency,
+	:created_at
+)
+RETURNING *
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE merchant_fee_schedule SET
+	currency   = :currency,
+	updated_at = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var deleteRecordStmt *sqlx.NamedStmt
+var deleteRecordSQL = `
+UPDATE merchant_fee_schedule SET
+	deleted_at = :deleted_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var getTiersStmt *sqlx.Stmt
+var getTiersSQL = `
+SELECT *
+FROM merchant_fee_tier
+WHERE fee_schedule_id = $1
+ORDER BY min_amount
+`
+
+var createTierStmt *sqlx.NamedStmt
+var createTierSQL = `
+INSERT INTO merchant_fee_tier (
+	id,
+	fee_schedule_id,
+	min_amount,
+	percentage,
+	fixed_fee,
+	created_at
+) VALUES (
+	:id,
+	:fee_schedule_id,
+	:min_amount,
+	:percentage,
+	:fixed_fee,
+	:created_at
+)
+RETURNING *
+`
+
+var deleteTiersStmt *sqlx.Stmt
+var deleteTiersSQL = `
+DELETE FROM merchant_fee_tier
+WHERE fee_schedule_id = $1
+`
+
+var validateRecordStmt *sqlx.NamedStmt
+var validateRecordSQL = `
+SELECT
+(
+	SELECT 1
+	FROM   merchant_fee_schedule
+	WHERE  merchant_id = :merchant_id
+	AND    currency = :currency
+	AND    id::text <> :id
+	AND    deleted_at IS NULL
+) duplicate_currency
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	deleteRecordStmt, err = db.PrepareNamed(deleteRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
+	}
+
+	getTiersStmt, err = db.Preparex(getTiersSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getTiersSQL %v", err)
+	}
+
+	createTierStmt, err = db.PrepareNamed(createTierSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createTierSQL %v", err)
+	}
+
+	deleteTiersStmt, err = db.Preparex(deleteTiersSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteTiersSQL %v", err)
+	}
+
+	validateRecordStmt, err = db.PrepareNamed(validateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare validateRecordSQL %v", err)
+	}
+
+}
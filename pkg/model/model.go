@@ -11,9 +11,10 @@ import (
 
 // Base -
 type Base struct {
-	Env    *env.Env
-	Logger zerolog.Logger
-	DB     *sqlx.Tx
+	Env          *env.Env
+	Logger       zerolog.Logger
+	DB           *sqlx.Tx
+	AuditContext *AuditContext
 }
 
 // Init -
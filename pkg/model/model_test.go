@@ -1 +1,27 @@
 package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditDiff(t *testing.T) {
+	diff, err := AuditDiff(
+		[]byte(`{"name":"a","status":"active","dba_name":"x"}`),
+		[]byte(`{"name":"b","status":"active","country_id":"AU"}`),
+	)
+
+	assert.NoError(t, err)
+	assert.Len(t, diff, 3)
+	assert.Equal(t, &AuditChange{Old: "a", New: "b"}, diff["name"])
+	assert.Equal(t, &AuditChange{Old: "x", New: nil}, diff["dba_name"])
+	assert.Equal(t, &AuditChange{Old: nil, New: "AU"}, diff["country_id"])
+}
+
+func TestAuditDiffCreate(t *testing.T) {
+	diff, err := AuditDiff(nil, []byte(`{"name":"a"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &AuditChange{Old: nil, New: "a"}, diff["name"])
+}
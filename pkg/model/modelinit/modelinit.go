@@ -2,12 +2,61 @@ package modelinit
 
 import (
 	"github.com/jmoiron/sqlx"
+	"github.com/vegh1010/test/pkg/model/apiclient"
+	"github.com/vegh1010/test/pkg/model/country"
+	"github.com/vegh1010/test/pkg/model/currency"
+	"github.com/vegh1010/test/pkg/model/featureflag"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/model/location"
 	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/merchantbusinesshours"
+	"github.com/vegh1010/test/pkg/model/merchantaddress"
+	"github.com/vegh1010/test/pkg/model/merchantbankaccount"
+	"github.com/vegh1010/test/pkg/model/merchantcontact"
+	"github.com/vegh1010/test/pkg/model/merchantfeeschedule"
+	"github.com/vegh1010/test/pkg/model/organisation"
+	"github.com/vegh1010/test/pkg/model/outboxevent"
+	"github.com/vegh1010/test/pkg/model/tenant"
+	"github.com/vegh1010/test/pkg/model/timezone"
+	"github.com/vegh1010/test/pkg/model/webhook"
+	"github.com/vegh1010/test/pkg/model/webhookdelivery"
+	"github.com/vegh1010/test/pkg/util/decimalutil"
 )
 
 // PrepareStatements prepares all of the model's statements.
 func PrepareStatements(db *sqlx.DB) {
 
+	currency.PrepareStatements(db)
 	merchant.PrepareStatements(db)
+	job.PrepareStatements(db)
+	outboxevent.PrepareStatements(db)
+	webhook.PrepareStatements(db)
+	webhookdelivery.PrepareStatements(db)
+	apiclient.PrepareStatements(db)
+	organisation.PrepareStatements(db)
+	location.PrepareStatements(db)
+	merchantaddress.PrepareStatements(db)
+	merchantcontact.PrepareStatements(db)
+	merchantbankaccount.PrepareStatements(db)
+	merchantfeeschedule.PrepareStatements(db)
+	merchantbusinesshours.PrepareStatements(db)
+	tenant.PrepareStatements(db)
+	timezone.PrepareStatements(db)
+	country.PrepareStatements(db)
+	featureflag.PrepareStatements(db)
 
 }
+
+// LoadReferenceData loads reference data kept in memory, currently the
+// currency minor units used by decimalutil.
+func LoadReferenceData() error {
+
+	exps, err := currency.GetExponents()
+	if err != nil {
+		return err
+	}
+
+	decimalutil.SetExponents(exps)
+
+	return nil
+}
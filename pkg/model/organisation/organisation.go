@@ -0,0 +1,351 @@
+package organisation
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID        string         `db:"id"`
+	TenantID  string         `db:"tenant_id"`
+	ParentID  sql.NullString `db:"parent_id"`
+	Name      string         `db:"name"`
+	Status    string         `db:"status"`
+	CreatedAt string         `db:"created_at"`
+	UpdatedAt sql.NullString `db:"updated_at"`
+	DeletedAt sql.NullString `db:"deleted_at"`
+}
+
+// SummaryRecord - number of entities with a status within an
+// organisation's subtree
+type SummaryRecord struct {
+	EntityType string `db:"entity_type"`
+	Status     string `db:"status"`
+	Count      int    `db:"count"`
+}
+
+// Organisation status values
+const (
+	StatusActive     = "active"
+	StatusInactive   = "inactive"
+	StatusTerminated = "terminated"
+)
+
+// EntityType - audit log entity type
+const EntityType = "organisation"
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching organisation record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// GetByParam -
+func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt := `
+SELECT *
+FROM organisation
+WHERE deleted_at IS NULL
+`
+
+	// params
+	for k := range params {
+		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
+	}
+
+	sqlStmt = sqlStmt + "ORDER BY created_at\n"
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	m.DebugStruct("Fetched", recs)
+
+	return recs, rows.Err()
+}
+
+// Create -
+func (m *Model) Create(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// status - initially is always active
+	rec.Status = StatusActive
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return err
+	}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationCreate, nil, auditData(rec))
+}
+
+// Update - terminating an organisation terminates every organisation,
+// merchant and location beneath it
+func (m *Model) Update(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// current record for status changes and audit
+	cur, err := m.GetByID(rec.ID)
+	if err != nil {
+		return err
+	}
+
+	stmt := db.NamedStmt(updateRecordStmt)
+
+	oldUpdatedAt := rec.UpdatedAt
+
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err = stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		rec.UpdatedAt = oldUpdatedAt
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	err = m.Audit(EntityType, rec.ID, model.AuditOperationUpdate, auditData(cur), auditData(rec))
+	if err != nil {
+		return err
+	}
+
+	if rec.Status == StatusTerminated && cur.Status != StatusTerminated {
+		return m.terminateChildren(rec.ID)
+	}
+
+	return nil
+}
+
+// terminateChildren -
+func (m *Model) terminateChildren(id string) error {
+
+	children, err := m.GetByParam(map[string]interface{}{"parent_id": id})
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if child.Status == StatusTerminated {
+			continue
+		}
+		child.Status = StatusTerminated
+		err = m.Update(child)
+		if err != nil {
+			return err
+		}
+	}
+
+	mm, err := merchant.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+	mm.SetAuditContext(m.AuditContext)
+
+	return mm.TerminateByOrganisationID(id)
+}
+
+// Delete -
+func (m *Model) Delete(id string) error {
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Delete ID %s", id)
+
+	// db
+	db := m.DB
+
+	rec := m.NewRecord()
+	rec.ID = id
+
+	stmt := db.NamedStmt(deleteRecordStmt)
+
+	// deleted at
+	rec.DeletedAt.String = util.GetTime()
+	rec.DeletedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing delete %s", err)
+		return err
+	}
+
+	before := rec
+	before.DeletedAt = sql.NullString{}
+
+	return m.Audit(EntityType, rec.ID, model.AuditOperationDelete, auditData(&before), auditData(&rec))
+}
+
+// HasChildren returns whether an organisation has any organisations or
+// merchants beneath it that have not been deleted
+func (m *Model) HasChildren(id string) (bool, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	var has bool
+
+	stmt := db.Stmtx(hasChildrenStmt)
+
+	err := stmt.QueryRowx(id).Scan(&has)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return false, err
+	}
+
+	return has, nil
+}
+
+// IsDescendant returns whether an organisation is the ancestor organisation
+// or is beneath it
+func (m *Model) IsDescendant(id, ancestorID string) (bool, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	var is bool
+
+	stmt := db.Stmtx(isDescendantStmt)
+
+	err := stmt.QueryRowx(ancestorID, id).Scan(&is)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return false, err
+	}
+
+	return is, nil
+}
+
+// GetSummary counts organisations, merchants and locations by status
+// across an organisation's entire subtree, including the organisation
+func (m *Model) GetSummary(id string) ([]*SummaryRecord, error) {
+
+	// records
+	var recs []*SummaryRecord
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getSummaryStmt)
+
+	err := stmt.Select(&recs, id)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return recs, nil
+}
+
+// auditData - organisation representation recorded in the audit log
+func auditData(rec *Record) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         rec.ID,
+		"parent_id":  rec.ParentID.String,
+		"name":       rec.Name,
+		"status":     rec.Status,
+		"deleted_at": rec.DeletedAt.String,
+	}
+}
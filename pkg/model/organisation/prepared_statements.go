@@ -0,0 +1,161 @@
+package organisation
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM organisation
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO organisation (
+	id,
+	parent_id,
+	name,
+	status,
+	created_at
+) VALUES (
+	:id,
+	:parent_id,
+	:name,
+	:status,
+	:created_at
+)
+RETURNING *
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE organisation SET
+	parent_id  = :parent_id,
+	name       = :name,
+	status     = :status,
+	updated_at = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var deleteRecordStmt *sqlx.NamedStmt
+var deleteRecordSQL = `
+UPDATE organisation SET
+	deleted_at = :deleted_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING *
+`
+
+var hasChildrenStmt *sqlx.Stmt
+var hasChildrenSQL = `
+SELECT EXISTS (
+	SELECT 1
+	FROM   organisation
+	WHERE  parent_id = $1
+	AND    deleted_at IS NULL
+) OR EXISTS (
+	SELECT 1
+	FROM   merchant
+	WHERE  organisation_id = $1
+	AND    deleted_at IS NULL
+)
+`
+
+var isDescendantStmt *sqlx.Stmt
+var isDescendantSQL = `
+WITH RECURSIVE subtree AS (
+	SELECT id
+	FROM   organisation
+	WHERE  id = $1
+	UNION
+	SELECT o.id
+	FROM   organisation o
+	JOIN   subtree s ON o.parent_id = s.id
+)
+SELECT EXISTS (
+	SELECT 1
+	FROM   subtree
+	WHERE  id = $2
+)
+`
+
+var getSummaryStmt *sqlx.Stmt
+var getSummarySQL = `
+WITH RECURSIVE subtree AS (
+	SELECT id
+	FROM   organisation
+	WHERE  id = $1
+	AND    deleted_at IS NULL
+	UNION
+	SELECT o.id
+	FROM   organisation o
+	JOIN   subtree s ON o.parent_id = s.id
+	WHERE  o.deleted_at IS NULL
+)
+SELECT 'organisation' AS entity_type, o.status::TEXT AS status, count(*) AS count
+FROM   organisation o
+JOIN   subtree s ON s.id = o.id
+GROUP  BY o.status
+UNION ALL
+SELECT 'merchant' AS entity_type, m.status::TEXT AS status, count(*) AS count
+FROM   merchant m
+JOIN   subtree s ON s.id = m.organisation_id
+WHERE  m.deleted_at IS NULL
+GROUP  BY m.status
+UNION ALL
+SELECT 'location' AS entity_type, l.status::TEXT AS status, count(*) AS count
+FROM   location l
+JOIN   merchant m ON m.id = l.merchant_id
+JOIN   subtree s ON s.id = m.organisation_id
+WHERE  l.deleted_at IS NULL
+AND    m.deleted_at IS NULL
+GROUP  BY l.status
+ORDER  BY 1, 2
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	deleteRecordStmt, err = db.PrepareNamed(deleteRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
+	}
+
+	hasChildrenStmt, err = db.Preparex(hasChildrenSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare hasChildrenSQL %v", err)
+	}
+
+	isDescendantStmt, err = db.Preparex(isDescendantSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare isDescendantSQL %v", err)
+	}
+
+	getSummaryStmt, err = db.Preparex(getSummarySQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getSummarySQL %v", err)
+	}
+
+}
@@ -0,0 +1,181 @@
+package outboxevent
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID            string         `db:"id"`
+	TenantID      string         `db:"tenant_id"`
+	Sequence      int64          `db:"sequence"`
+	AggregateType string         `db:"aggregate_type"`
+	AggregateID   string         `db:"aggregate_id"`
+	EventType     string         `db:"event_type"`
+	Payload       types.JSONText `db:"payload"`
+	ProcessedAt   sql.NullString `db:"processed_at"`
+	CreatedAt     string         `db:"created_at"`
+	UpdatedAt     sql.NullString `db:"updated_at"`
+	DeletedAt     sql.NullString `db:"deleted_at"`
+}
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{
+		Payload: types.JSONText("{}"),
+	}
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching outbox event record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// Write creates an event for an aggregate. Called with the same tx as the
+// change it describes so the event is only published if the change commits.
+func (m *Model) Write(aggregateType, aggregateID, eventType string, payload interface{}) (*Record, error) {
+
+	rec := m.NewRecord()
+	rec.AggregateType = aggregateType
+	rec.AggregateID = aggregateID
+	rec.EventType = eventType
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	rec.Payload = types.JSONText(data)
+
+	err = m.Create(&rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// Create -
+func (m *Model) Create(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	m.DebugStruct("Create ", rec)
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// LockUnprocessed fetches up to limit unprocessed events in sequence order,
+// skipping events locked by another tx.
+func (m *Model) LockUnprocessed(limit int) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(lockUnprocessedStmt)
+
+	rows, err := stmt.Queryx(limit)
+	if err != nil {
+		m.Logger.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	return recs, rows.Err()
+}
+
+// MarkProcessed -
+func (m *Model) MarkProcessed(id string) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(markProcessedStmt)
+
+	_, err := stmt.Exec(id, util.GetTime())
+	if err != nil {
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	return nil
+}
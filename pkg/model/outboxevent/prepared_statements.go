@@ -0,0 +1,90 @@
+package outboxevent
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM outbox_event
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO outbox_event (
+	id,
+	aggregate_type,
+	aggregate_id,
+	event_type,
+	payload,
+	created_at
+) VALUES (
+	:id,
+	:aggregate_type,
+	:aggregate_id,
+	:event_type,
+	:payload,
+	:created_at
+)
+RETURNING
+	id,
+	tenant_id,
+	sequence,
+	aggregate_type,
+	aggregate_id,
+	event_type,
+	payload,
+	processed_at,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+var lockUnprocessedStmt *sqlx.Stmt
+var lockUnprocessedSQL = `
+SELECT *
+FROM outbox_event
+WHERE processed_at IS NULL
+AND deleted_at IS NULL
+ORDER BY sequence
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+var markProcessedStmt *sqlx.Stmt
+var markProcessedSQL = `
+UPDATE outbox_event SET
+	processed_at = $2,
+	updated_at   = $2
+WHERE id = $1
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	lockUnprocessedStmt, err = db.Preparex(lockUnprocessedSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare lockUnprocessedSQL %v", err)
+	}
+
+	markProcessedStmt, err = db.Preparex(markProcessedSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare markProcessedSQL %v", err)
+	}
+
+}
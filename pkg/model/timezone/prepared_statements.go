@@ -0,0 +1,97 @@
+package timezone
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM timezone
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var getCanonicalIDStmt *sqlx.Stmt
+var getCanonicalIDSQL = `
+SELECT COALESCE(canonical_id, id)
+FROM timezone
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+// upsertSQL - zones are passed as parallel arrays so a sync is a single
+// statement, aliases may reference canonical zones inserted with them
+var upsertStmt *sqlx.Stmt
+var upsertSQL = `
+INSERT INTO timezone (
+	id,
+	canonical_id,
+	utc_offset,
+	dst,
+	status,
+	synced_at
+)
+SELECT
+	z.id,
+	z.canonical_id,
+	z.utc_offset,
+	z.dst,
+	'active',
+	$5
+FROM unnest(
+	CAST($1 AS TEXT[]),
+	CAST($2 AS TEXT[]),
+	CAST($3 AS INTEGER[]),
+	CAST($4 AS BOOLEAN[])
+) AS z (id, canonical_id, utc_offset, dst)
+ON CONFLICT (id) DO UPDATE SET
+	canonical_id = EXCLUDED.canonical_id,
+	utc_offset   = EXCLUDED.utc_offset,
+	dst          = EXCLUDED.dst,
+	status       = EXCLUDED.status,
+	synced_at    = EXCLUDED.synced_at,
+	updated_at   = CASE
+		WHEN (timezone.canonical_id, timezone.utc_offset, timezone.dst, timezone.status)
+			IS DISTINCT FROM (EXCLUDED.canonical_id, EXCLUDED.utc_offset, EXCLUDED.dst, EXCLUDED.status)
+		THEN EXCLUDED.synced_at
+		ELSE timezone.updated_at
+	END
+`
+
+var deactivateExceptStmt *sqlx.Stmt
+var deactivateExceptSQL = `
+UPDATE timezone SET
+	status     = 'inactive',
+	updated_at = $2
+WHERE status = 'active'
+AND deleted_at IS NULL
+AND id <> ALL(CAST($1 AS TEXT[]))
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	getCanonicalIDStmt, err = db.Preparex(getCanonicalIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getCanonicalIDSQL %v", err)
+	}
+
+	upsertStmt, err = db.Preparex(upsertSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare upsertSQL %v", err)
+	}
+
+	deactivateExceptStmt, err = db.Preparex(deactivateExceptSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deactivateExceptSQL %v", err)
+	}
+
+}
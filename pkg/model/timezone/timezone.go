@@ -0,0 +1,166 @@
+package timezone
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record - an IANA time zone. Deprecated aliases, such as Asia/Calcutta,
+// name their CanonicalID, Asia/Kolkata.
+type Record struct {
+	ID          string         `db:"id"`
+	CanonicalID sql.NullString `db:"canonical_id"`
+	// UTCOffset - seconds east of UTC when last synced
+	UTCOffset sql.NullInt64 `db:"utc_offset"`
+	// DST - whether the zone observes daylight saving time
+	DST       bool           `db:"dst"`
+	Status    string         `db:"status"`
+	SyncedAt  sql.NullString `db:"synced_at"`
+	CreatedAt string         `db:"created_at"`
+	UpdatedAt sql.NullString `db:"updated_at"`
+	DeletedAt sql.NullString `db:"deleted_at"`
+}
+
+// Status values
+const (
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching timezone record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// CanonicalID returns the canonical zone for an alias, or the ID itself
+// when it is canonical or unknown so validation can reject it
+func (m *Model) CanonicalID(id string) (string, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getCanonicalIDStmt)
+
+	var canonicalID string
+
+	err := stmt.QueryRowx(id).Scan(&canonicalID)
+	if err == sql.ErrNoRows {
+		return id, nil
+	}
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return "", err
+	}
+
+	return canonicalID, nil
+}
+
+// ModelGetter - returns a timezone model, such as a request's model store
+type ModelGetter interface {
+	GetTimezoneModel() (*Model, error)
+}
+
+// Normalise returns the zone to store for a timezone from a request,
+// aliases such as Asia/Calcutta are stored as the canonical zone,
+// Asia/Kolkata
+func Normalise(g ModelGetter, id string) (string, error) {
+
+	m, err := g.GetTimezoneModel()
+	if err != nil {
+		return "", err
+	}
+
+	return m.CanonicalID(id)
+}
+
+// Sync upserts zones as active, then deactivates every other zone,
+// returning how many were deactivated
+func (m *Model) Sync(recs []*Record) (int64, error) {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	ids := make([]string, len(recs))
+	canonicalIDs := make([]sql.NullString, len(recs))
+	offsets := make([]int64, len(recs))
+	dsts := make([]bool, len(recs))
+
+	for i, rec := range recs {
+		ids[i] = rec.ID
+		canonicalIDs[i] = rec.CanonicalID
+		offsets[i] = rec.UTCOffset.Int64
+		dsts[i] = rec.DST
+	}
+
+	now := util.GetTime()
+
+	_, err := db.Stmtx(upsertStmt).Exec(pq.Array(ids), pq.Array(canonicalIDs), pq.Array(offsets), pq.Array(dsts), now)
+	if err != nil {
+		log.Error().Msgf("Error executing upsert %v", err)
+		return 0, err
+	}
+
+	res, err := db.Stmtx(deactivateExceptStmt).Exec(pq.Array(ids), now)
+	if err != nil {
+		log.Error().Msgf("Error executing update %v", err)
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
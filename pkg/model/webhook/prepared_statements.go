@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getByIDStmt *sqlx.Stmt
+var getByIDSQL = `
+SELECT *
+FROM webhook
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+var lockByIDStmt *sqlx.Stmt
+var lockByIDSQL = `
+SELECT *
+FROM webhook
+WHERE id = $1
+AND deleted_at IS NULL
+FOR UPDATE SKIP LOCKED
+`
+
+var nextDeliverySequenceStmt *sqlx.Stmt
+var nextDeliverySequenceSQL = `
+UPDATE webhook SET
+	delivery_sequence = delivery_sequence + 1
+WHERE id = $1
+RETURNING delivery_sequence
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO webhook (
+	id,
+	url,
+	description,
+	secret,
+	event_types,
+	status,
+	created_at
+) VALUES (
+	:id,
+	:url,
+	:description,
+	:secret,
+	:event_types,
+	:status,
+	:created_at
+)
+RETURNING
+	id,
+	tenant_id,
+	url,
+	description,
+	secret,
+	event_types,
+	status,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE webhook SET
+	url            = :url,
+	description    = :description,
+	event_types    = :event_types,
+	status         = :status,
+	updated_at     = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING
+	id,
+	tenant_id,
+	url,
+	description,
+	secret,
+	event_types,
+	status,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+var deleteRecordStmt *sqlx.NamedStmt
+var deleteRecordSQL = `
+UPDATE webhook SET
+	deleted_at = :deleted_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING
+	id,
+	tenant_id,
+	url,
+	description,
+	secret,
+	event_types,
+	status,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getByIDStmt, err = db.Preparex(getByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getByIDSQL %v", err)
+	}
+
+	lockByIDStmt, err = db.Preparex(lockByIDSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare lockByIDSQL %v", err)
+	}
+
+	nextDeliverySequenceStmt, err = db.Preparex(nextDeliverySequenceSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare nextDeliverySequenceSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+	deleteRecordStmt, err = db.PrepareNamed(deleteRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare deleteRecordSQL %v", err)
+	}
+
+}
@@ -0,0 +1,271 @@
+package webhook
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID               string         `db:"id"`
+	TenantID         string         `db:"tenant_id"`
+	URL              string         `db:"url"`
+	Description      string         `db:"description"`
+	Secret           string         `db:"secret"`
+	EventTypes       pq.StringArray `db:"event_types"`
+	Status           string         `db:"status"`
+	DeliverySequence int64          `db:"delivery_sequence"`
+	CreatedAt        string         `db:"created_at"`
+	UpdatedAt        sql.NullString `db:"updated_at"`
+	DeletedAt        sql.NullString `db:"deleted_at"`
+}
+
+// Webhook status values
+const (
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+// EventTypeAll subscribes a webhook to every event type
+const EventTypeAll = "*"
+
+// Subscribed returns whether the webhook should receive an event type
+func (r *Record) Subscribed(eventType string) bool {
+	if len(r.EventTypes) == 0 {
+		return true
+	}
+	return util.StringInSlice(EventTypeAll, r.EventTypes) || util.StringInSlice(eventType, r.EventTypes)
+}
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{
+		EventTypes: pq.StringArray{},
+	}
+}
+
+// GetByID -
+func (m *Model) GetByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+	rec.ID = id
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Fetching webhook record by ID %s", id)
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getByIDStmt)
+
+	err := stmt.QueryRowx(rec.ID).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// LockByID fetches and locks a webhook, returning sql.ErrNoRows when the
+// webhook is already locked by another tx.
+func (m *Model) LockByID(id string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(lockByIDStmt)
+
+	err := stmt.QueryRowx(id).StructScan(&rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// NextDeliverySequence returns the sequence for a new delivery to a
+// webhook. The webhook row stays locked until the tx ends, so deliveries
+// are numbered in the order their txs commit.
+func (m *Model) NextDeliverySequence(id string) (int64, error) {
+
+	var seq int64
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(nextDeliverySequenceStmt)
+
+	err := stmt.QueryRowx(id).Scan(&seq)
+	if err != nil {
+		m.Logger.Error().Msgf("Error executing update %v", err)
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// GetByParam -
+func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt := `
+SELECT *
+FROM webhook
+WHERE deleted_at IS NULL
+`
+
+	// params
+	for k := range params {
+		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
+	}
+
+	sqlStmt = sqlStmt + "ORDER BY created_at\n"
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	m.DebugStruct("Fetched", recs)
+
+	return recs, rows.Err()
+}
+
+// Create -
+func (m *Model) Create(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// secret - generated, never provided by the client
+	rec.Secret = util.GenerateToken()
+
+	// status - initially is always active
+	rec.Status = StatusActive
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		log.Error().Msgf("Error executing insert %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Update -
+func (m *Model) Update(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(updateRecordStmt)
+
+	oldUpdatedAt := rec.UpdatedAt
+
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		rec.UpdatedAt = oldUpdatedAt
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete -
+func (m *Model) Delete(id string) error {
+
+	// log
+	log := m.Logger
+
+	log.Debug().Msgf("Delete ID %s", id)
+
+	// db
+	db := m.DB
+
+	rec := m.NewRecord()
+	rec.ID = id
+
+	stmt := db.NamedStmt(deleteRecordStmt)
+
+	// deleted at
+	rec.DeletedAt.String = util.GetTime()
+	rec.DeletedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(&rec)
+	if err != nil {
+		log.Error().Msgf("Error executing delete %s", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package webhookdelivery
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var getDueWebhookIDsStmt *sqlx.Stmt
+var getDueWebhookIDsSQL = `
+SELECT d.webhook_id
+FROM webhook_delivery d
+WHERE d.status = 'pending'
+AND d.deleted_at IS NULL
+AND d.next_attempt_at <= $1
+AND d.sequence = (
+	SELECT min(p.sequence)
+	FROM   webhook_delivery p
+	WHERE  p.webhook_id = d.webhook_id
+	AND    p.status = 'pending'
+	AND    p.deleted_at IS NULL
+)
+`
+
+var getNextDueStmt *sqlx.Stmt
+var getNextDueSQL = `
+SELECT *
+FROM (
+	SELECT *
+	FROM webhook_delivery
+	WHERE webhook_id = $1
+	AND status = 'pending'
+	AND deleted_at IS NULL
+	ORDER BY sequence
+	LIMIT 1
+) d
+WHERE d.next_attempt_at <= $2
+`
+
+var createRecordStmt *sqlx.NamedStmt
+var createRecordSQL = `
+INSERT INTO webhook_delivery (
+	id,
+	tenant_id,
+	webhook_id,
+	outbox_event_id,
+	sequence,
+	event_sequence,
+	event_type,
+	status,
+	attempts,
+	next_attempt_at,
+	created_at
+) VALUES (
+	:id,
+	:tenant_id,
+	:webhook_id,
+	:outbox_event_id,
+	:sequence,
+	:event_sequence,
+	:event_type,
+	:status,
+	:attempts,
+	:next_attempt_at,
+	:created_at
+)
+ON CONFLICT (webhook_id, outbox_event_id) DO NOTHING
+RETURNING
+	id,
+	tenant_id,
+	webhook_id,
+	outbox_event_id,
+	sequence,
+	event_sequence,
+	event_type,
+	status,
+	attempts,
+	next_attempt_at,
+	response_status,
+	response_body,
+	last_error,
+	delivered_at,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+var updateRecordStmt *sqlx.NamedStmt
+var updateRecordSQL = `
+UPDATE webhook_delivery SET
+	status          = :status,
+	attempts        = :attempts,
+	next_attempt_at = :next_attempt_at,
+	response_status = :response_status,
+	response_body   = :response_body,
+	last_error      = :last_error,
+	delivered_at    = :delivered_at,
+	updated_at      = :updated_at
+WHERE id = :id
+AND deleted_at IS NULL
+RETURNING
+	id,
+	tenant_id,
+	webhook_id,
+	outbox_event_id,
+	sequence,
+	event_sequence,
+	event_type,
+	status,
+	attempts,
+	next_attempt_at,
+	response_status,
+	response_body,
+	last_error,
+	delivered_at,
+	created_at,
+	updated_at,
+	deleted_at
+`
+
+// PrepareStatements prepares sql statements
+func PrepareStatements(db *sqlx.DB) {
+	var err error
+
+	getDueWebhookIDsStmt, err = db.Preparex(getDueWebhookIDsSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getDueWebhookIDsSQL %v", err)
+	}
+
+	getNextDueStmt, err = db.Preparex(getNextDueSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare getNextDueSQL %v", err)
+	}
+
+	createRecordStmt, err = db.PrepareNamed(createRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare createRecordSQL %v", err)
+	}
+
+	updateRecordStmt, err = db.PrepareNamed(updateRecordSQL)
+	if err != nil {
+		log.Fatal().Msgf("Failed to prepare updateRecordSQL %v", err)
+	}
+
+}
@@ -0,0 +1,212 @@
+package webhookdelivery
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// Record -
+type Record struct {
+	ID             string         `db:"id"`
+	TenantID       string         `db:"tenant_id"`
+	WebhookID      string         `db:"webhook_id"`
+	OutboxEventID  string         `db:"outbox_event_id"`
+	Sequence       int64          `db:"sequence"`
+	EventSequence  int64          `db:"event_sequence"`
+	EventType      string         `db:"event_type"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  string         `db:"next_attempt_at"`
+	ResponseStatus sql.NullInt64  `db:"response_status"`
+	ResponseBody   sql.NullString `db:"response_body"`
+	LastError      sql.NullString `db:"last_error"`
+	DeliveredAt    sql.NullString `db:"delivered_at"`
+	CreatedAt      string         `db:"created_at"`
+	UpdatedAt      sql.NullString `db:"updated_at"`
+	DeletedAt      sql.NullString `db:"deleted_at"`
+}
+
+// Delivery status values
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Model -
+type Model struct {
+	model.Base
+}
+
+// NewModel -
+func NewModel(e *env.Env, l zerolog.Logger, d *sqlx.Tx) (*Model, error) {
+	m := Model{
+		model.Base{
+			DB:     d,
+			Env:    e,
+			Logger: l,
+		},
+	}
+	err := m.Init()
+	return &m, err
+}
+
+// NewRecord -
+func (m *Model) NewRecord() Record {
+	return Record{}
+}
+
+// GetByParam -
+func (m *Model) GetByParam(params map[string]interface{}) ([]*Record, error) {
+
+	// records
+	var recs []*Record
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	// sqlStmt
+	sqlStmt := `
+SELECT *
+FROM webhook_delivery
+WHERE deleted_at IS NULL
+`
+
+	// params
+	for k := range params {
+		sqlStmt = sqlStmt + fmt.Sprintf("AND %s = :%s\n", k, k)
+	}
+
+	sqlStmt = sqlStmt + "ORDER BY sequence DESC\n"
+
+	rows, err := db.NamedQuery(sqlStmt, params)
+	if err != nil {
+		log.Error().Msgf("Error querying row %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Record
+		err = rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, &e)
+	}
+
+	return recs, rows.Err()
+}
+
+// GetDueWebhookIDs returns the webhooks whose oldest pending delivery is
+// due to be attempted.
+func (m *Model) GetDueWebhookIDs() ([]string, error) {
+
+	var ids []string
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getDueWebhookIDsStmt)
+
+	err := stmt.Select(&ids, util.GetTime())
+	if err != nil {
+		m.Logger.Error().Msgf("Error executing select %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetNextDue returns a webhook's oldest pending delivery when it is due
+// to be attempted, sql.ErrNoRows when there is none or it is not due yet.
+// Deliveries are attempted strictly in sequence per webhook.
+func (m *Model) GetNextDue(webhookID string) (*Record, error) {
+
+	// record
+	rec := m.NewRecord()
+
+	// db
+	db := m.DB
+
+	stmt := db.Stmtx(getNextDueStmt)
+
+	err := stmt.QueryRowx(webhookID, util.GetTime()).StructScan(&rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// Create -
+//
+// Creating a delivery that already exists for the webhook and event is a
+// no-op and returns sql.ErrNoRows.
+func (m *Model) Create(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(createRecordStmt)
+
+	// id
+	rec.ID = util.GetUUID()
+
+	// status - initially is always pending
+	rec.Status = StatusPending
+
+	// created at
+	rec.CreatedAt = util.GetTime()
+
+	// attempt immediately
+	rec.NextAttemptAt = rec.CreatedAt
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error().Msgf("Error executing insert %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Update -
+func (m *Model) Update(rec *Record) error {
+
+	// log
+	log := m.Logger
+
+	// db
+	db := m.DB
+
+	stmt := db.NamedStmt(updateRecordStmt)
+
+	oldUpdatedAt := rec.UpdatedAt
+
+	rec.UpdatedAt.String = util.GetTime()
+	rec.UpdatedAt.Valid = true
+
+	err := stmt.QueryRowx(rec).StructScan(rec)
+	if err != nil {
+		rec.UpdatedAt = oldUpdatedAt
+		log.Error().Msgf("Error executing update %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -6,7 +6,25 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog"
 	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model"
+	"github.com/vegh1010/test/pkg/model/apiclient"
+	"github.com/vegh1010/test/pkg/model/auditlog"
+	"github.com/vegh1010/test/pkg/model/country"
+	"github.com/vegh1010/test/pkg/model/currency"
+	"github.com/vegh1010/test/pkg/model/featureflag"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/model/location"
 	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/merchantaddress"
+	"github.com/vegh1010/test/pkg/model/merchantbankaccount"
+	"github.com/vegh1010/test/pkg/model/merchantbusinesshours"
+	"github.com/vegh1010/test/pkg/model/merchantcontact"
+	"github.com/vegh1010/test/pkg/model/merchantfeeschedule"
+	"github.com/vegh1010/test/pkg/model/organisation"
+	"github.com/vegh1010/test/pkg/model/outboxevent"
+	"github.com/vegh1010/test/pkg/model/timezone"
+	"github.com/vegh1010/test/pkg/model/webhook"
+	"github.com/vegh1010/test/pkg/model/webhookdelivery"
 )
 
 // ModelStore - contains a map of model structs
@@ -42,12 +60,112 @@ func (m *ModelStore) init() error {
 
 	// models
 	m.models["merchant"], err = merchant.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["job"], err = job.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["outboxevent"], err = outboxevent.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["webhook"], err = webhook.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["webhookdelivery"], err = webhookdelivery.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["apiclient"], err = apiclient.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["auditlog"], err = auditlog.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["organisation"], err = organisation.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["location"], err = location.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["merchantaddress"], err = merchantaddress.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["merchantcontact"], err = merchantcontact.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["merchantbankaccount"], err = merchantbankaccount.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["merchantfeeschedule"], err = merchantfeeschedule.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["merchantbusinesshours"], err = merchantbusinesshours.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["currency"], err = currency.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["country"], err = country.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["featureflag"], err = featureflag.NewModel(m.Env, m.Logger, m.DB)
+	if err != nil {
+		return err
+	}
+
+	m.models["timezone"], err = timezone.NewModel(m.Env, m.Logger, m.DB)
 
 	log.Debug().Msg("Done Initializing models")
 
 	return err
 }
 
+// auditable - implemented by all models through model.Base
+type auditable interface {
+	SetAuditContext(a *model.AuditContext)
+}
+
+// SetAuditContext sets who changes made through any model in the store
+// are attributed to in the audit log
+func (m *ModelStore) SetAuditContext(a *model.AuditContext) {
+	for _, mod := range m.models {
+		if am, ok := mod.(auditable); ok {
+			am.SetAuditContext(a)
+		}
+	}
+}
+
 // GetMerchantModel -
 func (m *ModelStore) GetMerchantModel() (*merchant.Model, error) {
 
@@ -58,3 +176,190 @@ func (m *ModelStore) GetMerchantModel() (*merchant.Model, error) {
 
 	return model.(*merchant.Model), nil
 }
+
+// GetJobModel -
+func (m *ModelStore) GetJobModel() (*job.Model, error) {
+
+	model := m.models["job"]
+	if model == nil {
+		return nil, errors.New("Job model does not exist")
+	}
+
+	return model.(*job.Model), nil
+}
+
+// GetOutboxEventModel -
+func (m *ModelStore) GetOutboxEventModel() (*outboxevent.Model, error) {
+
+	model := m.models["outboxevent"]
+	if model == nil {
+		return nil, errors.New("Outbox event model does not exist")
+	}
+
+	return model.(*outboxevent.Model), nil
+}
+
+// GetWebhookModel -
+func (m *ModelStore) GetWebhookModel() (*webhook.Model, error) {
+
+	model := m.models["webhook"]
+	if model == nil {
+		return nil, errors.New("Webhook model does not exist")
+	}
+
+	return model.(*webhook.Model), nil
+}
+
+// GetWebhookDeliveryModel -
+func (m *ModelStore) GetWebhookDeliveryModel() (*webhookdelivery.Model, error) {
+
+	model := m.models["webhookdelivery"]
+	if model == nil {
+		return nil, errors.New("Webhook delivery model does not exist")
+	}
+
+	return model.(*webhookdelivery.Model), nil
+}
+
+// GetAPIClientModel -
+func (m *ModelStore) GetAPIClientModel() (*apiclient.Model, error) {
+
+	model := m.models["apiclient"]
+	if model == nil {
+		return nil, errors.New("API client model does not exist")
+	}
+
+	return model.(*apiclient.Model), nil
+}
+
+// GetAuditLogModel -
+func (m *ModelStore) GetAuditLogModel() (*auditlog.Model, error) {
+
+	model := m.models["auditlog"]
+	if model == nil {
+		return nil, errors.New("Audit log model does not exist")
+	}
+
+	return model.(*auditlog.Model), nil
+}
+
+// GetOrganisationModel -
+func (m *ModelStore) GetOrganisationModel() (*organisation.Model, error) {
+
+	model := m.models["organisation"]
+	if model == nil {
+		return nil, errors.New("Organisation model does not exist")
+	}
+
+	return model.(*organisation.Model), nil
+}
+
+// GetLocationModel -
+func (m *ModelStore) GetLocationModel() (*location.Model, error) {
+
+	model := m.models["location"]
+	if model == nil {
+		return nil, errors.New("Location model does not exist")
+	}
+
+	return model.(*location.Model), nil
+}
+
+// GetMerchantAddressModel -
+func (m *ModelStore) GetMerchantAddressModel() (*merchantaddress.Model, error) {
+
+	model := m.models["merchantaddress"]
+	if model == nil {
+		return nil, errors.New("Merchant address model does not exist")
+	}
+
+	return model.(*merchantaddress.Model), nil
+}
+
+// GetMerchantContactModel -
+func (m *ModelStore) GetMerchantContactModel() (*merchantcontact.Model, error) {
+
+	model := m.models["merchantcontact"]
+	if model == nil {
+		return nil, errors.New("Merchant contact model does not exist")
+	}
+
+	return model.(*merchantcontact.Model), nil
+}
+
+// GetMerchantBankAccountModel -
+func (m *ModelStore) GetMerchantBankAccountModel() (*merchantbankaccount.Model, error) {
+
+	model := m.models["merchantbankaccount"]
+	if model == nil {
+		return nil, errors.New("Merchant bank account model does not exist")
+	}
+
+	return model.(*merchantbankaccount.Model), nil
+}
+
+// GetMerchantFeeScheduleModel -
+func (m *ModelStore) GetMerchantFeeScheduleModel() (*merchantfeeschedule.Model, error) {
+
+	model := m.models["merchantfeeschedule"]
+	if model == nil {
+		return nil, errors.New("Merchant fee schedule model does not exist")
+	}
+
+	return model.(*merchantfeeschedule.Model), nil
+}
+
+// GetMerchantBusinessHoursModel -
+func (m *ModelStore) GetMerchantBusinessHoursModel() (*merchantbusinesshours.Model, error) {
+
+	model := m.models["merchantbusinesshours"]
+	if model == nil {
+		return nil, errors.New("Merchant business hours model does not exist")
+	}
+
+	return model.(*merchantbusinesshours.Model), nil
+}
+
+// GetCurrencyModel -
+func (m *ModelStore) GetCurrencyModel() (*currency.Model, error) {
+
+	model := m.models["currency"]
+	if model == nil {
+		return nil, errors.New("Currency model does not exist")
+	}
+
+	return model.(*currency.Model), nil
+}
+
+// GetCountryModel -
+func (m *ModelStore) GetCountryModel() (*country.Model, error) {
+
+	model := m.models["country"]
+	if model == nil {
+		return nil, errors.New("Country model does not exist")
+	}
+
+	return model.(*country.Model), nil
+}
+
+// GetFeatureFlagModel -
+func (m *ModelStore) GetFeatureFlagModel() (*featureflag.Model, error) {
+
+	model := m.models["featureflag"]
+	if model == nil {
+		return nil, errors.New("Feature flag model does not exist")
+	}
+
+	return model.(*featureflag.Model), nil
+}
+
+// GetTimezoneModel -
+func (m *ModelStore) GetTimezoneModel() (*timezone.Model, error) {
+
+	model := m.models["timezone"]
+	if model == nil {
+		return nil, errors.New("Timezone model does not exist")
+	}
+
+	return model.(*timezone.Model), nil
+}
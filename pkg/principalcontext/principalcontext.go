@@ -0,0 +1,62 @@
+package principalcontext
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type keyType string
+
+// Key -
+const Key keyType = "PrincipalContext"
+
+// Principal roles
+const (
+	RoleAdmin    = "admin"
+	RoleUser     = "user"
+	RoleOperator = "operator"
+)
+
+// Principal - the authenticated caller of a request
+type Principal struct {
+	ID       string
+	TenantID string
+	Name     string
+	Role     string
+	// RateLimit - the API client's own rate limit, when it has one
+	RateLimit string
+}
+
+// IsAdmin -
+func (p *Principal) IsAdmin() bool {
+	return p != nil && p.Role == RoleAdmin
+}
+
+// IsOperator -
+func (p *Principal) IsOperator() bool {
+	return p != nil && p.Role == RoleOperator
+}
+
+// ErrPrincipalContextEmpty -
+var ErrPrincipalContextEmpty = errors.New("Could not find PrincipalContext : context empty")
+
+// GetContext returns the current principal
+func GetContext(r *http.Request) (*Principal, error) {
+	ctx := r.Context().Value(Key)
+	if ctx == nil {
+		return nil, ErrPrincipalContextEmpty
+	}
+	p := ctx.(*Principal)
+	return p, nil
+}
+
+// SetContext for current principal
+func SetContext(r *http.Request, p *Principal) *http.Request {
+
+	ctx := context.WithValue(r.Context(), Key, p)
+
+	r = r.WithContext(ctx)
+
+	return r
+}
@@ -0,0 +1,49 @@
+// Package reload runs a function when the process is asked to reload its
+// configuration, on SIGHUP, and optionally on an interval, so secrets,
+// log levels and other settings can change without a restart.
+package reload
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Watch calls reload on SIGHUP, and every interval when it is not zero,
+// until the returned stop function is called
+func Watch(interval time.Duration, reload func()) (stop func()) {
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	var ticker *time.Ticker
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		tick = ticker.C
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-hup:
+				reload()
+			case <-tick:
+				reload()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(hup)
+		if ticker != nil {
+			ticker.Stop()
+		}
+		close(done)
+	}
+}
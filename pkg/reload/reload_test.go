@@ -0,0 +1,30 @@
+package reload
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+
+	reloads := make(chan struct{}, 1)
+	stop := Watch(0, func() {
+		reloads <- struct{}{}
+	})
+	defer stop()
+
+	err := syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected reload on SIGHUP")
+	}
+}
@@ -0,0 +1,30 @@
+package requestidcontext
+
+import (
+	"context"
+	"net/http"
+)
+
+type keyType string
+
+// Key -
+const Key keyType = "RequestIDContext"
+
+// GetContext returns the current request ID, empty when not set
+func GetContext(r *http.Request) string {
+	ctx := r.Context().Value(Key)
+	if ctx == nil {
+		return ""
+	}
+	return ctx.(string)
+}
+
+// SetContext for current request ID
+func SetContext(r *http.Request, id string) *http.Request {
+
+	ctx := context.WithValue(r.Context(), Key, id)
+
+	r = r.WithContext(ctx)
+
+	return r
+}
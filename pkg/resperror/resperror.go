@@ -9,6 +9,11 @@ const (
 	ErrUnknownValidationErr = "Unknown validation error"
 	ErrNotFoundTitle        = "Not Found"
 	ErrNotFoundDetail       = "Resource Not Found"
+	ErrUnauthenticatedTitle = "Unauthenticated"
+	ErrForbiddenTitle       = "Forbidden"
+	ErrRateLimitedTitle     = "Too Many Requests"
+	ErrUnsupportedMediaType = "Unsupported Media Type"
+	ErrRequestTooLarge      = "Request Entity Too Large"
 	ErrJSONSyntax           = "JSON Syntax Error"
 )
 
@@ -20,9 +25,11 @@ const (
 	ErrInvalidTimestampFormat = " timestamp has an invalid format - Must be formatted in RFC3339 - i.e. 2006-01-02T15:04:05Z"
 	ErrInvalidFloatFormat     = " has an invalid format - Must be 2 decimal places - i.e. 99.99"
 	ErrIsInvalidInteger       = " must be a valid integer"
+	ErrMustBe                 = " must be "
 
 	// Prefix
-	ErrIsInvalid = "Invalid value for "
+	ErrIsInvalid    = "Invalid value for "
+	ErrUnknownField = "Unknown field "
 )
 
 // Error codes.
@@ -33,6 +40,21 @@ const (
 	// ErrCodeNotFound - For a resource not found error code.
 	ErrCodeNotFound = 2
 
+	// ErrCodeUnauthenticated - For a missing or invalid API key.
+	ErrCodeUnauthenticated = 3
+
+	// ErrCodeForbidden - For a principal without access to a resource.
+	ErrCodeForbidden = 4
+
+	// ErrCodeRateLimited - For a client that has exceeded its rate limit.
+	ErrCodeRateLimited = 5
+
+	// ErrCodeUnsupportedMediaType - For a request body that is not JSON.
+	ErrCodeUnsupportedMediaType = 6
+
+	// ErrCodeRequestTooLarge - For a request body over the size limit.
+	ErrCodeRequestTooLarge = 7
+
 	// ErrorCodeValidation - For an unknown validation code.
 	ErrCodeValidation = 100
 
@@ -44,12 +66,58 @@ const (
 	ErrCodeBadUUIDFormat        = 105
 	ErrCodeBadFloatFormat       = 106
 	ErrCodeInvalidIntegerFormat = 107
+	ErrCodeInvalidType          = 108
+	ErrCodeUnknownField         = 109
 
 	// Merchant codes.
 	ErrCodeInvalidCountry                     = 301
 	ErrCodeInvalidTimezone                    = 302
 	ErrCodeDuplicateClientRef                 = 303
 	ErrCodeTerminatedMerchantCannotBeModified = 304
+	ErrCodeInvalidOrganisation                = 305
+	ErrCodeInvalidAddressType                 = 306
+	ErrCodeInvalidContactRole                 = 307
+	ErrCodeInvalidEmail                       = 308
+	ErrCodeInvalidPhone                       = 309
+	ErrCodeUnsupportedCurrency                = 310
+	ErrCodeInvalidRegion                      = 311
+
+	// Webhook codes.
+	ErrCodeInvalidWebhookURL       = 401
+	ErrCodeInvalidWebhookEventType = 402
+	ErrCodeInvalidWebhookStatus    = 403
+
+	// Organisation codes.
+	ErrCodeInvalidOrganisationParent              = 501
+	ErrCodeInvalidOrganisationStatus              = 502
+	ErrCodeTerminatedOrganisationCannotBeModified = 503
+	ErrCodeOrganisationHasChildren                = 504
+
+	// Location codes.
+	ErrCodeInvalidLocationStatus              = 601
+	ErrCodeTerminatedLocationCannotBeModified = 602
+
+	// Bank account codes.
+	ErrCodeInvalidCurrency      = 701
+	ErrCodeInvalidIBAN          = 702
+	ErrCodeInvalidRoutingNumber = 703
+	ErrCodeInvalidBSB           = 704
+	ErrCodeInvalidAccountNumber = 705
+
+	// Fee schedule codes.
+	ErrCodeDuplicateFeeSchedule   = 801
+	ErrCodeInvalidFeeTiers        = 802
+	ErrCodeInvalidAmountPrecision = 803
+	ErrCodeInvalidPercentage      = 804
+	ErrCodeInvalidAmount          = 805
+
+	// Business hours codes.
+	ErrCodeInvalidBusinessHours = 901
+	ErrCodeInvalidHoliday       = 902
+	ErrCodeUnknownTimezone      = 903
+
+	// Admin codes.
+	ErrCodeInvalidLogLevel = 1001
 )
 
 // IsValidationErr -
@@ -127,6 +195,27 @@ func ValidationInvalidIntegerFormat(field string) *Data {
 	}
 }
 
+// ValidationInvalidType is a helper function for constructing a validation
+// error for a field with a JSON value of the wrong type, such as a number
+// for a string field.
+func ValidationInvalidType(field, expected string) *Data {
+	return &Data{
+		Code:   ErrCodeInvalidType,
+		Title:  ErrValidation,
+		Detail: field + ErrMustBe + expected,
+	}
+}
+
+// ValidationUnknownField is a helper function for constructing a validation
+// error for a field that is not part of the request.
+func ValidationUnknownField(field string) *Data {
+	return &Data{
+		Code:   ErrCodeUnknownField,
+		Title:  ErrValidation,
+		Detail: ErrUnknownField + field,
+	}
+}
+
 // TODO: Create ValidationInvalidWithDetails helper function?
 
 // ValidationInvalidUUID4 is a helper function for constructing a validation
@@ -176,6 +265,41 @@ var ErrorNotFound = &Data{
 	Detail: ErrNotFoundDetail,
 }
 
+// ErrorUnauthenticated -
+var ErrorUnauthenticated = &Data{
+	Code:   ErrCodeUnauthenticated,
+	Title:  ErrUnauthenticatedTitle,
+	Detail: "A valid API key is required",
+}
+
+// ErrorForbidden -
+var ErrorForbidden = &Data{
+	Code:   ErrCodeForbidden,
+	Title:  ErrForbiddenTitle,
+	Detail: "Not permitted to access this resource",
+}
+
+// ErrorRateLimited -
+var ErrorRateLimited = &Data{
+	Code:   ErrCodeRateLimited,
+	Title:  ErrRateLimitedTitle,
+	Detail: "Rate limit exceeded, retry after the time given in the Retry-After header",
+}
+
+// ErrorUnsupportedMediaType -
+var ErrorUnsupportedMediaType = &Data{
+	Code:   ErrCodeUnsupportedMediaType,
+	Title:  ErrUnsupportedMediaType,
+	Detail: "Request body must be sent with Content-Type application/json",
+}
+
+// ErrorRequestTooLarge -
+var ErrorRequestTooLarge = &Data{
+	Code:   ErrCodeRequestTooLarge,
+	Title:  ErrRequestTooLarge,
+	Detail: "Request body is larger than the maximum size",
+}
+
 // ErrorUnknownValidation -
 var ErrorUnknownValidation = &Data{
 	Code:   ErrCodeValidation,
@@ -204,5 +328,215 @@ var ErrTerminatedMerchantCannotBeModified = &Data{
 	Detail: "Terminated merchants cannot be modified",
 }
 
+// ErrorInvalidOrganisation - Merchant
+var ErrorInvalidOrganisation = &Data{
+	Code:   ErrCodeInvalidOrganisation,
+	Title:  ErrValidation,
+	Detail: "Field organisation value is not an existing organisation that has not been terminated",
+}
+
+// ErrorInvalidAddressType - Merchant address
+var ErrorInvalidAddressType = &Data{
+	Code:   ErrCodeInvalidAddressType,
+	Title:  ErrValidation,
+	Detail: "Field type must be one of registered, trading or postal",
+}
+
+// ErrorInvalidContactRole - Merchant contact
+var ErrorInvalidContactRole = &Data{
+	Code:   ErrCodeInvalidContactRole,
+	Title:  ErrValidation,
+	Detail: "Field role must be one of billing, technical or legal",
+}
+
+// ErrorInvalidEmail - Merchant contact
+var ErrorInvalidEmail = &Data{
+	Code:   ErrCodeInvalidEmail,
+	Title:  ErrValidation,
+	Detail: "Field email must be a valid email address",
+}
+
+// ErrorInvalidPhone - Merchant contact
+var ErrorInvalidPhone = &Data{
+	Code:   ErrCodeInvalidPhone,
+	Title:  ErrValidation,
+	Detail: "Field phone must be an international number in E.164 format",
+}
+
+// ErrorUnsupportedCurrency - Merchant
+var ErrorUnsupportedCurrency = &Data{
+	Code:   ErrCodeUnsupportedCurrency,
+	Title:  ErrValidation,
+	Detail: "Field currency is not one of the merchant's currencies",
+}
+
+// ErrorInvalidRegion - Merchant
+var ErrorInvalidRegion = &Data{
+	Code:   ErrCodeInvalidRegion,
+	Title:  ErrValidation,
+	Detail: "Field region value is not a subdivision of the address country",
+}
+
+// ErrorInvalidWebhookURL - Webhook
+var ErrorInvalidWebhookURL = &Data{
+	Code:   ErrCodeInvalidWebhookURL,
+	Title:  ErrValidation,
+	Detail: "Field url must be an absolute http or https URL",
+}
+
+// ErrorInvalidWebhookEventType - Webhook
+var ErrorInvalidWebhookEventType = &Data{
+	Code:   ErrCodeInvalidWebhookEventType,
+	Title:  ErrValidation,
+	Detail: "Field event_types contains an unknown event type",
+}
+
+// ErrorInvalidWebhookStatus - Webhook
+var ErrorInvalidWebhookStatus = &Data{
+	Code:   ErrCodeInvalidWebhookStatus,
+	Title:  ErrValidation,
+	Detail: "Field status must be one of active or inactive",
+}
+
+// ErrorInvalidOrganisationParent - Organisation
+var ErrorInvalidOrganisationParent = &Data{
+	Code:   ErrCodeInvalidOrganisationParent,
+	Title:  ErrValidation,
+	Detail: "Field parent value must be an existing organisation that has not been terminated and is not the organisation or one of its descendants",
+}
+
+// ErrorInvalidOrganisationStatus - Organisation
+var ErrorInvalidOrganisationStatus = &Data{
+	Code:   ErrCodeInvalidOrganisationStatus,
+	Title:  ErrValidation,
+	Detail: "Field status must be one of active, inactive or terminated",
+}
+
+// ErrTerminatedOrganisationCannotBeModified - Organisation
+var ErrTerminatedOrganisationCannotBeModified = &Data{
+	Code:   ErrCodeTerminatedOrganisationCannotBeModified,
+	Title:  ErrValidation,
+	Detail: "Terminated organisations cannot be modified",
+}
+
+// ErrOrganisationHasChildren - Organisation
+var ErrOrganisationHasChildren = &Data{
+	Code:   ErrCodeOrganisationHasChildren,
+	Title:  ErrValidation,
+	Detail: "Organisations with organisations or merchants beneath them cannot be deleted",
+}
+
+// ErrorInvalidLocationStatus - Location
+var ErrorInvalidLocationStatus = &Data{
+	Code:   ErrCodeInvalidLocationStatus,
+	Title:  ErrValidation,
+	Detail: "Field status must be one of active, inactive or terminated",
+}
+
+// ErrTerminatedLocationCannotBeModified - Location
+var ErrTerminatedLocationCannotBeModified = &Data{
+	Code:   ErrCodeTerminatedLocationCannotBeModified,
+	Title:  ErrValidation,
+	Detail: "Terminated locations cannot be modified",
+}
+
+// ErrorInvalidCurrency - Bank account
+var ErrorInvalidCurrency = &Data{
+	Code:   ErrCodeInvalidCurrency,
+	Title:  ErrValidation,
+	Detail: "Currency must be an active three letter ISO 4217 currency code",
+}
+
+// ErrorInvalidIBAN - Bank account
+var ErrorInvalidIBAN = &Data{
+	Code:   ErrCodeInvalidIBAN,
+	Title:  ErrValidation,
+	Detail: "Field account_number must be a valid IBAN for the country",
+}
+
+// ErrorInvalidRoutingNumber - Bank account
+var ErrorInvalidRoutingNumber = &Data{
+	Code:   ErrCodeInvalidRoutingNumber,
+	Title:  ErrValidation,
+	Detail: "Field bank_code must be a valid 9 digit ABA routing number",
+}
+
+// ErrorInvalidBSB - Bank account
+var ErrorInvalidBSB = &Data{
+	Code:   ErrCodeInvalidBSB,
+	Title:  ErrValidation,
+	Detail: "Field bank_code must be a valid BSB number, i.e. 062-000",
+}
+
+// ErrorInvalidAccountNumber - Bank account
+var ErrorInvalidAccountNumber = &Data{
+	Code:   ErrCodeInvalidAccountNumber,
+	Title:  ErrValidation,
+	Detail: "Field account_number is not a valid account number for the country",
+}
+
+// ErrorDuplicateFeeSchedule - Fee schedule
+var ErrorDuplicateFeeSchedule = &Data{
+	Code:   ErrCodeDuplicateFeeSchedule,
+	Title:  ErrValidation,
+	Detail: "Merchant already has a fee schedule for the currency",
+}
+
+// ErrorInvalidFeeTiers - Fee schedule
+var ErrorInvalidFeeTiers = &Data{
+	Code:   ErrCodeInvalidFeeTiers,
+	Title:  ErrValidation,
+	Detail: "Field tiers must start with a min_amount of 0 and not repeat a min_amount",
+}
+
+// ErrorInvalidAmountPrecision - Fee schedule
+var ErrorInvalidAmountPrecision = &Data{
+	Code:   ErrCodeInvalidAmountPrecision,
+	Title:  ErrValidation,
+	Detail: "Amount has more decimal places than the currency allows",
+}
+
+// ErrorInvalidPercentage - Fee schedule
+var ErrorInvalidPercentage = &Data{
+	Code:   ErrCodeInvalidPercentage,
+	Title:  ErrValidation,
+	Detail: "Field percentage must be a decimal string from 0 to 100 with up to 4 decimal places",
+}
+
+// ErrorInvalidAmount - Fee schedule
+var ErrorInvalidAmount = &Data{
+	Code:   ErrCodeInvalidAmount,
+	Title:  ErrValidation,
+	Detail: "Amounts must be positive decimal strings, i.e. \"10.00\"",
+}
+
+// ErrorInvalidBusinessHours - Business hours
+var ErrorInvalidBusinessHours = &Data{
+	Code:   ErrCodeInvalidBusinessHours,
+	Title:  ErrValidation,
+	Detail: "Field hours must have a day from monday to sunday and different opens and closes times from 00:00 to 24:00",
+}
+
+// ErrorInvalidHoliday - Business hours
+var ErrorInvalidHoliday = &Data{
+	Code:   ErrCodeInvalidHoliday,
+	Title:  ErrValidation,
+	Detail: "Field holidays must have a date, i.e. 2006-01-02, and either closed or different opens and closes times",
+}
+
+// ErrorUnknownTimezone - Business hours
+var ErrorUnknownTimezone = &Data{
+	Code:   ErrCodeUnknownTimezone,
+	Title:  ErrValidation,
+	Detail: "Merchant timezone is not in the tz database, update the merchant to an available timezone",
+}
+
+// ErrorInvalidLogLevel - Admin
+var ErrorInvalidLogLevel = &Data{
+	Code:   ErrCodeInvalidLogLevel,
+	Title:  ErrValidation,
+	Detail: "Field level and each of packages must be debug, info, warn or error",
+}
+
 // ErrorMap for looking error codes
 var ErrorMap = map[int]*Data{}
@@ -0,0 +1,72 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/jobs"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// TypePurgeMerchants - job type that hard deletes expired soft deleted merchants
+const TypePurgeMerchants = "retention.purge_merchants"
+
+// MerchantRetention returns how long soft deleted merchants are kept
+// before being purged, as configured by APP_MERCHANT_RETENTION_DAYS.
+// Zero means soft deleted merchants are kept forever.
+func MerchantRetention(e *env.Env) time.Duration {
+	return time.Duration(e.Config.Retention.MerchantDays) * 24 * time.Hour
+}
+
+// PurgeMerchantsHandler returns a handler that hard deletes merchants
+// soft deleted longer ago than the retention period
+func PurgeMerchantsHandler(e *env.Env, l zerolog.Logger, retention time.Duration) jobs.HandlerFunc {
+	return func(ctx context.Context, tx *sqlx.Tx, rec *job.Record) error {
+
+		m, err := merchant.NewModel(e, l, tx)
+		if err != nil {
+			return err
+		}
+
+		ids, err := m.GetDeletedBefore(util.GetFutureTime(-retention))
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			recs, err := m.GetByParamIncludingDeleted(map[string]interface{}{"id": id})
+			if err != nil {
+				return err
+			}
+			if len(recs) != 1 {
+				continue
+			}
+
+			// events and audit records belong to the merchant's tenant
+			err = db.SetTenant(tx, recs[0].TenantID)
+			if err != nil {
+				return err
+			}
+
+			err = m.Remove(id)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = db.SetTenant(tx, db.AllTenants)
+		if err != nil {
+			return err
+		}
+
+		l.Info().Msgf("Purged %d merchants deleted more than %s ago", len(ids), retention)
+
+		return nil
+	}
+}
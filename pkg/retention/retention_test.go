@@ -0,0 +1,18 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/config"
+	"github.com/vegh1010/test/pkg/env"
+)
+
+func TestMerchantRetention(t *testing.T) {
+	e := &env.Env{Config: &config.Config{}}
+	assert.Equal(t, time.Duration(0), MerchantRetention(e))
+
+	e.Config.Retention.MerchantDays = 30
+	assert.Equal(t, 30*24*time.Hour, MerchantRetention(e))
+}
@@ -0,0 +1,221 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"gopkg.in/olivere/elastic.v6"
+)
+
+// Elasticsearch defaults
+const (
+	DefaultElasticIndex = "merchants"
+	elasticDocType      = "_doc"
+)
+
+// elasticMapping - merchant index mapping, names are analysed text with a
+// keyword sub field for exact matches
+const elasticMapping = `{
+	"mappings": {
+		"_doc": {
+			"properties": {
+				"id":         {"type": "keyword"},
+				"tenant_id":  {"type": "keyword"},
+				"name":       {"type": "text", "fields": {"keyword": {"type": "keyword"}}},
+				"short_name": {"type": "text", "fields": {"keyword": {"type": "keyword"}}},
+				"dba_name":   {"type": "text", "fields": {"keyword": {"type": "keyword"}}},
+				"status":     {"type": "keyword"}
+			}
+		}
+	}
+}`
+
+// clients are shared as they hold connection pools
+var (
+	elasticClients   = map[string]*elastic.Client{}
+	elasticClientsMu sync.Mutex
+)
+
+// Document - a merchant as indexed in Elasticsearch
+type Document struct {
+	ID        string `json:"id"`
+	TenantID  string `json:"tenant_id"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	DBAName   string `json:"dba_name"`
+	Status    string `json:"status"`
+}
+
+// ElasticSearcher searches and maintains a merchant Elasticsearch index
+type ElasticSearcher struct {
+	Logger zerolog.Logger
+	Client *elastic.Client
+	Index  string
+}
+
+// NewElasticSearcher returns a searcher for the cluster at APP_ELASTICSEARCH_URL
+func NewElasticSearcher(e *env.Env, l zerolog.Logger) (*ElasticSearcher, error) {
+
+	c := e.Config.Search
+
+	// checked when the configuration is loaded
+	if c.ElasticsearchURL == "" {
+		return nil, fmt.Errorf("APP_ELASTICSEARCH_URL is required for the %s search backend", BackendElasticsearch)
+	}
+
+	index := c.ElasticsearchIndex
+	if index == "" {
+		index = DefaultElasticIndex
+	}
+
+	client, err := elasticClient(c.ElasticsearchURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s := ElasticSearcher{
+		Logger: l,
+		Client: client,
+		Index:  index,
+	}
+
+	return &s, nil
+}
+
+func elasticClient(url string) (*elastic.Client, error) {
+
+	elasticClientsMu.Lock()
+	defer elasticClientsMu.Unlock()
+
+	if c, ok := elasticClients[url]; ok {
+		return c, nil
+	}
+
+	c, err := elastic.NewClient(elastic.SetURL(url), elastic.SetSniff(false))
+	if err != nil {
+		return nil, err
+	}
+
+	elasticClients[url] = c
+
+	return c, nil
+}
+
+// EnsureIndex creates the merchant index when it does not exist
+func (s *ElasticSearcher) EnsureIndex(ctx context.Context) error {
+
+	exists, err := s.Client.IndexExists(s.Index).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	s.Logger.Info().Msgf("Creating Elasticsearch index %s", s.Index)
+
+	_, err = s.Client.CreateIndex(s.Index).BodyString(elasticMapping).Do(ctx)
+
+	return err
+}
+
+// SearchMerchants matches words and phrase prefixes across names, with
+// fuzzy matching for misspellings. Names are weighted above DBA names,
+// and DBA names above short names. Results are limited to the query's
+// tenant when set.
+func (s *ElasticSearcher) SearchMerchants(ctx context.Context, q *Query) ([]*Result, error) {
+
+	text := strings.TrimSpace(q.Text)
+	fields := []string{"name^3", "dba_name^2", "short_name"}
+
+	query := elastic.NewBoolQuery().Should(
+		elastic.NewMultiMatchQuery(text, fields...).Fuzziness("AUTO"),
+		elastic.NewMultiMatchQuery(text, fields...).Type("phrase_prefix"),
+	).MinimumNumberShouldMatch(1)
+
+	if q.TenantID != "" {
+		query = query.Filter(elastic.NewTermQuery("tenant_id", q.TenantID))
+	}
+
+	highlight := elastic.NewHighlight().
+		Fields(
+			elastic.NewHighlighterField("name"),
+			elastic.NewHighlighterField("short_name"),
+			elastic.NewHighlighterField("dba_name"),
+		).
+		PreTags(HighlightStart).
+		PostTags(HighlightEnd)
+
+	sr, err := s.Client.Search(s.Index).
+		Query(query).
+		Highlight(highlight).
+		Size(q.limit()).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*Result{}
+	if sr.Hits == nil {
+		return res, nil
+	}
+
+	for _, hit := range sr.Hits.Hits {
+		r := &Result{
+			ID:         hit.Id,
+			Highlights: map[string]string{},
+		}
+		if hit.Score != nil {
+			r.Score = *hit.Score
+		}
+		for field, fragments := range hit.Highlight {
+			r.Highlights[field] = strings.Join(fragments, " ")
+		}
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// IndexMerchant adds or replaces a merchant's document
+func (s *ElasticSearcher) IndexMerchant(ctx context.Context, rec *merchant.Record) error {
+
+	doc := Document{
+		ID:        rec.ID,
+		TenantID:  rec.TenantID,
+		Name:      rec.Name,
+		ShortName: rec.ShortName,
+		DBAName:   rec.DBAName,
+		Status:    rec.Status,
+	}
+
+	_, err := s.Client.Index().
+		Index(s.Index).
+		Type(elasticDocType).
+		Id(rec.ID).
+		BodyJson(&doc).
+		Do(ctx)
+
+	return err
+}
+
+// DeleteMerchant removes a merchant's document, a missing document is not
+// an error
+func (s *ElasticSearcher) DeleteMerchant(ctx context.Context, id string) error {
+
+	_, err := s.Client.Delete().
+		Index(s.Index).
+		Type(elasticDocType).
+		Id(id).
+		Do(ctx)
+	if err != nil && !elastic.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/jobs"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/model/merchant"
+	"github.com/vegh1010/test/pkg/model/outboxevent"
+)
+
+// TypeIndexMerchant - job type that syncs a merchant to Elasticsearch
+const TypeIndexMerchant = "search.index_merchant"
+
+// IndexMerchantPayload -
+type IndexMerchantPayload struct {
+	ID string `json:"id"`
+}
+
+// EnqueueIndexMerchant returns an outbox subscriber that enqueues an index
+// job for every merchant event. Jobs index the merchant as it is when the
+// job runs, so they may run in any order.
+func EnqueueIndexMerchant(e *env.Env, l zerolog.Logger) func(tx *sqlx.Tx, ev *outboxevent.Record) error {
+	return func(tx *sqlx.Tx, ev *outboxevent.Record) error {
+
+		if ev.AggregateType != merchant.AggregateType {
+			return nil
+		}
+
+		_, err := jobs.Enqueue(e, l, tx, TypeIndexMerchant, &IndexMerchantPayload{ID: ev.AggregateID}, nil)
+
+		return err
+	}
+}
+
+// IndexMerchantHandler returns a handler that indexes a merchant, or
+// removes it from the index once deleted
+func IndexMerchantHandler(e *env.Env, l zerolog.Logger, s *ElasticSearcher) jobs.HandlerFunc {
+	return func(ctx context.Context, tx *sqlx.Tx, rec *job.Record) error {
+
+		p := IndexMerchantPayload{}
+		err := jobs.DecodePayload(rec, &p)
+		if err != nil {
+			return err
+		}
+
+		m, err := merchant.NewModel(e, l, tx)
+		if err != nil {
+			return err
+		}
+
+		recs, err := m.GetByParam(map[string]interface{}{"id": p.ID})
+		if err != nil {
+			return err
+		}
+
+		if len(recs) != 1 {
+			return s.DeleteMerchant(ctx, p.ID)
+		}
+
+		return s.IndexMerchant(ctx, recs[0])
+	}
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/model/merchant"
+)
+
+// PostgresSearcher searches merchants using Postgres full text search
+// ranked together with pg_trgm similarity
+type PostgresSearcher struct {
+	Env    *env.Env
+	Logger zerolog.Logger
+	Tx     *sqlx.Tx
+}
+
+// NewPostgresSearcher -
+func NewPostgresSearcher(e *env.Env, l zerolog.Logger, tx *sqlx.Tx) *PostgresSearcher {
+	return &PostgresSearcher{
+		Env:    e,
+		Logger: l,
+		Tx:     tx,
+	}
+}
+
+// SearchMerchants -
+func (s *PostgresSearcher) SearchMerchants(ctx context.Context, q *Query) ([]*Result, error) {
+
+	m, err := merchant.NewModel(s.Env, s.Logger, s.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	recs, err := m.Search(PrefixQuery(q.Text), strings.TrimSpace(q.Text), q.limit())
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*Result{}
+	for _, rec := range recs {
+		res = append(res, &Result{
+			ID:    rec.ID,
+			Score: rec.Score,
+			Highlights: highlights(map[string]string{
+				"name":       rec.NameHighlight,
+				"short_name": rec.ShortNameHighlight,
+				"dba_name":   rec.DBANameHighlight,
+			}),
+		})
+	}
+
+	return res, nil
+}
+
+// highlights drops fields without a highlighted term, ts_headline returns
+// every field whether it matched or not
+func highlights(fields map[string]string) map[string]string {
+
+	h := map[string]string{}
+	for k, v := range fields {
+		if strings.Contains(v, HighlightStart) {
+			h[k] = v
+		}
+	}
+
+	return h
+}
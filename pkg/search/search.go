@@ -0,0 +1,110 @@
+// Package search finds merchants by name, short name or DBA name.
+//
+// Searches go through the Searcher interface. The default Postgres searcher
+// combines full text search with pg_trgm word similarity so partial and
+// misspelt names still match, and needs nothing beyond the database.
+// Setting APP_SEARCH_BACKEND=elasticsearch searches an Elasticsearch index
+// instead, which is kept in sync from merchant outbox events.
+package search
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/logger"
+)
+
+// Search backends
+const (
+	BackendPostgres      = "postgres"
+	BackendElasticsearch = "elasticsearch"
+)
+
+// Result limits
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Highlight tags wrapped around matching terms
+const (
+	HighlightStart = "<em>"
+	HighlightEnd   = "</em>"
+)
+
+// Query -
+type Query struct {
+	Text  string
+	Limit int
+	// TenantID - only the tenant's merchants match. The Postgres searcher
+	// is scoped by row level security and does not need it.
+	TenantID string
+}
+
+// Result - a matching merchant, best matches have the highest score
+type Result struct {
+	ID    string
+	Score float64
+	// Highlights - matching field values with matching terms highlighted,
+	// keyed by field name
+	Highlights map[string]string
+}
+
+// Searcher -
+type Searcher interface {
+	SearchMerchants(ctx context.Context, q *Query) ([]*Result, error)
+}
+
+// Backend returns the configured search backend
+func Backend(e *env.Env) string {
+
+	if e.Config.Search.Backend == BackendElasticsearch {
+		return BackendElasticsearch
+	}
+
+	return BackendPostgres
+}
+
+// NewSearcher returns the configured searcher. The Postgres searcher runs
+// its queries within tx.
+func NewSearcher(e *env.Env, l zerolog.Logger, tx *sqlx.Tx) (Searcher, error) {
+
+	l = logger.Package(l, "search")
+
+	if Backend(e) == BackendElasticsearch {
+		return NewElasticSearcher(e, l)
+	}
+
+	return NewPostgresSearcher(e, l, tx), nil
+}
+
+// limit returns the query limit within bounds
+func (q *Query) limit() int {
+	if q.Limit <= 0 {
+		return DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		return MaxLimit
+	}
+	return q.Limit
+}
+
+// PrefixQuery builds a tsquery matching every word of text as a prefix,
+// so "acme sto" matches "Acme Stores". Punctuation is dropped so user input
+// can not inject tsquery operators.
+func PrefixQuery(text string) string {
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
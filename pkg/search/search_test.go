@@ -0,0 +1,29 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixQuery(t *testing.T) {
+	assert.Equal(t, "acme:* & sto:*", PrefixQuery("Acme Sto"))
+	assert.Equal(t, "o:* & brien:*", PrefixQuery("  O'Brien "))
+	assert.Equal(t, "café:*", PrefixQuery("Café & | !"))
+	assert.Equal(t, "", PrefixQuery("&|!:*"))
+}
+
+func TestQueryLimit(t *testing.T) {
+	assert.Equal(t, DefaultLimit, (&Query{}).limit())
+	assert.Equal(t, 5, (&Query{Limit: 5}).limit())
+	assert.Equal(t, MaxLimit, (&Query{Limit: MaxLimit + 1}).limit())
+}
+
+func TestHighlights(t *testing.T) {
+	h := highlights(map[string]string{
+		"name":       "<em>Acme</em> Stores",
+		"short_name": "ACME",
+		"dba_name":   "",
+	})
+	assert.Equal(t, map[string]string{"name": "<em>Acme</em> Stores"}, h)
+}
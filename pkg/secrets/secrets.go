@@ -0,0 +1,158 @@
+// Package secrets reads secret settings, such as APP_DATABASE_PASS, from
+// somewhere other than plain environment variables.
+//
+// Providers:
+//
+//	env             NAME, or the contents of the file named by NAME_FILE
+//	file            the file NAME in a directory, /run/secrets by default
+//	encrypted-file  a JSON file of NAME to values encrypted with
+//	                APP_SECRETS_KEY
+//
+// Docker and Kubernetes mount secrets as files, either as NAME_FILE
+// pointing at them or with the file provider. Secrets are read again
+// whenever the configuration is reloaded so they may rotate.
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vegh1010/test/pkg/encryption"
+)
+
+// Provider names for APP_SECRETS_PROVIDER
+const (
+	ProviderEnv           = "env"
+	ProviderFile          = "file"
+	ProviderEncryptedFile = "encrypted-file"
+)
+
+// DefaultDir - where Docker mounts secrets
+const DefaultDir = "/run/secrets"
+
+// FileSuffix - appended to a setting's name to read it from a file
+const FileSuffix = "_FILE"
+
+// Provider - a source of secrets
+type Provider interface {
+	// Secret returns the named secret, ok is false when the provider does
+	// not have it
+	Secret(name string) (value string, ok bool, err error)
+	// String describes where secrets come from
+	String() string
+}
+
+// Env - secrets from values looked up by name, or from the file named by
+// NAME_FILE
+type Env struct {
+	// Name - where values come from, such as environment or .env
+	Name   string
+	Lookup func(name string) (string, bool)
+}
+
+// Secret -
+func (p *Env) Secret(name string) (string, bool, error) {
+
+	if v, ok := p.Lookup(name); ok {
+		return v, true, nil
+	}
+
+	path, ok := p.Lookup(name + FileSuffix)
+	if !ok || path == "" {
+		return "", false, nil
+	}
+
+	v, err := readFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s%s: %v", name, FileSuffix, err)
+	}
+
+	return v, true, nil
+}
+
+// String -
+func (p *Env) String() string {
+	return p.Name
+}
+
+// Dir - secrets from files named by the secret in a directory
+type Dir struct {
+	Path string
+}
+
+// Secret -
+func (p *Dir) Secret(name string) (string, bool, error) {
+
+	v, err := readFile(filepath.Join(p.Path, name))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return v, true, nil
+}
+
+// String -
+func (p *Dir) String() string {
+	return "file " + p.Path
+}
+
+// EncryptedFile - secrets from a JSON object of names to values
+// encrypted with encryption.Encrypt
+type EncryptedFile struct {
+	Path    string
+	secrets map[string]string
+}
+
+// NewEncryptedFile reads and decrypts every secret in a file
+func NewEncryptedFile(path string, key []byte) (*EncryptedFile, error) {
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := map[string]string{}
+	err = json.Unmarshal(b, &enc)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid secrets file %s: %v", path, err)
+	}
+
+	p := EncryptedFile{Path: path, secrets: map[string]string{}}
+	for name, ct := range enc {
+		v, err := encryption.Decrypt(key, ct)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid secrets file %s: %s cannot be decrypted: %v", path, name, err)
+		}
+		p.secrets[name] = v
+	}
+
+	return &p, nil
+}
+
+// Secret -
+func (p *EncryptedFile) Secret(name string) (string, bool, error) {
+	v, ok := p.secrets[name]
+	return v, ok, nil
+}
+
+// String -
+func (p *EncryptedFile) String() string {
+	return "encrypted-file " + p.Path
+}
+
+// readFile reads a secret, without the trailing newline most editors and
+// `echo` add
+func readFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
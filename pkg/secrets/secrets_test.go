@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/encryption"
+)
+
+func tempDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestEnv(t *testing.T) {
+
+	dir := tempDir(t, map[string]string{"pass": "from-file\n"})
+	defer os.RemoveAll(dir)
+
+	vals := map[string]string{
+		"APP_DATABASE_PASS":            "from-env",
+		"APP_DATABASE_OWNER_PASS_FILE": filepath.Join(dir, "pass"),
+		"APP_ENCRYPTION_KEY_FILE":      filepath.Join(dir, "missing"),
+	}
+	p := &Env{Name: "environment", Lookup: func(name string) (string, bool) {
+		v, ok := vals[name]
+		return v, ok
+	}}
+
+	v, ok, err := p.Secret("APP_DATABASE_PASS")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "from-env", v)
+
+	v, ok, err = p.Secret("APP_DATABASE_OWNER_PASS")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "from-file", v)
+
+	_, _, err = p.Secret("APP_ENCRYPTION_KEY")
+	assert.Error(t, err)
+
+	_, ok, err = p.Secret("APP_SECRETS_KEY")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDir(t *testing.T) {
+
+	dir := tempDir(t, map[string]string{"APP_DATABASE_PASS": "s3cret\r\n"})
+	defer os.RemoveAll(dir)
+
+	p := &Dir{Path: dir}
+
+	v, ok, err := p.Secret("APP_DATABASE_PASS")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "s3cret", v)
+
+	_, ok, err = p.Secret("APP_DATABASE_OWNER_PASS")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEncryptedFile(t *testing.T) {
+
+	key, _ := encryption.Key("key", strings.Repeat("ab", encryption.KeySize))
+	ct, err := encryption.Encrypt(key, "s3cret")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dir := tempDir(t, map[string]string{
+		"secrets.json": `{"APP_DATABASE_PASS": "` + ct + `"}`,
+		"bad.json":     `{"APP_DATABASE_PASS": "not encrypted"}`,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := NewEncryptedFile(filepath.Join(dir, "secrets.json"), key)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	v, ok, err := p.Secret("APP_DATABASE_PASS")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "s3cret", v)
+
+	_, ok, _ = p.Secret("APP_DATABASE_OWNER_PASS")
+	assert.False(t, ok)
+
+	// the wrong key
+	other, _ := encryption.Key("key", strings.Repeat("cd", encryption.KeySize))
+	_, err = NewEncryptedFile(filepath.Join(dir, "secrets.json"), other)
+	assert.Error(t, err)
+
+	_, err = NewEncryptedFile(filepath.Join(dir, "bad.json"), key)
+	assert.Error(t, err)
+}
@@ -0,0 +1,311 @@
+// Package tzsync keeps the timezone table in step with the IANA tz
+// database installed on the host, /usr/share/zoneinfo, or another
+// zoneinfo directory.
+//
+// Every zone found is stored as active with its current UTC offset and
+// whether it observes daylight saving time. Deprecated aliases, such as
+// Asia/Calcutta, are stored with the canonical zone they link to, and
+// zones no longer in the tz database are deactivated.
+package tzsync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/jobs"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/model/timezone"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// TypeSync - job type that syncs the timezone table
+const TypeSync = "timezone.sync"
+
+// SystemDir - where most Unix systems install the tz database
+const SystemDir = "/usr/share/zoneinfo"
+
+// linksFile - the compact tz source distributed alongside compiled zones,
+// its L lines link deprecated names to canonical zones
+const linksFile = "tzdata.zi"
+
+// backwardFile - the tz source file of deprecated names, its Link lines
+// are read when tzdata.zi is not installed
+const backwardFile = "backward"
+
+// tzifMagic - the first bytes of every compiled zone
+var tzifMagic = []byte("TZif")
+
+// skip - compiled files that are not zones
+var skip = map[string]bool{
+	"localtime":  true,
+	"posixrules": true,
+	"Factory":    true,
+}
+
+// Zone - a time zone as found in the tz database
+type Zone struct {
+	ID string
+	// CanonicalID - the zone a deprecated alias links to, empty for
+	// canonical zones
+	CanonicalID string
+	// UTCOffset - seconds east of UTC at the time the zone was loaded
+	UTCOffset int
+	// DST - whether the zone observes daylight saving time this year
+	DST bool
+}
+
+// Source returns the zoneinfo directory zones are loaded from, the first
+// of APP_TIMEZONE_SOURCE, ZONEINFO and SystemDir that is set. Load fails
+// when it is not installed rather than falling back to another copy.
+func Source(e *env.Env) string {
+
+	if s := e.Config.Timezones.Source; s != "" {
+		return s
+	}
+
+	if s := os.Getenv("ZONEINFO"); s != "" {
+		return s
+	}
+
+	return SystemDir
+}
+
+// Load returns the zones from a zoneinfo directory, sorted by ID, with
+// offsets as at now. The directory must include tzdata.zi or backward so
+// aliases are not stored as canonical zones.
+func Load(dir string, now time.Time) ([]*Zone, error) {
+
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("No tz database in %s, install tzdata or set APP_TIMEZONE_SOURCE to a zoneinfo directory", dir)
+	}
+
+	data, err := readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	zi, ok := data[linksFile]
+	if !ok {
+		zi, ok = data[backwardFile]
+	}
+	if !ok {
+		return nil, fmt.Errorf("No %s or %s in %s, aliases cannot be told from canonical zones", linksFile, backwardFile, dir)
+	}
+	delete(data, linksFile)
+	delete(data, backwardFile)
+
+	links, err := parseLinks(bytes.NewReader(zi))
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []*Zone
+
+	for id, b := range data {
+		z, err := newZone(id, b, now)
+		if err != nil {
+			return nil, err
+		}
+		z.CanonicalID = canonical(id, links)
+		if _, ok := data[z.CanonicalID]; !ok {
+			// an alias of a zone that is not installed
+			z.CanonicalID = ""
+		}
+		zones = append(zones, z)
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].ID < zones[j].ID
+	})
+
+	return zones, nil
+}
+
+// newZone reads a compiled zone's offsets
+func newZone(id string, b []byte, now time.Time) (*Zone, error) {
+
+	loc, err := time.LoadLocationFromTZData(id, b)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid zone %s: %v", id, err)
+	}
+
+	_, offset := now.In(loc).Zone()
+
+	// offsets differ between January and July in zones observing DST,
+	// whichever hemisphere they are in
+	year := now.Year()
+	_, jan := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone()
+	_, jul := time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone()
+
+	return &Zone{
+		ID:        id,
+		UTCOffset: offset,
+		DST:       jan != jul,
+	}, nil
+}
+
+// canonical follows links to the canonical zone, empty when id is
+// canonical
+func canonical(id string, links map[string]string) string {
+
+	target := ""
+	for i := 0; i < len(links); i++ {
+		next, ok := links[id]
+		if !ok {
+			break
+		}
+		target, id = next, next
+	}
+
+	return target
+}
+
+// parseLinks reads link lines, "L Asia/Kolkata Asia/Calcutta" in
+// tzdata.zi or "Link Asia/Kolkata Asia/Calcutta" in backward, returning
+// canonical zones by alias
+func parseLinks(r io.Reader) (map[string]string, error) {
+
+	links := map[string]string{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		f := strings.Fields(line)
+		if len(f) == 3 && (f[0] == "L" || f[0] == "Link") {
+			links[f[2]] = f[1]
+		}
+	}
+
+	return links, s.Err()
+}
+
+// readDir returns compiled zones, and tzdata.zi and backward when
+// present, by name
+func readDir(dir string) (map[string][]byte, error) {
+
+	data := map[string][]byte{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+
+		if info.IsDir() {
+			// posix and right hold copies of every zone
+			if name == "posix" || name == "right" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if skip[name] || !info.Mode().IsRegular() && info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if name == linksFile || name == backwardFile || bytes.HasPrefix(b, tzifMagic) {
+			data[name] = b
+		}
+
+		return nil
+	})
+
+	return data, err
+}
+
+// Result - what a sync changed
+type Result struct {
+	Zones       int
+	Aliases     int
+	Deactivated int64
+}
+
+// Sync stores zones in the timezone table and deactivates zones that are
+// no longer present
+func Sync(e *env.Env, l zerolog.Logger, tx *sqlx.Tx, zones []*Zone) (*Result, error) {
+
+	if len(zones) == 0 {
+		// an empty or unreadable source would deactivate every zone
+		return nil, fmt.Errorf("No time zones to sync")
+	}
+
+	m, err := timezone.NewModel(e, l, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Result{Zones: len(zones)}
+
+	var recs []*timezone.Record
+	for _, z := range zones {
+		rec := m.NewRecord()
+		rec.ID = z.ID
+		rec.CanonicalID = util.ToNullString(z.CanonicalID)
+		rec.UTCOffset = util.ToNullInt64(int64(z.UTCOffset))
+		rec.DST = z.DST
+		recs = append(recs, &rec)
+
+		if z.CanonicalID != "" {
+			res.Aliases++
+		}
+	}
+
+	res.Deactivated, err = m.Sync(recs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// SyncHandler returns a handler that syncs the timezone table from source
+func SyncHandler(e *env.Env, l zerolog.Logger, source string) jobs.HandlerFunc {
+	return func(ctx context.Context, tx *sqlx.Tx, rec *job.Record) error {
+
+		zones, err := Load(source, time.Now())
+		if err != nil {
+			return err
+		}
+
+		res, err := Sync(e, l, tx, zones)
+		if err != nil {
+			return err
+		}
+
+		l.Info().Msgf("Synced %d time zones, %d aliases, from %s, deactivated %d", res.Zones, res.Aliases, source, res.Deactivated)
+
+		return nil
+	}
+}
+
+// SyncInterval returns how often the timezone.sync job runs, set by
+// APP_TIMEZONE_SYNC_INTERVAL. Zero means the job is not scheduled.
+func SyncInterval(e *env.Env) time.Duration {
+	return e.Config.Timezones.SyncInterval
+}
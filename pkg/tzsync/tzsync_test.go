@@ -0,0 +1,108 @@
+package tzsync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinks(t *testing.T) {
+	zi := `# version 2024a
+Z Asia/Kolkata 5:53:28 - LMT 1854 Jun 28
+L Asia/Kolkata Asia/Calcutta
+L Asia/Dhaka Asia/Dacca
+L Asia/Jerusalem Asia/Tel_Aviv
+L Asia/Tel_Aviv Israel
+`
+	links, err := parseLinks(strings.NewReader(zi))
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Kolkata", links["Asia/Calcutta"])
+	assert.Len(t, links, 4)
+
+	assert.Equal(t, "Asia/Kolkata", canonical("Asia/Calcutta", links))
+	assert.Equal(t, "Asia/Jerusalem", canonical("Israel", links))
+	assert.Equal(t, "", canonical("Asia/Kolkata", links))
+
+	backward := `# Link	TARGET			LINK-NAME
+Link	Asia/Kolkata		Asia/Calcutta	# India
+Link	Asia/Dhaka		Asia/Dacca
+`
+	links, err = parseLinks(strings.NewReader(backward))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Asia/Calcutta": "Asia/Kolkata", "Asia/Dacca": "Asia/Dhaka"}, links)
+
+	// cycles end rather than loop forever
+	assert.NotPanics(t, func() {
+		canonical("A", map[string]string{"A": "B", "B": "A"})
+	})
+}
+
+func TestLoadDir(t *testing.T) {
+	if _, err := os.Stat(filepath.Join(SystemDir, linksFile)); err != nil {
+		t.Skipf("No tz database with %s in %s", linksFile, SystemDir)
+	}
+
+	now := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+
+	zones, err := Load(SystemDir, now)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	byID := map[string]*Zone{}
+	for _, z := range zones {
+		byID[z.ID] = z
+	}
+
+	if assert.Contains(t, byID, "Asia/Calcutta") {
+		assert.Equal(t, "Asia/Kolkata", byID["Asia/Calcutta"].CanonicalID)
+	}
+	if assert.Contains(t, byID, "Asia/Kolkata") {
+		z := byID["Asia/Kolkata"]
+		assert.Equal(t, "", z.CanonicalID)
+		assert.Equal(t, 19800, z.UTCOffset)
+		assert.False(t, z.DST)
+	}
+	if assert.Contains(t, byID, "Australia/Sydney") {
+		z := byID["Australia/Sydney"]
+		assert.Equal(t, 39600, z.UTCOffset)
+		assert.True(t, z.DST)
+	}
+
+	assert.NotContains(t, byID, "posixrules")
+	assert.NotContains(t, byID, "posix/Asia/Kolkata")
+	assert.NotContains(t, byID, linksFile)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zoneinfo")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not a zone"), 0644)
+
+	// without tzdata.zi or backward aliases would be stored as zones
+	_, err = Load(dir, time.Now())
+	assert.Error(t, err)
+
+	ioutil.WriteFile(filepath.Join(dir, backwardFile), []byte("Link Asia/Kolkata Asia/Calcutta\n"), 0644)
+
+	zones, err := Load(dir, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, zones)
+
+	ioutil.WriteFile(filepath.Join(dir, "Broken"), []byte("TZif broken"), 0644)
+
+	_, err = Load(dir, time.Now())
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(dir, "missing"), time.Now())
+	assert.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package decimalutil
 
 import (
+	"errors"
+	"sync"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -11,6 +14,11 @@ var exp = int32(2)
 
 var mult *decimal.Decimal
 
+// exponents - ISO 4217 minor units by currency, from the currency table
+// loaded with SetExponents at startup
+var exponentsMu sync.RWMutex
+var exponents = map[string]int32{}
+
 // CentsToDecimal converts from cents to decimal
 func CentsToDecimal(c int64) decimal.Decimal {
 	return decimal.New(c, -(exp))
@@ -26,6 +34,68 @@ func DecimalToCents(d decimal.Decimal) int64 {
 	return d.Mul(*mult).RoundBank(0).IntPart()
 }
 
+// ErrUnknownCurrency - the currency's minor units were not loaded, it is
+// not in the currency table or was added since startup
+var ErrUnknownCurrency = errors.New("No minor units known for currency")
+
+// Exponent returns the number of minor unit digits for a currency,
+// i.e. 2 for AUD, 0 for JPY and 3 for KWD, and false for currencies that
+// have not been loaded
+func Exponent(currency string) (int32, bool) {
+	exponentsMu.RLock()
+	defer exponentsMu.RUnlock()
+
+	e, ok := exponents[currency]
+	return e, ok
+}
+
+// SetExponents replaces the minor units used for each currency with those
+// from the currency table, see modelinit.LoadReferenceData
+func SetExponents(exps map[string]int32) {
+	exponentsMu.Lock()
+	defer exponentsMu.Unlock()
+
+	exponents = exps
+}
+
+// MinorUnitsToDecimal converts from minor units of a currency to decimal
+func MinorUnitsToDecimal(u int64, currency string) (decimal.Decimal, error) {
+	e, ok := Exponent(currency)
+	if !ok {
+		return decimal.Decimal{}, ErrUnknownCurrency
+	}
+	return decimal.New(u, -e), nil
+}
+
+// DecimalToMinorUnits converts from decimal to minor units of a currency
+func DecimalToMinorUnits(d decimal.Decimal, currency string) (int64, error) {
+	e, ok := Exponent(currency)
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return d.Shift(e).RoundBank(0).IntPart(), nil
+}
+
+// MinorUnitsToString formats minor units of a currency as an exact
+// decimal string with the currency's number of decimal places
+func MinorUnitsToString(u int64, currency string) (string, error) {
+	e, ok := Exponent(currency)
+	if !ok {
+		return "", ErrUnknownCurrency
+	}
+	return decimal.New(u, -e).StringFixed(e), nil
+}
+
+// ValidPrecision checks a decimal has no more decimal places than the
+// currency allows, no precision is valid for an unknown currency
+func ValidPrecision(d decimal.Decimal, currency string) bool {
+	e, ok := Exponent(currency)
+	if !ok {
+		return false
+	}
+	return d.Equal(d.Truncate(e))
+}
+
 // DecimalToString -
 func DecimalToString(d decimal.Decimal) string {
 	return d.String()
@@ -1 +1,112 @@
 package decimalutil
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// minor units as loaded from the currency table
+func init() {
+	SetExponents(map[string]int32{"AUD": 2, "USD": 2, "JPY": 0, "KWD": 3})
+}
+
+func TestExponent(t *testing.T) {
+	tests := []struct {
+		currency string
+		exp      int32
+		ok       bool
+	}{
+		{"AUD", 2, true},
+		{"JPY", 0, true},
+		{"KWD", 3, true},
+		// not loaded
+		{"XXX", 0, false},
+	}
+
+	for _, tt := range tests {
+		e, ok := Exponent(tt.currency)
+		assert.Equal(t, tt.ok, ok, tt.currency)
+		assert.Equal(t, tt.exp, e, tt.currency)
+	}
+}
+
+func TestUnknownCurrency(t *testing.T) {
+	_, err := DecimalToMinorUnits(mustDecimal("1.50"), "XXX")
+	assert.Equal(t, ErrUnknownCurrency, err)
+
+	_, err = MinorUnitsToDecimal(150, "XXX")
+	assert.Equal(t, ErrUnknownCurrency, err)
+
+	_, err = MinorUnitsToString(150, "XXX")
+	assert.Equal(t, ErrUnknownCurrency, err)
+
+	assert.False(t, ValidPrecision(mustDecimal("1"), "XXX"))
+}
+
+func TestSetExponents(t *testing.T) {
+	defer SetExponents(exponents)
+
+	SetExponents(map[string]int32{"AUD": 2, "JPY": 0, "BHD": 3})
+
+	e, ok := Exponent("BHD")
+	assert.True(t, ok)
+	assert.Equal(t, int32(3), e)
+
+	_, ok = Exponent("KWD")
+	assert.False(t, ok)
+
+	str, err := MinorUnitsToString(1500, "BHD")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.500", str)
+}
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		units    int64
+		str      string
+	}{
+		{"20.20", "AUD", 2020, "20.20"},
+		{"0.29", "USD", 29, "0.29"},
+		{"1500", "JPY", 1500, "1500"},
+		{"1.234", "KWD", 1234, "1.234"},
+		{"5", "AUD", 500, "5.00"},
+	}
+
+	for _, tt := range tests {
+		d, err := DecimalFromString(tt.value)
+		assert.NoError(t, err)
+
+		u, err := DecimalToMinorUnits(d, tt.currency)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.units, u)
+
+		md, err := MinorUnitsToDecimal(tt.units, tt.currency)
+		assert.NoError(t, err)
+		assert.True(t, md.Equal(d))
+
+		str, err := MinorUnitsToString(tt.units, tt.currency)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.str, str)
+	}
+}
+
+func TestValidPrecision(t *testing.T) {
+	assert.True(t, ValidPrecision(mustDecimal("0.29"), "AUD"))
+	assert.True(t, ValidPrecision(mustDecimal("10"), "AUD"))
+	assert.False(t, ValidPrecision(mustDecimal("0.295"), "AUD"))
+	assert.False(t, ValidPrecision(mustDecimal("1.5"), "JPY"))
+	assert.True(t, ValidPrecision(mustDecimal("1.005"), "KWD"))
+}
+
+func mustDecimal(s string) decimal.Decimal {
+	d, err := DecimalFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
@@ -1,21 +1,54 @@
 package validator
 
 import (
+	"math/big"
+	"net/mail"
+	"regexp"
+	"strconv"
 	"time"
+
+	"github.com/shopspring/decimal"
 	"github.com/vegh1010/test/pkg/util"
 )
 
+// e164 - international phone number format
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// iban - country code, check digits and basic bank account number
+var iban = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+
+// currency - ISO 4217 alphabetic code
+var currency = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// aba - US routing number
+var aba = regexp.MustCompile(`^[0-9]{9}$`)
+
+// bsb - AU bank state branch number
+var bsb = regexp.MustCompile(`^[0-9]{3}-[0-9]{3}$`)
+
+// IBANLengths - IBAN length by country code for countries that use IBAN
+var IBANLengths = map[string]int{
+	"AD": 24, "AE": 23, "AT": 20, "BE": 16, "BG": 22, "BH": 22, "BR": 29,
+	"CH": 21, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "EE": 20, "ES": 24,
+	"FI": 18, "FO": 18, "FR": 27, "GB": 22, "GI": 23, "GL": 18, "GR": 27,
+	"HR": 21, "HU": 28, "IE": 22, "IL": 23, "IS": 26, "IT": 27, "JO": 30,
+	"KW": 30, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MT": 31,
+	"NL": 18, "NO": 15, "PK": 24, "PL": 28, "PT": 25, "QA": 29, "RO": 24,
+	"RS": 22, "SA": 24, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "TR": 26,
+}
+
 // ValidateUUID4 validates that a string is in a UUID format.
 func ValidateUUID4(uuid string) bool {
 	return util.ValidateUUID(uuid)
 }
 
-// ValidateDotTwoPrecision -
+// ValidateDotTwoPrecision checks a value has no more than 2 decimal places.
+//
+// The check is done on the shortest decimal representation of the float,
+// float math gets values like 0.29 wrong as 0.29 * 100 is 28.999999999999996.
 func ValidateDotTwoPrecision(total float64) bool {
-	if float64(int(total*100))/100 != total {
-		return false
-	}
-	return true
+	d := decimal.NewFromFloat(total)
+	return d.Equal(d.Truncate(2))
 }
 
 // ValidateTimestampFormat checks that a timestamp is in RFC3339 format.
@@ -25,3 +58,69 @@ func ValidateTimestampFormat(timestamp string) bool {
 	}
 	return true
 }
+
+// ValidateEmail checks that a string is a bare email address.
+func ValidateEmail(email string) bool {
+	a, err := mail.ParseAddress(email)
+	if err != nil {
+		return false
+	}
+	return a.Address == email
+}
+
+// ValidatePhone checks that a phone number is in E.164 format.
+func ValidatePhone(phone string) bool {
+	return e164.MatchString(phone)
+}
+
+// ValidateIBAN checks an IBAN without spaces, its length for the country
+// and its mod 97 checksum.
+func ValidateIBAN(s string) bool {
+	if !iban.MatchString(s) {
+		return false
+	}
+	if l, ok := IBANLengths[s[:2]]; ok && len(s) != l {
+		return false
+	}
+
+	// move the country code and check digits to the end and convert
+	// letters to numbers, A = 10 ... Z = 35
+	rearranged := s[4:] + s[:4]
+	digits := make([]byte, 0, len(rearranged)*2)
+	for _, c := range rearranged {
+		if c >= 'A' && c <= 'Z' {
+			digits = append(digits, strconv.Itoa(int(c-'A'+10))...)
+			continue
+		}
+		digits = append(digits, byte(c))
+	}
+
+	n, ok := new(big.Int).SetString(string(digits), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// ValidateABARoutingNumber checks a US routing number and its checksum.
+func ValidateABARoutingNumber(s string) bool {
+	if !aba.MatchString(s) {
+		return false
+	}
+	weights := []int{3, 7, 1}
+	sum := 0
+	for i, c := range s {
+		sum += int(c-'0') * weights[i%3]
+	}
+	return sum%10 == 0
+}
+
+// ValidateBSB checks an AU BSB number in 000-000 format.
+func ValidateBSB(s string) bool {
+	return bsb.MatchString(s)
+}
+
+// ValidateCurrencyCode checks a currency is a three letter ISO 4217 code.
+func ValidateCurrencyCode(s string) bool {
+	return currency.MatchString(s)
+}
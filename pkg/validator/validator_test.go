@@ -1 +1,58 @@
 package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEmail(t *testing.T) {
+	assert.True(t, ValidateEmail("billing@example.com"))
+	assert.False(t, ValidateEmail("Billing <billing@example.com>"))
+	assert.False(t, ValidateEmail("billing"))
+	assert.False(t, ValidateEmail(""))
+}
+
+func TestValidatePhone(t *testing.T) {
+	assert.True(t, ValidatePhone("+61291234567"))
+	assert.False(t, ValidatePhone("0291234567"))
+	assert.False(t, ValidatePhone("+61 2 9123 4567"))
+	assert.False(t, ValidatePhone("+0123"))
+}
+
+func TestValidateIBAN(t *testing.T) {
+	assert.True(t, ValidateIBAN("GB82WEST12345698765432"))
+	assert.True(t, ValidateIBAN("DE89370400440532013000"))
+	assert.False(t, ValidateIBAN("GB82WEST12345698765431"))
+	assert.False(t, ValidateIBAN("GB82WEST1234569876543"))
+	assert.False(t, ValidateIBAN("gb82west12345698765432"))
+	assert.False(t, ValidateIBAN(""))
+}
+
+func TestValidateABARoutingNumber(t *testing.T) {
+	assert.True(t, ValidateABARoutingNumber("021000021"))
+	assert.True(t, ValidateABARoutingNumber("011000015"))
+	assert.False(t, ValidateABARoutingNumber("021000022"))
+	assert.False(t, ValidateABARoutingNumber("02100002"))
+}
+
+func TestValidateBSB(t *testing.T) {
+	assert.True(t, ValidateBSB("062-000"))
+	assert.False(t, ValidateBSB("062000"))
+	assert.False(t, ValidateBSB("06-2000"))
+}
+
+func TestValidateDotTwoPrecision(t *testing.T) {
+	assert.True(t, ValidateDotTwoPrecision(0.29))
+	assert.True(t, ValidateDotTwoPrecision(1.15))
+	assert.True(t, ValidateDotTwoPrecision(99.99))
+	assert.True(t, ValidateDotTwoPrecision(10))
+	assert.False(t, ValidateDotTwoPrecision(0.295))
+	assert.False(t, ValidateDotTwoPrecision(1.001))
+}
+
+func TestValidateCurrencyCode(t *testing.T) {
+	assert.True(t, ValidateCurrencyCode("AUD"))
+	assert.False(t, ValidateCurrencyCode("aud"))
+	assert.False(t, ValidateCurrencyCode("AU"))
+}
@@ -0,0 +1,452 @@
+// Package webhooks delivers outbox events to registered webhook endpoints.
+//
+// Delivery runs as a scheduled job. Each pass fans new outbox events out
+// into a delivery row per subscribed webhook, then attempts due deliveries.
+// Deliveries are numbered per webhook as they are fanned out and attempted
+// strictly in that order, a failing delivery blocks later ones for the
+// same webhook until it succeeds or is given up on.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/vegh1010/test/pkg/db"
+	"github.com/vegh1010/test/pkg/env"
+	"github.com/vegh1010/test/pkg/jobs"
+	"github.com/vegh1010/test/pkg/logger"
+	"github.com/vegh1010/test/pkg/model/job"
+	"github.com/vegh1010/test/pkg/model/outboxevent"
+	"github.com/vegh1010/test/pkg/model/webhook"
+	"github.com/vegh1010/test/pkg/model/webhookdelivery"
+	"github.com/vegh1010/test/pkg/util"
+)
+
+// TypeDispatch - job type for a dispatch pass
+const TypeDispatch = "webhooks.dispatch"
+
+// Dispatcher defaults
+const (
+	DefaultTimeout     = 10 * time.Second
+	DefaultMaxAttempts = 15
+	fanOutBatchSize    = 100
+	maxResponseBody    = 1024
+)
+
+// Event - the JSON body posted to webhook endpoints. Sequence increases by
+// one with each delivery to the endpoint, in the order they are made.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Sequence  int64           `json:"sequence"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Subscriber is called for every outbox event as it is fanned out, within
+// the fan out tx. Returning an error rolls back the batch so the event is
+// fanned out again on the next pass.
+type Subscriber func(tx *sqlx.Tx, ev *outboxevent.Record) error
+
+// Dispatcher -
+type Dispatcher struct {
+	Env         *env.Env
+	Logger      zerolog.Logger
+	DB          *sqlx.DB
+	Client      *http.Client
+	MaxAttempts int
+	Subscribers []Subscriber
+}
+
+// NewDispatcher -
+func NewDispatcher(e *env.Env, l zerolog.Logger, db *sqlx.DB) (*Dispatcher, error) {
+	d := Dispatcher{
+		Env:         e,
+		Logger:      logger.Package(l, "webhooks"),
+		DB:          db,
+		Client:      &http.Client{Timeout: DefaultTimeout},
+		MaxAttempts: DefaultMaxAttempts,
+	}
+	err := d.init()
+	return &d, err
+}
+
+func (d *Dispatcher) init() error {
+
+	c := d.Env.Config.Webhooks
+
+	if c.Timeout > 0 {
+		d.Client.Timeout = c.Timeout
+	}
+
+	if c.MaxAttempts > 0 {
+		d.MaxAttempts = c.MaxAttempts
+	}
+
+	return nil
+}
+
+// Subscribe adds a subscriber to outbox events
+func (d *Dispatcher) Subscribe(s Subscriber) {
+	d.Subscribers = append(d.Subscribers, s)
+}
+
+// Handler returns the dispatch job handler. The job's tx stays open for
+// the pass, the dispatcher fans out and delivers in short txs of its own
+// and has none open while posting to an endpoint.
+func (d *Dispatcher) Handler() jobs.HandlerFunc {
+	return func(ctx context.Context, tx *sqlx.Tx, rec *job.Record) error {
+		return d.Dispatch(ctx)
+	}
+}
+
+// Dispatch fans out new events and attempts all due deliveries
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+
+	// log
+	log := d.Logger
+
+	err := d.FanOut()
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.begin()
+	if err != nil {
+		return err
+	}
+
+	dm, err := webhookdelivery.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return util.RollbackTxWithError(err, "Error creating delivery model", tx)
+	}
+
+	ids, err := dm.GetDueWebhookIDs()
+	if err != nil {
+		return util.RollbackTxWithError(err, "Error fetching due webhooks", tx)
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err = d.deliverWebhook(ctx, id)
+		if err != nil {
+			log.Error().Msgf("Error delivering webhook %s %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// begin starts a tx scoped to all tenants, every tenant's events are
+// dispatched
+func (d *Dispatcher) begin() (*sqlx.Tx, error) {
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.SetTenant(tx, db.AllTenants)
+	if err != nil {
+		return nil, util.RollbackTxWithError(err, "Error setting tenant", tx)
+	}
+
+	return tx, nil
+}
+
+// FanOut creates a delivery for each subscribed webhook for every
+// unprocessed outbox event. Webhooks only receive their own tenant's events.
+func (d *Dispatcher) FanOut() error {
+
+	for {
+		n, err := d.fanOutBatch()
+		if err != nil {
+			return err
+		}
+		if n < fanOutBatchSize {
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) fanOutBatch() (int, error) {
+
+	// log
+	log := d.Logger
+
+	tx, err := d.begin()
+	if err != nil {
+		return 0, err
+	}
+
+	om, err := outboxevent.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return 0, util.RollbackTxWithError(err, "Error creating outbox event model", tx)
+	}
+
+	wm, err := webhook.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return 0, util.RollbackTxWithError(err, "Error creating webhook model", tx)
+	}
+
+	dm, err := webhookdelivery.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return 0, util.RollbackTxWithError(err, "Error creating delivery model", tx)
+	}
+
+	events, err := om.LockUnprocessed(fanOutBatchSize)
+	if err != nil {
+		return 0, util.RollbackTxWithError(err, "Error locking outbox events", tx)
+	}
+
+	if len(events) == 0 {
+		return 0, tx.Rollback()
+	}
+
+	hooks, err := wm.GetByParam(map[string]interface{}{"status": webhook.StatusActive})
+	if err != nil {
+		return 0, util.RollbackTxWithError(err, "Error fetching webhooks", tx)
+	}
+
+	for _, ev := range events {
+		for _, hook := range hooks {
+			if hook.TenantID != ev.TenantID || !hook.Subscribed(ev.EventType) {
+				continue
+			}
+
+			seq, err := wm.NextDeliverySequence(hook.ID)
+			if err != nil {
+				return 0, util.RollbackTxWithError(err, "Error numbering delivery", tx)
+			}
+
+			rec := dm.NewRecord()
+			rec.Sequence = seq
+			rec.TenantID = ev.TenantID
+			rec.WebhookID = hook.ID
+			rec.OutboxEventID = ev.ID
+			rec.EventSequence = ev.Sequence
+			rec.EventType = ev.EventType
+
+			err = dm.Create(&rec)
+			if err != nil && err != sql.ErrNoRows {
+				return 0, util.RollbackTxWithError(err, "Error creating delivery", tx)
+			}
+		}
+
+		for _, sub := range d.Subscribers {
+			err = sub(tx, ev)
+			if err != nil {
+				return 0, util.RollbackTxWithError(err, "Error notifying outbox event subscriber", tx)
+			}
+		}
+
+		err = om.MarkProcessed(ev.ID)
+		if err != nil {
+			return 0, util.RollbackTxWithError(err, "Error marking outbox event processed", tx)
+		}
+	}
+
+	log.Debug().Msgf("Fanned out %d outbox events to %d webhooks", len(events), len(hooks))
+
+	return len(events), tx.Commit()
+}
+
+// deliverWebhook attempts a webhook's due deliveries in order until one
+// fails or none remain. Each delivery is claimed in a tx, posted with no tx
+// open, then its result recorded in another tx.
+func (d *Dispatcher) deliverWebhook(ctx context.Context, id string) error {
+
+	for {
+		hook, del, ev, err := d.claim(id)
+		if err != nil {
+			return err
+		}
+		if del == nil {
+			return nil
+		}
+
+		code, body, serr := d.send(ctx, hook, ev, del)
+
+		pending, err := d.record(hook, del, code, body, serr)
+		if err != nil {
+			return err
+		}
+
+		if pending {
+			// preserve ordering, later deliveries wait for this one
+			return nil
+		}
+	}
+}
+
+// claim returns a webhook's next delivery when it is due, and nil when
+// there is none. Claiming counts an attempt and moves the next attempt
+// past the time a post may take, so no other dispatcher posts to the
+// webhook until this one records the result or gives up.
+func (d *Dispatcher) claim(id string) (*webhook.Record, *webhookdelivery.Record, *outboxevent.Record, error) {
+
+	tx, err := d.begin()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	wm, err := webhook.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return nil, nil, nil, util.RollbackTxWithError(err, "Error creating webhook model", tx)
+	}
+
+	om, err := outboxevent.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return nil, nil, nil, util.RollbackTxWithError(err, "Error creating outbox event model", tx)
+	}
+
+	dm, err := webhookdelivery.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return nil, nil, nil, util.RollbackTxWithError(err, "Error creating delivery model", tx)
+	}
+
+	hook, err := wm.LockByID(id)
+	if err == sql.ErrNoRows {
+		// deleted or being claimed by another dispatcher
+		return nil, nil, nil, tx.Rollback()
+	}
+	if err != nil {
+		return nil, nil, nil, util.RollbackTxWithError(err, "Error locking webhook", tx)
+	}
+
+	if hook.Status != webhook.StatusActive {
+		// deliveries are held until the webhook is reactivated
+		return nil, nil, nil, tx.Rollback()
+	}
+
+	del, err := dm.GetNextDue(hook.ID)
+	if err == sql.ErrNoRows {
+		// none, or claimed by another dispatcher
+		return nil, nil, nil, tx.Rollback()
+	}
+	if err != nil {
+		return nil, nil, nil, util.RollbackTxWithError(err, "Error fetching next delivery", tx)
+	}
+
+	ev, err := om.GetByID(del.OutboxEventID)
+	if err != nil {
+		return nil, nil, nil, util.RollbackTxWithError(err, "Error fetching outbox event", tx)
+	}
+
+	del.Attempts++
+	del.NextAttemptAt = util.GetFutureTime(2 * d.timeout())
+
+	err = dm.Update(del)
+	if err != nil {
+		return nil, nil, nil, util.RollbackTxWithError(err, "Error claiming delivery", tx)
+	}
+
+	return hook, del, ev, tx.Commit()
+}
+
+// record saves the result of a delivery attempt, returning whether the
+// delivery is still pending
+func (d *Dispatcher) record(hook *webhook.Record, del *webhookdelivery.Record, code int, body string, serr error) (bool, error) {
+
+	// log
+	log := d.Logger
+
+	tx, err := d.begin()
+	if err != nil {
+		return false, err
+	}
+
+	dm, err := webhookdelivery.NewModel(d.Env, d.Logger, tx)
+	if err != nil {
+		return false, util.RollbackTxWithError(err, "Error creating delivery model", tx)
+	}
+
+	if code != 0 {
+		del.ResponseStatus = util.ToNullInt64(int64(code))
+		del.ResponseBody = util.ToNullString(body)
+	}
+
+	switch {
+	case serr == nil:
+		del.Status = webhookdelivery.StatusDelivered
+		del.DeliveredAt = util.ToNullString(util.GetTime())
+		del.LastError = sql.NullString{}
+	case del.Attempts >= d.MaxAttempts:
+		log.Error().Msgf("Giving up on delivery %s to webhook %s after %d attempts %v", del.ID, hook.ID, del.Attempts, serr)
+		del.Status = webhookdelivery.StatusDead
+		del.LastError = util.ToNullString(serr.Error())
+	default:
+		log.Warn().Msgf("Delivery %s to webhook %s failed %v", del.ID, hook.ID, serr)
+		del.NextAttemptAt = util.GetFutureTime(jobs.Backoff(del.Attempts))
+		del.LastError = util.ToNullString(serr.Error())
+	}
+
+	err = dm.Update(del)
+	if err != nil {
+		return false, util.RollbackTxWithError(err, "Error updating delivery", tx)
+	}
+
+	return del.Status == webhookdelivery.StatusPending, tx.Commit()
+}
+
+// timeout returns how long a post may take
+func (d *Dispatcher) timeout() time.Duration {
+	if d.Client != nil && d.Client.Timeout > 0 {
+		return d.Client.Timeout
+	}
+	return DefaultTimeout
+}
+
+// send posts an event to a webhook, returning the response status and a
+// truncated response body. Any non 2xx response is an error.
+func (d *Dispatcher) send(ctx context.Context, hook *webhook.Record, ev *outboxevent.Record, del *webhookdelivery.Record) (int, string, error) {
+
+	body, err := json.Marshal(&Event{
+		ID:        ev.ID,
+		Type:      ev.EventType,
+		Sequence:  del.Sequence,
+		CreatedAt: ev.CreatedAt,
+		Data:      json.RawMessage(ev.Payload),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(HeaderEvent, ev.EventType)
+	req.Header.Set(HeaderDelivery, del.ID)
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, time.Now(), body))
+
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Body.Close()
+
+	resBody, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, string(resBody), fmt.Errorf("Webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, string(resBody), nil
+}
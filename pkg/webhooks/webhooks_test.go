@@ -0,0 +1,75 @@
+package webhooks
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/vegh1010/test/pkg/model/outboxevent"
+	"github.com/vegh1010/test/pkg/model/webhook"
+	"github.com/vegh1010/test/pkg/model/webhookdelivery"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	sig := Sign("secret", time.Now(), body)
+
+	assert.NoError(t, Verify("secret", sig, body, time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify("other", sig, body, time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify("secret", sig, []byte(`{"id":"2"}`), time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify("secret", "garbage", body, time.Minute))
+
+	old := Sign("secret", time.Now().Add(-time.Hour), body)
+	assert.Equal(t, ErrInvalidSignature, Verify("secret", old, body, time.Minute))
+	assert.NoError(t, Verify("secret", old, body, 0))
+}
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	d := &Dispatcher{Client: srv.Client()}
+	hook := &webhook.Record{ID: "hook", URL: srv.URL, Secret: "secret"}
+	ev := &outboxevent.Record{ID: "event", EventType: "merchant.created", Sequence: 1, Payload: types.JSONText(`{"id":"m"}`)}
+
+	del := &webhookdelivery.Record{ID: "delivery", Sequence: 7}
+
+	code, body, err := d.send(context.Background(), hook, ev, del)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body)
+	assert.Equal(t, "merchant.created", got.Header.Get(HeaderEvent))
+	assert.Equal(t, "delivery", got.Header.Get(HeaderDelivery))
+	assert.NoError(t, Verify("secret", got.Header.Get(HeaderSignature), gotBody, time.Minute))
+	assert.Contains(t, string(gotBody), `"sequence":7`)
+	assert.Contains(t, string(gotBody), `"data":{"id":"m"}`)
+}
+
+func TestSendFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	d := &Dispatcher{Client: srv.Client()}
+	hook := &webhook.Record{ID: "hook", URL: srv.URL, Secret: "secret"}
+	ev := &outboxevent.Record{ID: "event", Payload: types.JSONText(`{}`)}
+
+	code, _, err := d.send(context.Background(), hook, ev, &webhookdelivery.Record{ID: "delivery"})
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}